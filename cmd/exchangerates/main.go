@@ -0,0 +1,37 @@
+package main
+
+import (
+	"log"
+	"os"
+	"stori-service/config"
+	"stori-service/src/environments/client/modules/exchangerate"
+	"stori-service/src/libs/database"
+	"stori-service/src/libs/logger"
+)
+
+/*
+Imports the exchange rates of a CSV file with the columns base, quote, date (YYYY-MM-DD) and rate:
+
+	go run cmd/exchangerates/main.go files/exchange_rates.csv
+*/
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalln("usage: exchangerates <file.csv>")
+	}
+	config.SetupCommonDependencies()
+	defer config.TearDownCommonDependencies()
+
+	file, err := os.Open(os.Args[1])
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer file.Close()
+
+	rExchangeRate := exchangerate.NewExchangeRateGormRepo(database.GetStoriGormConnection())
+	sExchangeRate := exchangerate.NewExchangeRateService(rExchangeRate)
+	imported, err := sExchangeRate.ImportRates(file)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	logger.GetInstance().Info("Exchange rates imported: ", imported)
+}
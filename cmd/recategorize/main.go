@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"os"
+	"stori-service/config"
+	"stori-service/src/environments/client/modules/categoryrule"
+	"stori-service/src/environments/client/modules/customer"
+	"stori-service/src/environments/client/modules/movement"
+	"stori-service/src/libs/database"
+	"stori-service/src/libs/logger"
+	"strconv"
+)
+
+/*
+Applies the current category rules to the movements already imported of the given customers, or of all of them
+when no customer is given. The categories that came in the files are kept:
+
+	go run cmd/recategorize/main.go [customer_id...]
+*/
+func main() {
+	customerIDs := make([]int, 0, len(os.Args)-1)
+	for _, arg := range os.Args[1:] {
+		customerID, err := strconv.Atoi(arg)
+		if err != nil {
+			log.Fatalln("usage: recategorize [customer_id...]")
+		}
+		customerIDs = append(customerIDs, customerID)
+	}
+	config.SetupCommonDependencies()
+	defer config.TearDownCommonDependencies()
+
+	connection := database.GetStoriGormConnection()
+	rCustomer := customer.NewCustomerGormRepo(connection)
+	rMovement := movement.NewMovementGormRepo(connection)
+	rCategoryRule := categoryrule.NewCategoryRuleGormRepo(connection)
+	sCategoryRule := categoryrule.NewCategoryRuleService(rCategoryRule, rCustomer, rMovement)
+	if len(customerIDs) == 0 {
+		var err error
+		customerIDs, err = rCustomer.FindIDs()
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	for _, customerID := range customerIDs {
+		updated, err := sCategoryRule.Recategorize(customerID)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		logger.GetInstance().Info("Movements recategorized of the customer ", customerID, ": ", updated)
+	}
+}
@@ -14,10 +14,13 @@ require (
 	github.com/gorilla/mux v1.8.0
 	github.com/leodido/go-urn v1.2.1 // indirect
 	github.com/liip/sheriff v0.9.0
+	github.com/minio/minio-go/v7 v7.0.21
 	github.com/nicksnyder/go-i18n/v2 v2.1.2
+	github.com/pkg/sftp v1.13.4
 	github.com/robinjoseph08/go-pg-migrations/v2 v2.1.0
-	github.com/sirupsen/logrus v1.7.0
+	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.2
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
 	golang.org/x/text v0.3.7
 	gopkg.in/alexcesaro/quotedprintable.v3 v3.0.0-20150716171945-2caba252f4dc // indirect
 	gopkg.in/go-extras/elogrus.v7 v7.2.0
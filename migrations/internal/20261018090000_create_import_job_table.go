@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		CREATE TABLE import_job (
+			import_job_id serial PRIMARY KEY,
+			customer_id int NOT NULL,
+			status varchar(20) NOT NULL DEFAULT 'pending',
+			total_rows int NOT NULL DEFAULT 0,
+			imported_rows int NOT NULL DEFAULT 0,
+			error text,
+			started_at timestamp with time zone,
+			finished_at timestamp with time zone,
+			created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			updated_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			deleted_at timestamp with time zone
+		)
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		DROP TABLE import_job
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018090000_create_import_job_table", up, down, opts)
+}
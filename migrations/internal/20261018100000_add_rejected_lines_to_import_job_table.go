@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE import_job
+			ADD COLUMN rejected_rows int NOT NULL DEFAULT 0,
+			ADD COLUMN rejected_lines jsonb
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE import_job
+			DROP COLUMN rejected_rows,
+			DROP COLUMN rejected_lines
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018100000_add_rejected_lines_to_import_job_table", up, down, opts)
+}
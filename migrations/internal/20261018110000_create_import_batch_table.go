@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		CREATE TABLE import_batch (
+			import_batch_id serial PRIMARY KEY,
+			customer_id int NOT NULL,
+			file_hash char(64) NOT NULL,
+			total_rows int NOT NULL DEFAULT 0,
+			imported_rows int NOT NULL DEFAULT 0,
+			rejected_rows int NOT NULL DEFAULT 0,
+			total_income float NOT NULL DEFAULT 0,
+			total_outcome float NOT NULL DEFAULT 0,
+			initial_available float NOT NULL DEFAULT 0,
+			final_available float NOT NULL DEFAULT 0,
+			started_at timestamp with time zone NOT NULL,
+			finished_at timestamp with time zone,
+			created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			updated_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			deleted_at timestamp with time zone
+		);
+		CREATE UNIQUE INDEX import_batch_customer_id_file_hash_idx ON import_batch (customer_id, file_hash)
+			WHERE deleted_at IS NULL;
+		ALTER TABLE movement
+			ADD COLUMN import_batch_id int REFERENCES import_batch (import_batch_id);
+		CREATE INDEX movement_import_batch_id_idx ON movement (import_batch_id);
+		ALTER TABLE import_job
+			ADD COLUMN import_batch_id int REFERENCES import_batch (import_batch_id)
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE import_job
+			DROP COLUMN import_batch_id;
+		ALTER TABLE movement
+			DROP COLUMN import_batch_id;
+		DROP TABLE import_batch
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018110000_create_import_batch_table", up, down, opts)
+}
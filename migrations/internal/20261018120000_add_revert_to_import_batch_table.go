@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE import_batch
+			ADD COLUMN reverted_at timestamp with time zone,
+			ADD COLUMN reverted_by varchar(255),
+			ADD COLUMN revert_reason text;
+		DROP INDEX import_batch_customer_id_file_hash_idx;
+		CREATE UNIQUE INDEX import_batch_customer_id_file_hash_idx ON import_batch (customer_id, file_hash)
+			WHERE deleted_at IS NULL AND reverted_at IS NULL
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		DROP INDEX import_batch_customer_id_file_hash_idx;
+		CREATE UNIQUE INDEX import_batch_customer_id_file_hash_idx ON import_batch (customer_id, file_hash)
+			WHERE deleted_at IS NULL;
+		ALTER TABLE import_batch
+			DROP COLUMN reverted_at,
+			DROP COLUMN reverted_by,
+			DROP COLUMN revert_reason
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018120000_add_revert_to_import_batch_table", up, down, opts)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// the IDs of the bank files become the external_id of the default source, the movement_id keeps its value
+	// as the surrogate key and new movements take it from the sequence
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement
+			ADD COLUMN external_id bigint,
+			ADD COLUMN source varchar(50);
+		UPDATE movement SET external_id = movement_id, source = 'default';
+		ALTER TABLE movement
+			ALTER COLUMN external_id SET NOT NULL,
+			ALTER COLUMN source SET NOT NULL,
+			ALTER COLUMN movement_id TYPE bigint;
+		CREATE SEQUENCE movement_movement_id_seq OWNED BY movement.movement_id;
+		SELECT setval('movement_movement_id_seq', COALESCE(MAX(movement_id), 0) + 1, false) FROM movement;
+		ALTER TABLE movement
+			ALTER COLUMN movement_id SET DEFAULT nextval('movement_movement_id_seq');
+		CREATE UNIQUE INDEX movement_customer_id_source_external_id_idx ON movement (customer_id, source, external_id)
+			WHERE deleted_at IS NULL
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		DROP INDEX movement_customer_id_source_external_id_idx;
+		ALTER TABLE movement
+			ALTER COLUMN movement_id DROP DEFAULT;
+		DROP SEQUENCE movement_movement_id_seq;
+		ALTER TABLE movement
+			ALTER COLUMN movement_id TYPE int,
+			DROP COLUMN external_id,
+			DROP COLUMN source
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018130000_add_external_id_to_movement_table", up, down, opts)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// amounts are exact with 2 decimals, the float values are rounded to cents as the service did
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement
+			ALTER COLUMN quantity TYPE numeric(19,2) USING ROUND(quantity::numeric, 2),
+			ALTER COLUMN available TYPE numeric(19,2) USING ROUND(available::numeric, 2);
+		ALTER TABLE import_batch
+			ALTER COLUMN total_income TYPE numeric(19,2) USING ROUND(total_income::numeric, 2),
+			ALTER COLUMN total_outcome TYPE numeric(19,2) USING ROUND(total_outcome::numeric, 2),
+			ALTER COLUMN initial_available TYPE numeric(19,2) USING ROUND(initial_available::numeric, 2),
+			ALTER COLUMN final_available TYPE numeric(19,2) USING ROUND(final_available::numeric, 2)
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement
+			ALTER COLUMN quantity TYPE float,
+			ALTER COLUMN available TYPE float;
+		ALTER TABLE import_batch
+			ALTER COLUMN total_income TYPE float,
+			ALTER COLUMN total_outcome TYPE float,
+			ALTER COLUMN initial_available TYPE float,
+			ALTER COLUMN final_available TYPE float
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018140000_use_numeric_amounts", up, down, opts)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// the existing movements are in pesos, the totals of the batches are moved to their balance in pesos
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement ADD COLUMN currency char(3);
+		UPDATE movement SET currency = 'MXN';
+		ALTER TABLE movement ALTER COLUMN currency SET NOT NULL;
+		ALTER TABLE import_batch ADD COLUMN balances jsonb;
+		UPDATE import_batch b SET balances = jsonb_build_array(jsonb_build_object(
+			'currency', 'MXN',
+			'income_rows', (SELECT COUNT(*) FROM movement m WHERE m.import_batch_id = b.import_batch_id AND m.type = 1),
+			'outcome_rows', (SELECT COUNT(*) FROM movement m WHERE m.import_batch_id = b.import_batch_id AND m.type = -1),
+			'total_income', b.total_income,
+			'total_outcome', b.total_outcome,
+			'initial_available', b.initial_available,
+			'final_available', b.final_available
+		));
+		ALTER TABLE import_batch
+			DROP COLUMN total_income,
+			DROP COLUMN total_outcome,
+			DROP COLUMN initial_available,
+			DROP COLUMN final_available
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE import_batch
+			ADD COLUMN total_income numeric(19,2) NOT NULL DEFAULT 0,
+			ADD COLUMN total_outcome numeric(19,2) NOT NULL DEFAULT 0,
+			ADD COLUMN initial_available numeric(19,2) NOT NULL DEFAULT 0,
+			ADD COLUMN final_available numeric(19,2) NOT NULL DEFAULT 0;
+		UPDATE import_batch b SET
+			total_income = (balance->>'total_income')::numeric,
+			total_outcome = (balance->>'total_outcome')::numeric,
+			initial_available = (balance->>'initial_available')::numeric,
+			final_available = (balance->>'final_available')::numeric
+		FROM jsonb_array_elements(b.balances) balance
+		WHERE balance->>'currency' = 'MXN';
+		ALTER TABLE import_batch DROP COLUMN balances;
+		ALTER TABLE movement DROP COLUMN currency
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018150000_add_currency_to_movement_table", up, down, opts)
+}
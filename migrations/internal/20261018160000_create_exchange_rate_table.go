@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// the existing movements are in pesos as the home currency of the customers, their rate is one
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		CREATE TABLE exchange_rate (
+			exchange_rate_id serial PRIMARY KEY,
+			base_currency char(3) NOT NULL,
+			quote_currency char(3) NOT NULL,
+			date timestamp with time zone NOT NULL,
+			rate numeric(19,8) NOT NULL CHECK (rate > 0),
+			created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			updated_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			UNIQUE (base_currency, quote_currency, date)
+		);
+		ALTER TABLE customer ADD COLUMN home_currency char(3) NOT NULL DEFAULT 'MXN';
+		ALTER TABLE movement
+			ADD COLUMN home_quantity numeric(19,2),
+			ADD COLUMN exchange_rate numeric(19,8);
+		UPDATE movement SET home_quantity = quantity, exchange_rate = 1;
+		ALTER TABLE movement
+			ALTER COLUMN home_quantity SET NOT NULL,
+			ALTER COLUMN exchange_rate SET NOT NULL;
+		UPDATE import_batch SET balances = (
+			SELECT jsonb_agg(CASE WHEN balance->>'currency' = 'MXN' THEN balance || jsonb_build_object(
+				'home_income', balance->'total_income',
+				'home_outcome', balance->'total_outcome',
+				'home_available', balance->'final_available'
+			) ELSE balance END)
+			FROM jsonb_array_elements(balances) balance
+		) WHERE jsonb_array_length(balances) > 0
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		UPDATE import_batch SET balances = (
+			SELECT jsonb_agg(balance - 'home_income' - 'home_outcome' - 'home_available')
+			FROM jsonb_array_elements(balances) balance
+		) WHERE jsonb_array_length(balances) > 0;
+		ALTER TABLE movement
+			DROP COLUMN home_quantity,
+			DROP COLUMN exchange_rate;
+		ALTER TABLE customer DROP COLUMN home_currency;
+		DROP TABLE exchange_rate
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018160000_create_exchange_rate_table", up, down, opts)
+}
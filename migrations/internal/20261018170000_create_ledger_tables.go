@@ -0,0 +1,102 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// every entry must balance to zero in each currency after each statement that writes its postings, the check runs
+	// once per statement for the entries it wrote. The existing movements are posted against the customer and
+	// clearing accounts of their currency
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		CREATE TABLE account (
+			account_id serial PRIMARY KEY,
+			code varchar(50) NOT NULL UNIQUE,
+			type varchar(20) NOT NULL,
+			customer_id integer,
+			currency char(3) NOT NULL,
+			created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+		);
+		CREATE TABLE journal_entry (
+			journal_entry_id serial PRIMARY KEY,
+			movement_id bigint REFERENCES movement (movement_id),
+			import_batch_id integer REFERENCES import_batch (import_batch_id),
+			reversal_of_id integer REFERENCES journal_entry (journal_entry_id),
+			date timestamp with time zone NOT NULL,
+			description varchar(255) NOT NULL DEFAULT '',
+			created_at timestamp with time zone NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX journal_entry_import_batch_id_idx ON journal_entry (import_batch_id);
+		CREATE TABLE posting (
+			posting_id serial PRIMARY KEY,
+			journal_entry_id integer NOT NULL REFERENCES journal_entry (journal_entry_id),
+			account_id integer NOT NULL REFERENCES account (account_id),
+			amount numeric(19,2) NOT NULL CHECK (amount <> 0),
+			created_at timestamp with time zone NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX posting_journal_entry_id_idx ON posting (journal_entry_id);
+		CREATE INDEX posting_account_id_idx ON posting (account_id);
+		CREATE FUNCTION check_journal_entry_balance() RETURNS trigger AS $$
+		DECLARE
+			unbalanced integer;
+		BEGIN
+			SELECT posting.journal_entry_id INTO unbalanced
+			FROM posting JOIN account USING (account_id)
+			WHERE posting.journal_entry_id IN (SELECT DISTINCT journal_entry_id FROM new_posting)
+			GROUP BY posting.journal_entry_id, account.currency
+			HAVING SUM(posting.amount) <> 0
+			LIMIT 1;
+			IF FOUND THEN
+				RAISE EXCEPTION 'journal entry % does not balance to zero', unbalanced;
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER posting_insert_balance_check
+			AFTER INSERT ON posting
+			REFERENCING NEW TABLE AS new_posting
+			FOR EACH STATEMENT EXECUTE PROCEDURE check_journal_entry_balance();
+		CREATE TRIGGER posting_update_balance_check
+			AFTER UPDATE ON posting
+			REFERENCING NEW TABLE AS new_posting
+			FOR EACH STATEMENT EXECUTE PROCEDURE check_journal_entry_balance();
+		INSERT INTO account (code, type, customer_id, currency)
+			SELECT DISTINCT 'CUSTOMER-' || customer_id || '-' || currency, 'customer', customer_id, currency
+			FROM movement WHERE deleted_at IS NULL
+			UNION
+			SELECT DISTINCT 'CLEARING-' || currency, 'clearing', NULL::integer, currency
+			FROM movement WHERE deleted_at IS NULL;
+		INSERT INTO journal_entry (movement_id, import_batch_id, date, description)
+			SELECT movement_id, import_batch_id, date, 'Movement ' || external_id || ' of ' || source
+			FROM movement WHERE deleted_at IS NULL ORDER BY movement_id;
+		INSERT INTO posting (journal_entry_id, account_id, amount)
+			SELECT journal_entry.journal_entry_id, account.account_id, -movement.quantity * movement.type
+			FROM journal_entry
+			JOIN movement USING (movement_id)
+			JOIN account ON account.code = 'CUSTOMER-' || movement.customer_id || '-' || movement.currency
+			UNION ALL
+			SELECT journal_entry.journal_entry_id, account.account_id, movement.quantity * movement.type
+			FROM journal_entry
+			JOIN movement USING (movement_id)
+			JOIN account ON account.code = 'CLEARING-' || movement.currency
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		DROP TABLE posting;
+		DROP FUNCTION check_journal_entry_balance();
+		DROP TABLE journal_entry;
+		DROP TABLE account
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018170000_create_ledger_tables", up, down, opts)
+}
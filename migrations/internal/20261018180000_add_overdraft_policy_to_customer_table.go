@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// the existing customers keep importing negative balances, they are only flagged in the import,
+	// the jobs that fail with an overdraft keep the needs-action slug of the error
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE customer
+			ADD COLUMN overdraft_policy varchar(20) NOT NULL DEFAULT 'allow_flag',
+			ADD COLUMN credit_limit numeric(19,2) NOT NULL DEFAULT 0 CHECK (credit_limit >= 0);
+		ALTER TABLE import_job ADD COLUMN action varchar(50)
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE import_job DROP COLUMN action;
+		ALTER TABLE customer
+			DROP COLUMN overdraft_policy,
+			DROP COLUMN credit_limit
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018180000_add_overdraft_policy_to_customer_table", up, down, opts)
+}
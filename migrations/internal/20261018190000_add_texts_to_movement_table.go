@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement
+			ADD COLUMN description varchar(255) NOT NULL DEFAULT '',
+			ADD COLUMN merchant varchar(100) NOT NULL DEFAULT '',
+			ADD COLUMN category varchar(50) NOT NULL DEFAULT ''
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement
+			DROP COLUMN description,
+			DROP COLUMN merchant,
+			DROP COLUMN category
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018190000_add_texts_to_movement_table", up, down, opts)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// rules are soft deleted, so the movements keep the rule that categorized them until they are recategorized
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		CREATE TABLE category_rule (
+			category_rule_id serial PRIMARY KEY,
+			customer_id int,
+			category varchar(50) NOT NULL,
+			description_pattern varchar(255) NOT NULL DEFAULT '',
+			min_amount numeric(19,2),
+			max_amount numeric(19,2),
+			type int NOT NULL DEFAULT 0,
+			priority int NOT NULL DEFAULT 0,
+			created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			updated_at timestamp with time zone NOT NULL DEFAULT NOW(),
+			deleted_at timestamp with time zone
+		);
+		CREATE INDEX category_rule_customer_id_idx ON category_rule (customer_id);
+		ALTER TABLE movement ADD COLUMN category_rule_id int REFERENCES category_rule (category_rule_id)
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement DROP COLUMN category_rule_id;
+		DROP TABLE category_rule
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018200000_create_category_rule_table", up, down, opts)
+}
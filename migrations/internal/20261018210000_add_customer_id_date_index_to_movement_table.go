@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// the balance of a customer at a date reads its last movement at or before it in each currency
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		CREATE INDEX movement_customer_id_date_idx ON movement (customer_id, date)
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		DROP INDEX movement_customer_id_date_idx
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018210000_add_customer_id_date_index_to_movement_table", up, down, opts)
+}
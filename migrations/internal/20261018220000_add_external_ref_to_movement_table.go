@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/go-pg/pg/v9/orm"
+	migrations "github.com/robinjoseph08/go-pg-migrations/v2"
+)
+
+func init() {
+	// the hash of a text reference can be the same for two references, so the duplicates are found by the reference
+	// of the bank, the movements already imported only have the external_id and it becomes their reference
+	up := func(db orm.DB) error {
+		_, err := db.Exec(`
+		ALTER TABLE movement
+			ADD COLUMN external_ref varchar(255) NOT NULL DEFAULT '';
+		UPDATE movement SET external_ref = external_id::text;
+		DROP INDEX movement_customer_id_source_external_id_idx;
+		CREATE UNIQUE INDEX movement_customer_id_source_external_ref_idx ON movement (customer_id, source, external_ref)
+			WHERE deleted_at IS NULL
+		`)
+		return err
+	}
+
+	down := func(db orm.DB) error {
+		_, err := db.Exec(`
+		DROP INDEX movement_customer_id_source_external_ref_idx;
+		CREATE UNIQUE INDEX movement_customer_id_source_external_id_idx ON movement (customer_id, source, external_id)
+			WHERE deleted_at IS NULL;
+		ALTER TABLE movement
+			DROP COLUMN external_ref
+		`)
+		return err
+	}
+
+	opts := migrations.MigrationOptions{}
+
+	migrations.Register("20261018220000_add_external_ref_to_movement_table", up, down, opts)
+}
@@ -0,0 +1,48 @@
+package balance
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/controller"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils/balancefilter"
+	"stori-service/src/utils/helpers"
+)
+
+// struct that implements IBalanceController
+type balanceController struct {
+	controller.ClientController
+	sBalance interfaces.IBalanceService
+}
+
+/*
+NewBalanceController creates a new controller, receives service by dependency injection
+and returns IBalanceController, so needs to implement all its methods
+*/
+func NewBalanceController(sBalance interfaces.IBalanceService) interfaces.IBalanceController {
+	return &balanceController{sBalance: sBalance}
+}
+
+/*
+FindAt takes the customerID and the balance filter from params and calls the service to get the balances
+of the customer as of the date, with the totals of the window when it has one
+*/
+func (c *balanceController) FindAt(response http.ResponseWriter, request *http.Request) {
+	customerID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	filter, err := balancefilter.GetBalanceFilterFromQuery(request.URL.Query())
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	balance, err := c.sBalance.FindAt(customerID, *filter)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, balance, http.StatusOK, i18n.T(i18n.Message{MessageID: "BALANCE.FOUND"}))
+}
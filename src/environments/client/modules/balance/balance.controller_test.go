@@ -0,0 +1,149 @@
+package balance
+
+import (
+	"net/http"
+	"net/url"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils"
+	"stori-service/src/utils/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+func TestBalanceController(t *testing.T) {
+	path := `/{id}/balance`
+	at := time.Date(2022, time.July, 15, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	totalIncome, totalOutcome := money.Amount(10000), money.Amount(7500)
+	t.Run("FindAt", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				name    string
+				query   url.Values
+				filter  dto.BalanceFilter
+				balance *dto.BalanceAt
+			}{
+				{
+					name:   "Finding the balance at a timestamp",
+					query:  url.Values{"at": []string{"2022-07-15T12:00:00Z"}},
+					filter: dto.BalanceFilter{At: at},
+					balance: &dto.BalanceAt{
+						CustomerID: 1,
+						At:         at,
+						Currencies: []dto.CurrencyBalanceAt{{Currency: "MXN", Available: 2500}},
+					},
+				},
+				{
+					name:   "Finding the balance with the totals of a window",
+					query:  url.Values{"at": []string{"2022-07-15T12:00:00Z"}, "from": []string{"2022-07-01"}},
+					filter: dto.BalanceFilter{At: at, From: &from},
+					balance: &dto.BalanceAt{
+						CustomerID: 1,
+						At:         at,
+						From:       &from,
+						Currencies: []dto.CurrencyBalanceAt{
+							{Currency: "MXN", Available: 2500, TotalIncome: &totalIncome, TotalOutcome: &totalOutcome},
+						},
+					},
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					// fixture
+					mockBalanceService := new(mock.ClientBalanceService)
+					balanceController := NewBalanceController(mockBalanceService)
+
+					// mock expectations
+					mockBalanceService.On("FindAt", 1, tC.filter).Return(tC.balance, nil)
+
+					//Action
+					resp := mock.MHTTPHandle(http.MethodGet, path, balanceController.FindAt, "1/balance", tC.query, nil)
+
+					//Mock Assertion
+					mockBalanceService.AssertExpectations(t)
+					mockBalanceService.AssertNumberOfCalls(t, "FindAt", 1)
+
+					result := &dto.BalanceAt{}
+					bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+					//Data Assertion
+					assert.Equal(t, http.StatusOK, resp.StatusCode)
+					assert.Equal(t, i18n.T(i18n.Message{MessageID: "BALANCE.FOUND"}), bodyResponse.Message)
+					assert.Empty(t, bodyResponse.Errors)
+					assert.Equal(t, tC.balance, result)
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			testCases := []struct {
+				name   string
+				params string
+				query  url.Values
+				err    error
+			}{
+				{
+					name:   "Invalid id",
+					params: "asd/balance",
+					err:    errors.ErrInternalServer,
+				},
+				{
+					name:   "Invalid at",
+					params: "1/balance",
+					query:  url.Values{"at": []string{"yesterday"}},
+					err:    errors.ErrFieldValidation("at", "datetime", time.RFC3339),
+				},
+				{
+					name:   "To before from",
+					params: "1/balance",
+					query:  url.Values{"from": []string{"2022-07-31"}, "to": []string{"2022-07-01"}},
+					err:    errors.ErrFieldValidation("to", "gtefield", "from"),
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					// fixture
+					mockBalanceService := new(mock.ClientBalanceService)
+					balanceController := NewBalanceController(mockBalanceService)
+
+					//Action
+					resp := mock.MHTTPHandle(http.MethodGet, path, balanceController.FindAt, tC.params, tC.query, nil)
+
+					//Mock Assertion
+					mockBalanceService.AssertNumberOfCalls(t, "FindAt", 0)
+
+					bodyResponse, _ := utils.GetBodyResponse(resp, nil)
+
+					//Data Assertion
+					assert.Equal(t, errors.GetStatusCode(tC.err), resp.StatusCode)
+					assert.Equal(t, tC.err.Error(), bodyResponse.Errors[0]["error"])
+				})
+			}
+			t.Run("Service fails finding the customer", func(t *testing.T) {
+				// fixture
+				mockBalanceService := new(mock.ClientBalanceService)
+				balanceController := NewBalanceController(mockBalanceService)
+
+				// mock expectations
+				mockBalanceService.On("FindAt", 1, testifyMock.AnythingOfType("dto.BalanceFilter")).Return(nil, errors.ErrNotFound)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, balanceController.FindAt, "1/balance", nil, nil)
+
+				//Mock Assertion
+				mockBalanceService.AssertExpectations(t)
+
+				bodyResponse, _ := utils.GetBodyResponse(resp, nil)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrNotFound.Error(), bodyResponse.Errors[0]["error"])
+			})
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package balance
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/utils/helpers"
+
+	"github.com/gorilla/mux"
+)
+
+type balanceRouter struct {
+	cBalance interfaces.IBalanceController
+}
+
+/*
+NewCustomerBalanceRouter receives the controller and assigns the routes of the balance under a customer
+*/
+func NewCustomerBalanceRouter(subRouter *mux.Router, cBalance interfaces.IBalanceController) {
+	routerBalance := balanceRouter{cBalance}
+	routerBalance.customerRoutes(subRouter)
+}
+
+/*
+customerRoutes assigns controller function for routes under a customer
+*/
+func (r *balanceRouter) customerRoutes(subRouter *mux.Router) {
+	subRouter.
+		Path(`/{id}/balance`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cBalance.FindAt),
+		)).
+		Methods(http.MethodGet)
+}
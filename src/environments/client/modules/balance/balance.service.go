@@ -0,0 +1,157 @@
+package balance
+
+import (
+	goerrors "errors"
+	"sort"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"time"
+)
+
+/*
+Struct that implements IBalanceService
+*/
+type balanceService struct {
+	rMovement interfaces.IMovementRepository
+	rCustomer interfaces.ICustomerRepository
+}
+
+/*
+	NewBalanceService creates a new service, receives repositories by dependency injection
+	and returns IBalanceService, so it needs to implement all its methods
+*/
+func NewBalanceService(rMovement interfaces.IMovementRepository, rCustomer interfaces.ICustomerRepository) interfaces.IBalanceService {
+	return &balanceService{rMovement, rCustomer}
+}
+
+/*
+Recalculate sets the available of the customer movements in the currency from the date on, as the running balance
+in order of date and ID starting from the available of the last movement before that date. It must be called after
+movements are inserted, deleted or changed with the first date they touch, so backdated and out of order movements
+leave the balances as in the timeline. It runs in the transaction received, or in a new one when it's nil
+*/
+func (s *balanceService) Recalculate(tx interface{}, customerID int, currency string, from time.Time) error {
+	rMovement := s.rMovement.Clone().(interfaces.IMovementRepository)
+	rMovement.Begin(tx)
+	if tx == nil {
+		defer rMovement.Rollback()
+	}
+
+	var opening money.Amount
+	lastMovement, err := rMovement.GetLastMovementBeforeDate(customerID, currency, from)
+	if !goerrors.Is(err, errors.ErrNotFound) {
+		if err != nil {
+			return err
+		}
+		opening = lastMovement.Available
+	}
+	err = rMovement.UpdateAvailableFromDate(customerID, currency, from, opening)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		return rMovement.Commit()
+	}
+	return nil
+}
+
+/*
+FindOverdrafts returns the first movement of the import batch in each currency that leaves the balance below zero,
+sorted by currency. It must be called after Recalculate. It runs in the transaction received, or in a new one when it's nil
+*/
+func (s *balanceService) FindOverdrafts(tx interface{}, importBatchID int) ([]entity.Movement, error) {
+	rMovement := s.rMovement.Clone().(interfaces.IMovementRepository)
+	rMovement.Begin(tx)
+	if tx == nil {
+		defer rMovement.Rollback()
+	}
+
+	return rMovement.FindFirstOverdrawn(importBatchID)
+}
+
+/*
+FindCreditLimitOverdraft returns the first movement of the import batch that leaves the balance of the customer in all
+its currencies, converted to the home currency, below the negative of its credit limit, or nil when there isn't one.
+The available of the overdraft is that balance. It must be called after Recalculate.
+It runs in the transaction received, or in a new one when it's nil
+*/
+func (s *balanceService) FindCreditLimitOverdraft(tx interface{}, customer *entity.Customer, importBatchID int) (*entity.Overdraft, error) {
+	rMovement := s.rMovement.Clone().(interfaces.IMovementRepository)
+	rMovement.Begin(tx)
+	if tx == nil {
+		defer rMovement.Rollback()
+	}
+
+	overdraft, err := rMovement.FindFirstOverCreditLimit(customer.CustomerID, importBatchID, customer.CreditLimit)
+	if goerrors.Is(err, errors.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return overdraft, nil
+}
+
+/*
+FindAt takes a customerID, check if the customer exists and returns its available in each currency as of the date
+of the filter, from the last movement at or before it. When the filter has a window the balances have the total
+incomes and outcomes in it, the currencies with movements only in the window are listed with them too
+*/
+func (s *balanceService) FindAt(customerID int, filter dto.BalanceFilter) (*dto.BalanceAt, error) {
+	_, err := s.rCustomer.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	lastMovements, err := s.rMovement.GetLastMovementsAtDate(customerID, filter.At)
+	if err != nil {
+		return nil, err
+	}
+	balance := &dto.BalanceAt{
+		CustomerID: customerID,
+		At:         filter.At,
+		From:       filter.From,
+		To:         filter.To,
+		Currencies: make([]dto.CurrencyBalanceAt, 0, len(lastMovements)),
+	}
+	for _, movement := range lastMovements {
+		balance.Currencies = append(balance.Currencies, dto.CurrencyBalanceAt{Currency: movement.Currency, Available: movement.Available})
+	}
+	if !filter.HasWindow() {
+		return balance, nil
+	}
+	totals, err := s.rMovement.GetTotalsByCustomerID(customerID, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	for index := range totals {
+		currencyBalance := findCurrency(balance, totals[index].Currency)
+		currencyBalance.TotalIncome = &totals[index].TotalIncome
+		currencyBalance.TotalOutcome = &totals[index].TotalOutcome
+	}
+	for index := range balance.Currencies {
+		if balance.Currencies[index].TotalIncome == nil { // no movements in the window
+			balance.Currencies[index].TotalIncome = new(money.Amount)
+			balance.Currencies[index].TotalOutcome = new(money.Amount)
+		}
+	}
+	sort.Slice(balance.Currencies, func(i, j int) bool {
+		return balance.Currencies[i].Currency < balance.Currencies[j].Currency
+	})
+	return balance, nil
+}
+
+/*
+findCurrency returns the balance of the currency, it's added without available when it isn't there
+*/
+func findCurrency(balance *dto.BalanceAt, currency string) *dto.CurrencyBalanceAt {
+	for index := range balance.Currencies {
+		if balance.Currencies[index].Currency == currency {
+			return &balance.Currencies[index]
+		}
+	}
+	balance.Currencies = append(balance.Currencies, dto.CurrencyBalanceAt{Currency: currency})
+	return &balance.Currencies[len(balance.Currencies)-1]
+}
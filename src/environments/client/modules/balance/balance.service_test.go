@@ -0,0 +1,388 @@
+package balance
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
+	customMocks "stori-service/src/utils/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceService(t *testing.T) {
+	from := time.Date(2022, time.June, 15, 0, 0, 0, 0, time.UTC)
+	repositoryErr := goerrors.New("repository error")
+	transaction := struct{}{}
+	t.Run("Recalculate", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Recalculating in a new transaction", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockMovementRepo.On("GetLastMovementBeforeDate", 1, "MXN", from).Return(&entity.Movement{Available: 2500}, nil)
+				mockMovementRepo.On("UpdateAvailableFromDate", 1, "MXN", from, money.Amount(2500)).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				err := sBalance.Recalculate(nil, 1, "MXN", from)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+			t.Run("Recalculating in the transaction of the caller", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", transaction).Return(transaction)
+				mockMovementRepo.On("GetLastMovementBeforeDate", 1, "MXN", from).Return(&entity.Movement{Available: 2500}, nil)
+				mockMovementRepo.On("UpdateAvailableFromDate", 1, "MXN", from, money.Amount(2500)).Return(nil)
+
+				// action
+				err := sBalance.Recalculate(transaction, 1, "MXN", from)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 0)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+			t.Run("Recalculating from the first movement", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", transaction).Return(transaction)
+				mockMovementRepo.On("GetLastMovementBeforeDate", 1, "USD", from).Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("UpdateAvailableFromDate", 1, "USD", from, money.Amount(0)).Return(nil)
+
+				// action
+				err := sBalance.Recalculate(transaction, 1, "USD", from)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository fails on", func(t *testing.T) {
+				testCases := []struct {
+					Name   string
+					Method string
+				}{
+					{Name: "Getting the opening balance", Method: "GetLastMovementBeforeDate"},
+					{Name: "Updating the balances", Method: "UpdateAvailableFromDate"},
+					{Name: "Committing", Method: "Commit"},
+				}
+				for _, testCase := range testCases {
+					t.Run(testCase.Name, func(t *testing.T) {
+						mockMovementRepo := new(customMocks.ClientMovementRepository)
+						sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+						errorOn := func(method string) error {
+							if method == testCase.Method {
+								return repositoryErr
+							}
+							return nil
+						}
+
+						// mock preparation
+						mockMovementRepo.On("Clone").Return(mockMovementRepo)
+						mockMovementRepo.On("Begin", nil).Return(nil)
+						mockMovementRepo.On("Rollback").Return(nil)
+						mockMovementRepo.On("GetLastMovementBeforeDate", 1, "MXN", from).Return(&entity.Movement{Available: 2500}, errorOn("GetLastMovementBeforeDate"))
+						mockMovementRepo.On("UpdateAvailableFromDate", 1, "MXN", from, money.Amount(2500)).Return(errorOn("UpdateAvailableFromDate"))
+						mockMovementRepo.On("Commit").Return(errorOn("Commit"))
+
+						// action
+						err := sBalance.Recalculate(nil, 1, "MXN", from)
+
+						// mock assertion
+						mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+						// assertion
+						assert.EqualError(t, err, repositoryErr.Error())
+					})
+				}
+			})
+		})
+	})
+	t.Run("FindOverdrafts", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the overdrafts of the import batch", func(t *testing.T) {
+				overdrawn := []entity.Movement{{MovementID: 7, Currency: "MXN", Available: -1500}, {MovementID: 9, Currency: "USD", Available: -10}}
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", transaction).Return(transaction)
+				mockMovementRepo.On("FindFirstOverdrawn", 3).Return(overdrawn, nil)
+
+				// action
+				movements, err := sBalance.FindOverdrafts(transaction, 3)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 0)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, overdrawn, movements)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository fails on finding the movements", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockMovementRepo.On("FindFirstOverdrawn", 3).Return(nil, repositoryErr)
+
+				// action
+				movements, err := sBalance.FindOverdrafts(nil, 3)
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, movements)
+			})
+		})
+	})
+	t.Run("FindCreditLimitOverdraft", func(t *testing.T) {
+		customer := &entity.Customer{CustomerID: 1, OverdraftPolicy: constant.OverdraftCreditLimit, CreditLimit: 50000}
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the movement over the credit limit", func(t *testing.T) {
+				overdraft := &entity.Overdraft{MovementID: 7, ExternalID: 2, Source: "default", Available: -50001}
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", transaction).Return(transaction)
+				mockMovementRepo.On("FindFirstOverCreditLimit", 1, 3, money.Amount(50000)).Return(overdraft, nil)
+
+				// action
+				got, err := sBalance.FindCreditLimitOverdraft(transaction, customer, 3)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 0)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, overdraft, got)
+			})
+			t.Run("Not finding an overdraft", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockMovementRepo.On("FindFirstOverCreditLimit", 1, 3, money.Amount(50000)).Return(nil, errors.ErrNotFound)
+
+				// action
+				got, err := sBalance.FindCreditLimitOverdraft(nil, customer, 3)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Nil(t, got)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository fails on finding the movement", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sBalance := NewBalanceService(mockMovementRepo, new(customMocks.ClientCustomerRepository))
+
+				// mock preparation
+				mockMovementRepo.On("Clone").Return(mockMovementRepo)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockMovementRepo.On("FindFirstOverCreditLimit", 1, 3, money.Amount(50000)).Return(nil, repositoryErr)
+
+				// action
+				got, err := sBalance.FindCreditLimitOverdraft(nil, customer, 3)
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, got)
+			})
+		})
+	})
+	t.Run("FindAt", func(t *testing.T) {
+		at := time.Date(2022, time.June, 30, 23, 59, 59, 999999000, time.UTC)
+		to := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+		customer := &entity.Customer{CustomerID: 1}
+		amount := func(value int) *money.Amount {
+			amount := money.Amount(value)
+			return &amount
+		}
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the balances without a window", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sBalance := NewBalanceService(mockMovementRepo, mockCustomerRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementsAtDate", 1, at).Return([]entity.Movement{
+					{Currency: "MXN", Available: 2500},
+					{Currency: "USD", Available: -300},
+				}, nil)
+
+				// action
+				balance, err := sBalance.FindAt(1, dto.BalanceFilter{At: at})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetTotalsByCustomerID", 0)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, &dto.BalanceAt{
+					CustomerID: 1,
+					At:         at,
+					Currencies: []dto.CurrencyBalanceAt{
+						{Currency: "MXN", Available: 2500},
+						{Currency: "USD", Available: -300},
+					},
+				}, balance)
+			})
+			t.Run("Finding the balances with the totals of a window", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sBalance := NewBalanceService(mockMovementRepo, mockCustomerRepo)
+				var from *time.Time
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementsAtDate", 1, at).Return([]entity.Movement{
+					{Currency: "MXN", Available: 2500},
+					{Currency: "USD", Available: -300},
+				}, nil)
+				mockMovementRepo.On("GetTotalsByCustomerID", 1, from, &to).Return([]dto.CurrencyTotals{
+					{Currency: "EUR", TotalIncome: 100, TotalOutcome: 0},
+					{Currency: "MXN", TotalIncome: 10000, TotalOutcome: 7500},
+				}, nil)
+
+				// action
+				balance, err := sBalance.FindAt(1, dto.BalanceFilter{At: at, To: &to})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, []dto.CurrencyBalanceAt{
+					{Currency: "EUR", Available: 0, TotalIncome: amount(100), TotalOutcome: amount(0)}, // only after the date
+					{Currency: "MXN", Available: 2500, TotalIncome: amount(10000), TotalOutcome: amount(7500)},
+					{Currency: "USD", Available: -300, TotalIncome: amount(0), TotalOutcome: amount(0)},
+				}, balance.Currencies)
+				assert.Equal(t, &to, balance.To)
+			})
+			t.Run("Customer without movements", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sBalance := NewBalanceService(mockMovementRepo, mockCustomerRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementsAtDate", 1, at).Return([]entity.Movement{}, nil)
+
+				// action
+				balance, err := sBalance.FindAt(1, dto.BalanceFilter{At: at})
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Empty(t, balance.Currencies)
+				assert.NotNil(t, balance.Currencies) // it's an empty list in the response
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Customer doesn't exist", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sBalance := NewBalanceService(mockMovementRepo, mockCustomerRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(nil, errors.ErrNotFound)
+
+				// action
+				balance, err := sBalance.FindAt(1, dto.BalanceFilter{At: at})
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementsAtDate", 0)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrNotFound)
+				assert.Nil(t, balance)
+			})
+			t.Run("Repository fails on finding the last movements", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sBalance := NewBalanceService(mockMovementRepo, mockCustomerRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementsAtDate", 1, at).Return(nil, repositoryErr)
+
+				// action
+				balance, err := sBalance.FindAt(1, dto.BalanceFilter{At: at})
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, balance)
+			})
+			t.Run("Repository fails on getting the totals", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sBalance := NewBalanceService(mockMovementRepo, mockCustomerRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementsAtDate", 1, at).Return([]entity.Movement{}, nil)
+				mockMovementRepo.On("GetTotalsByCustomerID", 1, &to, &to).Return(nil, repositoryErr)
+
+				// action
+				balance, err := sBalance.FindAt(1, dto.BalanceFilter{At: at, From: &to, To: &to})
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, balance)
+			})
+		})
+	})
+}
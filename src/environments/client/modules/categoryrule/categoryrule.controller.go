@@ -0,0 +1,126 @@
+package categoryrule
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/controller"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils"
+	"stori-service/src/utils/helpers"
+	"strconv"
+)
+
+// struct that implements ICategoryRuleController
+type categoryRuleController struct {
+	controller.ClientController
+	sCategoryRule interfaces.ICategoryRuleService
+}
+
+/*
+NewCategoryRuleController creates a new controller, receives service by dependency injection
+and returns ICategoryRuleController, so needs to implement all its methods
+*/
+func NewCategoryRuleController(sCategoryRule interfaces.ICategoryRuleService) interfaces.ICategoryRuleController {
+	return &categoryRuleController{sCategoryRule: sCategoryRule}
+}
+
+/*
+List returns every rule, or the rules of the customer and the global ones when the customer_id query is sent
+*/
+func (c *categoryRuleController) List(response http.ResponseWriter, request *http.Request) {
+	var customerID *int
+	if customerIDStr := request.URL.Query().Get("customer_id"); customerIDStr != "" {
+		id, err := strconv.Atoi(customerIDStr)
+		if err != nil {
+			c.MakeErrorResponse(response, errors.ErrFieldValidation("customer_id", "numeric", ""))
+			return
+		}
+		customerID = &id
+	}
+	categoryRules, err := c.sCategoryRule.Find(customerID)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, categoryRules, http.StatusOK, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.LISTED"}))
+}
+
+/*
+FindByID takes the rule ID from params and returns the rule
+*/
+func (c *categoryRuleController) FindByID(response http.ResponseWriter, request *http.Request) {
+	categoryRuleID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	categoryRule, err := c.sCategoryRule.FindByID(categoryRuleID)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, categoryRule, http.StatusOK, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.FOUND"}))
+}
+
+/*
+Create takes the rule from the body and calls the service to create it
+*/
+func (c *categoryRuleController) Create(response http.ResponseWriter, request *http.Request) {
+	var categoryRule entity.CategoryRule
+	if err := utils.GetBodyRequest(request, &categoryRule); err != nil {
+		c.MakeErrorResponse(response, errors.ErrInvalidBody)
+		return
+	}
+	created, err := c.sCategoryRule.Create(categoryRule)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, created, http.StatusCreated, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.CREATED"}))
+}
+
+/*
+Update takes the rule ID from params and its new values from the body, then calls the service to update it
+*/
+func (c *categoryRuleController) Update(response http.ResponseWriter, request *http.Request) {
+	categoryRuleID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	var categoryRule entity.CategoryRule
+	if err := utils.GetBodyRequest(request, &categoryRule); err != nil {
+		c.MakeErrorResponse(response, errors.ErrInvalidBody)
+		return
+	}
+	updated, err := c.sCategoryRule.Update(categoryRuleID, categoryRule)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, updated, http.StatusOK, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.UPDATED"}))
+}
+
+/*
+Delete takes the rule ID from params and calls the service to delete it
+*/
+func (c *categoryRuleController) Delete(response http.ResponseWriter, request *http.Request) {
+	categoryRuleID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	err = c.sCategoryRule.Delete(categoryRuleID)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, nil, http.StatusOK, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.DELETED"}))
+}
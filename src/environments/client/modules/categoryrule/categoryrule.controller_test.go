@@ -0,0 +1,316 @@
+package categoryrule
+
+import (
+	"net/http"
+	"net/url"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils"
+	"stori-service/src/utils/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryRuleController(t *testing.T) {
+	urlvalues := url.Values{}
+	path := `/{id}`
+	customerID := 1
+	categoryRule := entity.CategoryRule{CustomerID: &customerID, Category: "Groceries", DescriptionPattern: "walmart", Type: -1}
+	expectedCategoryRule := &entity.CategoryRule{CategoryRuleID: 7, CustomerID: &customerID, Category: "Groceries", DescriptionPattern: "walmart", Type: -1}
+	t.Run("List", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				Name       string
+				Query      url.Values
+				CustomerID *int
+			}{
+				{Name: "Listing every rule", Query: urlvalues, CustomerID: nil},
+				{Name: "Listing the rules of a customer", Query: url.Values{"customer_id": []string{"1"}}, CustomerID: &customerID},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					// fixture
+					mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+					categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+					// mock expectations
+					mockCategoryRuleService.On("Find", testCase.CustomerID).Return([]entity.CategoryRule{*expectedCategoryRule}, nil)
+
+					//Action
+					resp := mock.MHTTPHandle(http.MethodGet, "/", categoryRuleController.List, "", testCase.Query, nil)
+
+					//Mock Assertion
+					mockCategoryRuleService.AssertExpectations(t)
+
+					result := &[]entity.CategoryRule{}
+					bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+					//Data Assertion
+					assert.Equal(t, http.StatusOK, resp.StatusCode)
+					assert.Equal(t, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.LISTED"}), bodyResponse.Message)
+					assert.Len(t, *result, 1)
+					assert.Equal(t, "Groceries", (*result)[0].Category)
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid customer id", func(t *testing.T) {
+				// fixture
+				categoryRuleController := NewCategoryRuleController(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, "/", categoryRuleController.List, "", url.Values{"customer_id": []string{"asd"}}, nil)
+
+				bodyResponse, _ := utils.GetBodyResponse(resp, &[]entity.CategoryRule{})
+
+				//Data Assertion
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.NotEmpty(t, bodyResponse.Errors)
+			})
+		})
+	})
+	t.Run("FindByID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding a rule", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+				// mock expectations
+				mockCategoryRuleService.On("FindByID", 7).Return(expectedCategoryRule, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, categoryRuleController.FindByID, "7", urlvalues, nil)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.FOUND"}), bodyResponse.Message)
+				assert.Equal(t, 7, result.CategoryRuleID)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Rule not found", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+				// mock expectations
+				mockCategoryRuleService.On("FindByID", 7).Return(nil, errors.ErrNotFound)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, categoryRuleController.FindByID, "7", urlvalues, nil)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrNotFound.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+		})
+	})
+	t.Run("Create", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating a rule", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+				// mock expectations
+				mockCategoryRuleService.On("Create", categoryRule).Return(expectedCategoryRule, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, "/", categoryRuleController.Create, "", urlvalues, categoryRule)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.CREATED"}), bodyResponse.Message)
+				assert.Equal(t, 7, result.CategoryRuleID)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid body", func(t *testing.T) {
+				// fixture
+				categoryRuleController := NewCategoryRuleController(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, "/", categoryRuleController.Create, "", urlvalues, "rule")
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, errors.ErrInvalidBody.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Invalid rule", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+				validationErr := errors.ErrFieldValidation("DescriptionPattern", "regexp", "")
+
+				// mock expectations
+				mockCategoryRuleService.On("Create", categoryRule).Return(nil, validationErr)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, "/", categoryRuleController.Create, "", urlvalues, categoryRule)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.NotEmpty(t, bodyResponse.Errors)
+				assert.Empty(t, result)
+			})
+		})
+	})
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Updating a rule", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+				// mock expectations
+				mockCategoryRuleService.On("Update", 7, categoryRule).Return(expectedCategoryRule, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPut, path, categoryRuleController.Update, "7", urlvalues, categoryRule)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.UPDATED"}), bodyResponse.Message)
+				assert.Equal(t, 7, result.CategoryRuleID)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid id", func(t *testing.T) {
+				// fixture
+				categoryRuleController := NewCategoryRuleController(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPut, path, categoryRuleController.Update, "asd", urlvalues, categoryRule)
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Invalid body", func(t *testing.T) {
+				// fixture
+				categoryRuleController := NewCategoryRuleController(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPut, path, categoryRuleController.Update, "7", urlvalues, "rule")
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, errors.ErrInvalidBody.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Rule not found", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+				// mock expectations
+				mockCategoryRuleService.On("Update", 7, categoryRule).Return(nil, errors.ErrNotFound)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPut, path, categoryRuleController.Update, "7", urlvalues, categoryRule)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				result := &entity.CategoryRule{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrNotFound.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Deleting a rule", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+				// mock expectations
+				mockCategoryRuleService.On("Delete", 7).Return(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodDelete, path, categoryRuleController.Delete, "7", urlvalues, nil)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				bodyResponse, _ := utils.GetBodyResponse(resp, nil)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "CATEGORY_RULE.DELETED"}), bodyResponse.Message)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Rule not found", func(t *testing.T) {
+				// fixture
+				mockCategoryRuleService := new(mock.ClientCategoryRuleService)
+				categoryRuleController := NewCategoryRuleController(mockCategoryRuleService)
+
+				// mock expectations
+				mockCategoryRuleService.On("Delete", 7).Return(errors.ErrNotFound)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodDelete, path, categoryRuleController.Delete, "7", urlvalues, nil)
+
+				//Mock Assertion
+				mockCategoryRuleService.AssertExpectations(t)
+
+				bodyResponse, _ := utils.GetBodyResponse(resp, nil)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrNotFound.Error(), bodyResponse.Errors[0]["error"])
+			})
+		})
+	})
+}
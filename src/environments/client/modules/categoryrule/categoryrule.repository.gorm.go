@@ -0,0 +1,94 @@
+package categoryrule
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/client/resources/interfaces"
+	database "stori-service/src/environments/common/resources/database/transaction"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+
+	"gorm.io/gorm"
+)
+
+/*
+struct that implements ICategoryRuleRepository
+*/
+type categoryRuleGormRepo struct {
+	database.TransactionalGORMRepository
+}
+
+/*
+NewCategoryRuleGormRepo creates a new repo and returns ICategoryRuleRepository,
+so it needs to implement all its methods
+*/
+func NewCategoryRuleGormRepo(gormDb *gorm.DB) interfaces.ICategoryRuleRepository {
+	rCategoryRule := &categoryRuleGormRepo{}
+	rCategoryRule.DB = gormDb
+	return rCategoryRule
+}
+
+/*
+Create receives a category rule and creates it, the generated ID is set on the received rule
+*/
+func (r *categoryRuleGormRepo) Create(categoryRule *entity.CategoryRule) error {
+	return r.DB.Create(categoryRule).Error
+}
+
+/*
+Update receives a category rule and saves all its fields
+*/
+func (r *categoryRuleGormRepo) Update(categoryRule *entity.CategoryRule) error {
+	return r.DB.Save(categoryRule).Error
+}
+
+/*
+Delete soft deletes the category rule, the movements it categorized keep its ID until they are recategorized
+*/
+func (r *categoryRuleGormRepo) Delete(categoryRuleID int) error {
+	result := r.DB.Delete(&entity.CategoryRule{}, categoryRuleID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+/*
+FindByID returns the category rule by its ID
+*/
+func (r *categoryRuleGormRepo) FindByID(categoryRuleID int) (*entity.CategoryRule, error) {
+	var categoryRule entity.CategoryRule
+	err := r.DB.First(&categoryRule, categoryRuleID).Error
+	if goerrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &categoryRule, nil
+}
+
+/*
+Find returns the rules of the customer and the global ones, or every rule when the customer is nil
+*/
+func (r *categoryRuleGormRepo) Find(customerID *int) ([]entity.CategoryRule, error) {
+	categoryRules := []entity.CategoryRule{}
+	db := r.DB
+	if customerID != nil {
+		db = db.Where("customer_id = ? OR customer_id IS NULL", *customerID)
+	}
+	err := db.Order("category_rule_id").Find(&categoryRules).Error
+	if err != nil {
+		return nil, err
+	}
+	return categoryRules, nil
+}
+
+/*
+Clone returns a new instance of the repository
+*/
+func (r *categoryRuleGormRepo) Clone() interface{} {
+	return NewCategoryRuleGormRepo(r.DB)
+}
@@ -0,0 +1,256 @@
+package categoryrule
+
+import (
+	"os"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/database"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// setup
+	database.SetupStoriGormDB()
+	code := m.Run()
+	os.Exit(code)
+}
+
+var customerID = 1
+var otherCustomerID = 2
+var minAmount = money.Amount(10000)
+
+var categoryRules = []entity.CategoryRule{
+	{
+		CategoryRuleID:     1,
+		Category:           "Transport",
+		DescriptionPattern: "uber",
+	},
+	{
+		CategoryRuleID:     2,
+		CustomerID:         &customerID,
+		Category:           "Groceries",
+		DescriptionPattern: "walmart",
+		Type:               -1,
+	},
+	{
+		CategoryRuleID: 3,
+		CustomerID:     &otherCustomerID,
+		Category:       "Salary",
+		MinAmount:      &minAmount,
+		Type:           1,
+	},
+}
+
+/*
+Fixtures: a global rule and a rule for the customers 1 and 2
+*/
+func addFixtures(tx *gorm.DB) {
+	tx.Exec("UPDATE movement SET category_rule_id = NULL")    // releasing the rules
+	tx.Unscoped().Where("1=1").Delete(&entity.CategoryRule{}) // cleaning rules
+	tx.Create(categoryRules)
+	tx.Exec("SELECT setval('category_rule_category_rule_id_seq', MAX(category_rule_id)) FROM category_rule") // the fixtures have their ids
+}
+
+func TestCategoryRuleRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating a rule", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+				categoryRule := &entity.CategoryRule{Category: "Coffee", DescriptionPattern: "starbucks"}
+
+				err := rCategoryRule.Create(categoryRule)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 4, categoryRule.CategoryRuleID)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Updating a rule", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+				categoryRule := categoryRules[0]
+				categoryRule.Category = "Rides"
+
+				err := rCategoryRule.Update(&categoryRule)
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var got entity.CategoryRule
+				tx.First(&got, 1)
+				assert.Equal(t, "Rides", got.Category)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Deleting a rule", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+
+				err := rCategoryRule.Delete(1)
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var count int64
+				tx.Model(&entity.CategoryRule{}).Count(&count)
+				assert.Equal(t, int64(2), count)
+				tx.Unscoped().Model(&entity.CategoryRule{}).Count(&count)
+				assert.Equal(t, int64(3), count)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Rule doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+
+				err := rCategoryRule.Delete(50)
+
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindByID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the rule", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+
+				got, err := rCategoryRule.FindByID(2)
+
+				assert.NoError(t, err)
+				assert.True(t, cmp.Equal(got, &categoryRules[1], cmpopts.IgnoreTypes(time.Time{}, gorm.DeletedAt{})))
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Rule doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+
+				got, err := rCategoryRule.FindByID(50)
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Rule deleted", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				tx.Delete(&entity.CategoryRule{}, 2)
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+
+				got, err := rCategoryRule.FindByID(2)
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Find", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				Name       string
+				CustomerID *int
+				Expected   []int
+			}{
+				{Name: "Finding every rule", CustomerID: nil, Expected: []int{1, 2, 3}},
+				{Name: "Finding the rules of the customer and the global ones", CustomerID: &customerID, Expected: []int{1, 2}},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					connection := database.GetStoriGormConnection()
+					tx := connection.Begin()
+					addFixtures(tx)
+					rCategoryRule := NewCategoryRuleGormRepo(tx)
+
+					got, err := rCategoryRule.Find(testCase.CustomerID)
+
+					assert.NoError(t, err)
+					gotIDs := []int{}
+					for _, categoryRule := range got {
+						gotIDs = append(gotIDs, categoryRule.CategoryRuleID)
+					}
+					assert.Equal(t, testCase.Expected, gotIDs)
+					t.Cleanup(func() {
+						tx.Rollback()
+					})
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rCategoryRule := NewCategoryRuleGormRepo(tx)
+				tx.Exec("DROP TABLE category_rule CASCADE")
+
+				got, err := rCategoryRule.Find(nil)
+
+				assert.Nil(t, got)
+				assert.Error(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Clone", func(t *testing.T) {
+		db := database.GetStoriGormConnection()
+		rCategoryRule := NewCategoryRuleGormRepo(db)
+
+		clone := rCategoryRule.Clone()
+
+		assert.NotNil(t, clone)
+		assert.Equal(t, rCategoryRule, clone)
+	})
+}
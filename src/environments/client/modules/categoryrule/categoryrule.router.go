@@ -0,0 +1,57 @@
+package categoryrule
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/utils/helpers"
+
+	"github.com/gorilla/mux"
+)
+
+type categoryRuleRouter struct {
+	cCategoryRule interfaces.ICategoryRuleController
+}
+
+/*
+NewCategoryRuleRouter receives the controller and calls all functions for route versions
+*/
+func NewCategoryRuleRouter(subRouter *mux.Router, cCategoryRule interfaces.ICategoryRuleController) {
+	routerCategoryRule := categoryRuleRouter{cCategoryRule}
+	routerCategoryRule.routes(subRouter)
+}
+
+/*
+routes assigns controller function for routes
+*/
+func (r *categoryRuleRouter) routes(subRouter *mux.Router) {
+	subRouter.
+		Path(``).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cCategoryRule.List),
+		)).
+		Methods(http.MethodGet)
+	subRouter.
+		Path(``).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cCategoryRule.Create),
+		)).
+		Methods(http.MethodPost)
+	subRouter.
+		Path(`/{id}`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cCategoryRule.FindByID),
+		)).
+		Methods(http.MethodGet)
+	subRouter.
+		Path(`/{id}`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cCategoryRule.Update),
+		)).
+		Methods(http.MethodPut)
+	subRouter.
+		Path(`/{id}`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cCategoryRule.Delete),
+		)).
+		Methods(http.MethodDelete)
+}
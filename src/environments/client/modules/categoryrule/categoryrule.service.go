@@ -0,0 +1,183 @@
+package categoryrule
+
+import (
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/categorizer"
+	"stori-service/src/libs/env"
+)
+
+var (
+	batchSize = env.ImportBatchSize // declared here to test several batches of movements
+)
+
+/*
+Struct that implements ICategoryRuleService
+*/
+type categoryRuleService struct {
+	rCategoryRule interfaces.ICategoryRuleRepository
+	rCustomer     interfaces.ICustomerRepository
+	rMovement     interfaces.IMovementRepository
+}
+
+/*
+	NewCategoryRuleService creates a new service, receives repositories by dependency injection
+	and returns ICategoryRuleService, so it needs to implement all its methods
+*/
+func NewCategoryRuleService(rCategoryRule interfaces.ICategoryRuleRepository, rCustomer interfaces.ICustomerRepository, rMovement interfaces.IMovementRepository) interfaces.ICategoryRuleService {
+	return &categoryRuleService{rCategoryRule, rCustomer, rMovement}
+}
+
+/*
+Create validates the rule and the customer it belongs to, when it isn't global, and creates it
+*/
+func (s *categoryRuleService) Create(categoryRule entity.CategoryRule) (*entity.CategoryRule, error) {
+	categoryRule.CategoryRuleID = 0
+	if err := s.validate(&categoryRule); err != nil {
+		return nil, err
+	}
+	err := s.rCategoryRule.Create(&categoryRule)
+	if err != nil {
+		return nil, err
+	}
+	return &categoryRule, nil
+}
+
+/*
+Update replaces the conditions and the category of an existing rule, the movements it categorized
+don't change until they are recategorized
+*/
+func (s *categoryRuleService) Update(categoryRuleID int, categoryRule entity.CategoryRule) (*entity.CategoryRule, error) {
+	current, err := s.rCategoryRule.FindByID(categoryRuleID)
+	if err != nil {
+		return nil, err
+	}
+	categoryRule.CategoryRuleID = current.CategoryRuleID
+	categoryRule.CreatedAt = current.CreatedAt
+	if err := s.validate(&categoryRule); err != nil {
+		return nil, err
+	}
+	err = s.rCategoryRule.Update(&categoryRule)
+	if err != nil {
+		return nil, err
+	}
+	return &categoryRule, nil
+}
+
+/*
+Delete removes the rule, the movements it categorized don't change until they are recategorized
+*/
+func (s *categoryRuleService) Delete(categoryRuleID int) error {
+	return s.rCategoryRule.Delete(categoryRuleID)
+}
+
+/*
+FindByID returns the rule by its ID
+*/
+func (s *categoryRuleService) FindByID(categoryRuleID int) (*entity.CategoryRule, error) {
+	return s.rCategoryRule.FindByID(categoryRuleID)
+}
+
+/*
+Find returns the rules of the customer and the global ones, or every rule when the customer is nil
+*/
+func (s *categoryRuleService) Find(customerID *int) ([]entity.CategoryRule, error) {
+	return s.rCategoryRule.Find(customerID)
+}
+
+/*
+NewCategorizer returns a categorizer with the rules of the customer and the global ones.
+It runs in the transaction received, or in a new one when it's nil
+*/
+func (s *categoryRuleService) NewCategorizer(tx interface{}, customerID int) (*categorizer.Categorizer, error) {
+	rCategoryRule := s.rCategoryRule.Clone().(interfaces.ICategoryRuleRepository)
+	rCategoryRule.Begin(tx)
+	if tx == nil {
+		defer rCategoryRule.Rollback()
+	}
+	categoryRules, err := rCategoryRule.Find(&customerID)
+	if err != nil {
+		return nil, err
+	}
+	return categorizer.New(categoryRules)
+}
+
+/*
+Recategorize applies the current rules to the movements of the customer without a category from the file,
+so changes in the rules reach the movements already imported. The customer is locked, so no import runs
+at the same time. It returns the number of movements whose category changed
+*/
+func (s *categoryRuleService) Recategorize(customerID int) (int, error) {
+	rCustomer := s.rCustomer.Clone().(interfaces.ICustomerRepository)
+	rMovement := s.rMovement.Clone().(interfaces.IMovementRepository)
+	tx := rCustomer.Begin(nil)
+	rMovement.Begin(tx)
+	defer rCustomer.Rollback()
+
+	_, err := rCustomer.FindAndLockByCustomerID(customerID)
+	if err != nil {
+		return 0, err
+	}
+	rulesCategorizer, err := s.NewCategorizer(tx, customerID)
+	if err != nil {
+		return 0, err
+	}
+	updated := 0
+	afterID := 0
+	for {
+		movements, err := rMovement.FindCategorizable(customerID, afterID, batchSize)
+		if err != nil {
+			return 0, err
+		}
+		for index := range movements {
+			movement := &movements[index]
+			category := ""
+			var categoryRuleID *int
+			if categoryRule := rulesCategorizer.Match(movement); categoryRule != nil {
+				category = categoryRule.Category
+				categoryRuleID = &categoryRule.CategoryRuleID
+			}
+			if category == movement.Category && equalIDs(categoryRuleID, movement.CategoryRuleID) {
+				continue
+			}
+			err = rMovement.UpdateCategory(movement.MovementID, category, categoryRuleID)
+			if err != nil {
+				return 0, err
+			}
+			updated++
+		}
+		if len(movements) < batchSize {
+			break
+		}
+		afterID = movements[len(movements)-1].MovementID
+	}
+	err = rCustomer.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+/*
+validate returns an error if the rule isn't valid or its customer doesn't exist
+*/
+func (s *categoryRuleService) validate(categoryRule *entity.CategoryRule) error {
+	if err := categoryRule.Validate(); err != nil {
+		return err
+	}
+	if categoryRule.CustomerID == nil {
+		return nil
+	}
+	_, err := s.rCustomer.FindByCustomerID(*categoryRule.CustomerID)
+	return err
+}
+
+/*
+equalIDs returns true when both IDs are nil or have the same value
+*/
+func equalIDs(first *int, second *int) bool {
+	if first == nil || second == nil {
+		return first == second
+	}
+	return *first == *second
+}
@@ -0,0 +1,439 @@
+package categoryrule
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	customMocks "stori-service/src/utils/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryRuleService(t *testing.T) {
+	repositoryErr := goerrors.New("repository error")
+	transaction := struct{}{}
+	customerID := 1
+	groceriesID := 7
+	transportID := 8
+	newCategoryRule := func() entity.CategoryRule {
+		return entity.CategoryRule{CustomerID: &customerID, Category: "Groceries", DescriptionPattern: "walmart", Type: -1}
+	}
+	categoryRules := []entity.CategoryRule{
+		{CategoryRuleID: groceriesID, CustomerID: &customerID, Category: "Groceries", DescriptionPattern: "walmart", Type: -1},
+		{CategoryRuleID: transportID, Category: "Transport", DescriptionPattern: "uber"},
+	}
+	t.Run("Create", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating a customer rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, nil)
+				categoryRule := newCategoryRule()
+				categoryRule.CategoryRuleID = 50
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockCategoryRuleRepo.On("Create", mock.AnythingOfType("*entity.CategoryRule")).Return(nil)
+
+				// action
+				created, err := sCategoryRule.Create(categoryRule)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertExpectations(t)
+				mockCustomerRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 0, created.CategoryRuleID)
+				assert.Equal(t, "Groceries", created.Category)
+			})
+			t.Run("Creating a global rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, nil)
+				categoryRule := newCategoryRule()
+				categoryRule.CustomerID = nil
+
+				// mock preparation
+				mockCategoryRuleRepo.On("Create", mock.AnythingOfType("*entity.CategoryRule")).Return(nil)
+
+				// action
+				_, err := sCategoryRule.Create(categoryRule)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertExpectations(t)
+				mockCustomerRepo.AssertNumberOfCalls(t, "FindByCustomerID", 0)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid pattern", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+				categoryRule := newCategoryRule()
+				categoryRule.DescriptionPattern = "walmart("
+
+				// action
+				created, err := sCategoryRule.Create(categoryRule)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertNumberOfCalls(t, "Create", 0)
+
+				// assertion
+				assert.Error(t, err)
+				assert.Nil(t, created)
+			})
+			t.Run("Customer not found", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, nil)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(nil, errors.ErrNotFound)
+
+				// action
+				created, err := sCategoryRule.Create(newCategoryRule())
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertNumberOfCalls(t, "Create", 0)
+
+				// assertion
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				assert.Nil(t, created)
+			})
+			t.Run("Repository fails on creating the rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, nil)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockCategoryRuleRepo.On("Create", mock.AnythingOfType("*entity.CategoryRule")).Return(repositoryErr)
+
+				// action
+				created, err := sCategoryRule.Create(newCategoryRule())
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, created)
+			})
+		})
+	})
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Updating a rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, nil)
+				current := categoryRules[0]
+				categoryRule := newCategoryRule()
+				categoryRule.Category = "Supermarket"
+
+				// mock preparation
+				mockCategoryRuleRepo.On("FindByID", groceriesID).Return(&current, nil)
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockCategoryRuleRepo.On("Update", mock.AnythingOfType("*entity.CategoryRule")).Return(nil)
+
+				// action
+				updated, err := sCategoryRule.Update(groceriesID, categoryRule)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertExpectations(t)
+				mockCustomerRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, groceriesID, updated.CategoryRuleID)
+				assert.Equal(t, "Supermarket", updated.Category)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Rule not found", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+
+				// mock preparation
+				mockCategoryRuleRepo.On("FindByID", 50).Return(nil, errors.ErrNotFound)
+
+				// action
+				updated, err := sCategoryRule.Update(50, newCategoryRule())
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertNumberOfCalls(t, "Update", 0)
+
+				// assertion
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				assert.Nil(t, updated)
+			})
+			t.Run("Invalid rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+				current := categoryRules[0]
+				categoryRule := newCategoryRule()
+				categoryRule.Category = ""
+
+				// mock preparation
+				mockCategoryRuleRepo.On("FindByID", groceriesID).Return(&current, nil)
+
+				// action
+				updated, err := sCategoryRule.Update(groceriesID, categoryRule)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertNumberOfCalls(t, "Update", 0)
+
+				// assertion
+				assert.Error(t, err)
+				assert.Nil(t, updated)
+			})
+			t.Run("Repository fails on updating the rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, nil)
+				current := categoryRules[0]
+
+				// mock preparation
+				mockCategoryRuleRepo.On("FindByID", groceriesID).Return(&current, nil)
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockCategoryRuleRepo.On("Update", mock.AnythingOfType("*entity.CategoryRule")).Return(repositoryErr)
+
+				// action
+				updated, err := sCategoryRule.Update(groceriesID, newCategoryRule())
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, updated)
+			})
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Deleting a rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+
+				// mock preparation
+				mockCategoryRuleRepo.On("Delete", groceriesID).Return(nil)
+
+				// action
+				err := sCategoryRule.Delete(groceriesID)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+		})
+	})
+	t.Run("Find", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the rules of a customer", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+
+				// mock preparation
+				mockCategoryRuleRepo.On("Find", &customerID).Return(categoryRules, nil)
+
+				// action
+				got, err := sCategoryRule.Find(&customerID)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, categoryRules, got)
+			})
+			t.Run("Finding a rule", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+
+				// mock preparation
+				mockCategoryRuleRepo.On("FindByID", groceriesID).Return(&categoryRules[0], nil)
+
+				// action
+				got, err := sCategoryRule.FindByID(groceriesID)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, &categoryRules[0], got)
+			})
+		})
+	})
+	t.Run("NewCategorizer", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Categorizing with the rules of the customer", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+
+				// mock preparation
+				mockCategoryRuleRepo.On("Clone").Return(mockCategoryRuleRepo)
+				mockCategoryRuleRepo.On("Begin", transaction).Return(transaction)
+				mockCategoryRuleRepo.On("Find", &customerID).Return(categoryRules, nil)
+
+				// action
+				rulesCategorizer, err := sCategoryRule.NewCategorizer(transaction, 1)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertExpectations(t)
+				mockCategoryRuleRepo.AssertNumberOfCalls(t, "Rollback", 0)
+
+				// assertion
+				assert.NoError(t, err)
+				categoryRule := rulesCategorizer.Match(&entity.Movement{Description: "UBER TRIP", Type: -1})
+				assert.Equal(t, transportID, categoryRule.CategoryRuleID)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository fails on finding the rules", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, nil, nil)
+
+				// mock preparation
+				mockCategoryRuleRepo.On("Clone").Return(mockCategoryRuleRepo)
+				mockCategoryRuleRepo.On("Begin", nil).Return(nil)
+				mockCategoryRuleRepo.On("Rollback").Return(nil)
+				mockCategoryRuleRepo.On("Find", &customerID).Return(nil, repositoryErr)
+
+				// action
+				rulesCategorizer, err := sCategoryRule.NewCategorizer(nil, 1)
+
+				// mock assertion
+				mockCategoryRuleRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, rulesCategorizer)
+			})
+		})
+	})
+	t.Run("Recategorize", func(t *testing.T) {
+		batchSizeBackup := batchSize
+		batchSize = 2
+		defer func() { batchSize = batchSizeBackup }()
+		newMovements := func() ([]entity.Movement, []entity.Movement) {
+			return []entity.Movement{
+				{MovementID: 1, Description: "Walmart", Type: -1},
+				{MovementID: 2, Description: "Uber", Type: -1, Category: "Transport", CategoryRuleID: &transportID},
+			}, []entity.Movement{
+				{MovementID: 3, Description: "Rent", Type: -1, Category: "Groceries", CategoryRuleID: &groceriesID},
+			}
+		}
+		prepareMocks := func(mockCategoryRuleRepo *customMocks.ClientCategoryRuleRepository, mockCustomerRepo *customMocks.ClientCustomerRepository, mockMovementRepo *customMocks.ClientMovementRepository) {
+			mockCategoryRuleRepo.On("Clone").Return(mockCategoryRuleRepo)
+			mockCustomerRepo.On("Clone").Return(mockCustomerRepo)
+			mockMovementRepo.On("Clone").Return(mockMovementRepo)
+			mockCustomerRepo.On("Begin", nil).Return(transaction)
+			mockMovementRepo.On("Begin", transaction).Return(transaction)
+			mockCategoryRuleRepo.On("Begin", transaction).Return(transaction)
+			mockCustomerRepo.On("Rollback").Return(nil)
+		}
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Recategorizing the movements in batches", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, mockMovementRepo)
+				firstBatch, secondBatch := newMovements()
+
+				// mock preparation
+				prepareMocks(mockCategoryRuleRepo, mockCustomerRepo, mockMovementRepo)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockCategoryRuleRepo.On("Find", &customerID).Return(categoryRules, nil)
+				mockMovementRepo.On("FindCategorizable", 1, 0, 2).Return(firstBatch, nil)
+				mockMovementRepo.On("FindCategorizable", 1, 2, 2).Return(secondBatch, nil)
+				mockMovementRepo.On("UpdateCategory", 1, "Groceries", &groceriesID).Return(nil)
+				mockMovementRepo.On("UpdateCategory", 3, "", (*int)(nil)).Return(nil)
+				mockCustomerRepo.On("Commit").Return(nil)
+
+				// action
+				updated, err := sCategoryRule.Recategorize(1)
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "UpdateCategory", 2)
+				mockCustomerRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 2, updated)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Customer not found", func(t *testing.T) {
+				mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, mockMovementRepo)
+
+				// mock preparation
+				prepareMocks(mockCategoryRuleRepo, mockCustomerRepo, mockMovementRepo)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(nil, errors.ErrNotFound)
+
+				// action
+				updated, err := sCategoryRule.Recategorize(1)
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "FindCategorizable", 0)
+				mockCustomerRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+				// assertion
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				assert.Zero(t, updated)
+			})
+			t.Run("Repository fails on", func(t *testing.T) {
+				testCases := []struct {
+					Name   string
+					Method string
+				}{
+					{Name: "Finding the rules", Method: "Find"},
+					{Name: "Finding the movements", Method: "FindCategorizable"},
+					{Name: "Updating a category", Method: "UpdateCategory"},
+					{Name: "Committing", Method: "Commit"},
+				}
+				for _, testCase := range testCases {
+					t.Run(testCase.Name, func(t *testing.T) {
+						mockCategoryRuleRepo := new(customMocks.ClientCategoryRuleRepository)
+						mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+						mockMovementRepo := new(customMocks.ClientMovementRepository)
+						sCategoryRule := NewCategoryRuleService(mockCategoryRuleRepo, mockCustomerRepo, mockMovementRepo)
+						firstBatch, secondBatch := newMovements()
+						errorOn := func(method string) error {
+							if method == testCase.Method {
+								return repositoryErr
+							}
+							return nil
+						}
+
+						// mock preparation
+						prepareMocks(mockCategoryRuleRepo, mockCustomerRepo, mockMovementRepo)
+						mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+						mockCategoryRuleRepo.On("Find", &customerID).Return(categoryRules, errorOn("Find"))
+						mockMovementRepo.On("FindCategorizable", 1, 0, 2).Return(firstBatch, errorOn("FindCategorizable"))
+						mockMovementRepo.On("FindCategorizable", 1, 2, 2).Return(secondBatch, nil)
+						mockMovementRepo.On("UpdateCategory", mock.Anything, mock.Anything, mock.Anything).Return(errorOn("UpdateCategory"))
+						mockCustomerRepo.On("Commit").Return(errorOn("Commit"))
+
+						// action
+						updated, err := sCategoryRule.Recategorize(1)
+
+						// mock assertion
+						mockCustomerRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+						// assertion
+						assert.EqualError(t, err, repositoryErr.Error())
+						assert.Zero(t, updated)
+					})
+				}
+			})
+		})
+	})
+}
@@ -42,6 +42,18 @@ func (r *customerGormRepo) FindByCustomerID(customerId int) (*entity.Customer, e
 	return r.findAndMayLockByCustomerID(customerId, false)
 }
 
+/*
+FindIDs returns the IDs of all the customers in order
+*/
+func (r *customerGormRepo) FindIDs() ([]int, error) {
+	customerIDs := []int{}
+	err := r.DB.Model(&entity.Customer{}).Order("customer_id").Pluck("customer_id", &customerIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return customerIDs, nil
+}
+
 /*
 findAndMayLockByCustomerID returns a customer by its id and locks it if the second argument is true
 */
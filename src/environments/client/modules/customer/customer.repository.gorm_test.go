@@ -171,4 +171,40 @@ func TestCustomerRepository(t *testing.T) {
 			assert.Equal(t, rCustomer, clone)
 		})
 	})
+	t.Run("FindIDs", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the ids of the customers", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rCustomer := NewCustomerGormRepo(tx)
+
+				got, err := rCustomer.FindIDs()
+
+				assert.NoError(t, err)
+				assert.Equal(t, []int{1, 2, 3, 4}, got)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rCustomer := NewCustomerGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Customer{})
+
+				got, err := rCustomer.FindIDs()
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
 }
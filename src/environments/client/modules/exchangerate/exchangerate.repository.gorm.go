@@ -0,0 +1,67 @@
+package exchangerate
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/client/resources/interfaces"
+	database "stori-service/src/environments/common/resources/database/transaction"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+struct that implements IExchangeRateRepository
+*/
+type exchangeRateGormRepo struct {
+	database.TransactionalGORMRepository
+}
+
+/*
+NewExchangeRateGormRepo creates a new repo and returns IExchangeRateRepository,
+so it needs to implement all its methods
+*/
+func NewExchangeRateGormRepo(gormDb *gorm.DB) interfaces.IExchangeRateRepository {
+	rExchangeRate := &exchangeRateGormRepo{}
+	rExchangeRate.DB = gormDb
+	return rExchangeRate
+}
+
+/*
+FindEffective returns the rate of the currencies effective on the date, the last one with a date until it
+*/
+func (r *exchangeRateGormRepo) FindEffective(baseCurrency string, quoteCurrency string, date time.Time) (*entity.ExchangeRate, error) {
+	var exchangeRate entity.ExchangeRate
+	err := r.DB.Where("base_currency = ? AND quote_currency = ? AND date <= ?", baseCurrency, quoteCurrency, date).
+		Order("date DESC").
+		First(&exchangeRate).Error
+	if goerrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &exchangeRate, nil
+}
+
+/*
+Upsert creates the rates, the ones of a pair and date that already exist get the new rate
+*/
+func (r *exchangeRateGormRepo) Upsert(exchangeRates []entity.ExchangeRate) error {
+	if len(exchangeRates) == 0 {
+		return nil
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&exchangeRates).Error
+}
+
+/*
+Clone returns a new instance of the repository
+*/
+func (r *exchangeRateGormRepo) Clone() interface{} {
+	return NewExchangeRateGormRepo(r.DB)
+}
@@ -0,0 +1,183 @@
+package exchangerate
+
+import (
+	"os"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/database"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// setup
+	database.SetupStoriGormDB()
+	code := m.Run()
+	os.Exit(code)
+}
+
+var (
+	firstDay  = time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+	secondDay = time.Date(2022, time.June, 2, 0, 0, 0, 0, time.UTC)
+)
+
+var exchangeRates = []entity.ExchangeRate{
+	{ExchangeRateID: 1, BaseCurrency: "USD", QuoteCurrency: "MXN", Date: firstDay, Rate: 2005000000},
+	{ExchangeRateID: 2, BaseCurrency: "USD", QuoteCurrency: "MXN", Date: secondDay, Rate: 1990000000},
+	{ExchangeRateID: 3, BaseCurrency: "EUR", QuoteCurrency: "MXN", Date: firstDay, Rate: 2112345678},
+}
+
+/*
+	Fixtures: two days of dollars to pesos and one of euros to pesos
+*/
+func addFixtures(tx *gorm.DB) {
+	tx.Where("1=1").Delete(&entity.ExchangeRate{}) // cleaning rates
+	tx.Create(exchangeRates)
+}
+
+func TestExchangeRateRepository(t *testing.T) {
+	t.Run("FindEffective", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				name     string
+				base     string
+				date     time.Time
+				expected money.Rate
+			}{
+				{name: "Rate of the day", base: "USD", date: firstDay.Add(10 * time.Hour), expected: 2005000000},
+				{name: "Rate of a later day", base: "USD", date: secondDay, expected: 1990000000},
+				{name: "Last rate before the day", base: "EUR", date: secondDay.AddDate(0, 1, 0), expected: 2112345678},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.name, func(t *testing.T) {
+					connection := database.GetStoriGormConnection()
+					tx := connection.Begin()
+					addFixtures(tx)
+					rExchangeRate := NewExchangeRateGormRepo(tx)
+
+					got, err := rExchangeRate.FindEffective(testCase.base, "MXN", testCase.date)
+
+					assert.NoError(t, err)
+					assert.Equal(t, testCase.expected, got.Rate)
+					t.Cleanup(func() {
+						tx.Rollback()
+					})
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Date before the first rate", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rExchangeRate := NewExchangeRateGormRepo(tx)
+
+				got, err := rExchangeRate.FindEffective("USD", "MXN", firstDay.AddDate(0, 0, -1))
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Pair without rates", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rExchangeRate := NewExchangeRateGormRepo(tx)
+
+				got, err := rExchangeRate.FindEffective("MXN", "USD", secondDay)
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rExchangeRate := NewExchangeRateGormRepo(tx)
+				tx.Exec("DROP TABLE exchange_rate CASCADE")
+
+				got, err := rExchangeRate.FindEffective("USD", "MXN", secondDay)
+
+				assert.Nil(t, got)
+				assert.Error(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Upsert", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating and replacing rates", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rExchangeRate := NewExchangeRateGormRepo(tx)
+
+				err := rExchangeRate.Upsert([]entity.ExchangeRate{
+					{BaseCurrency: "USD", QuoteCurrency: "MXN", Date: secondDay, Rate: 1980000000},
+					{BaseCurrency: "EUR", QuoteCurrency: "MXN", Date: secondDay, Rate: 2100000000},
+				})
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var count int64
+				tx.Model(&entity.ExchangeRate{}).Count(&count)
+				assert.Equal(t, int64(4), count)
+				var got entity.ExchangeRate
+				tx.Where("base_currency = ? AND date = ?", "USD", secondDay).First(&got)
+				assert.Equal(t, money.Rate(1980000000), got.Rate)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Without rates", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rExchangeRate := NewExchangeRateGormRepo(tx)
+
+				err := rExchangeRate.Upsert(nil)
+
+				assert.NoError(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rExchangeRate := NewExchangeRateGormRepo(tx)
+				tx.Exec("DROP TABLE exchange_rate CASCADE")
+
+				err := rExchangeRate.Upsert(exchangeRates)
+
+				assert.Error(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Clone", func(t *testing.T) {
+		db := database.GetStoriGormConnection()
+		rExchangeRate := NewExchangeRateGormRepo(db)
+
+		clone := rExchangeRate.Clone()
+
+		assert.NotNil(t, clone)
+		assert.Equal(t, rExchangeRate, clone)
+	})
+}
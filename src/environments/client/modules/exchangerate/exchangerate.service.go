@@ -0,0 +1,189 @@
+package exchangerate
+
+import (
+	"encoding/csv"
+	goerrors "errors"
+	"io"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/env"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
+	"strings"
+	"time"
+)
+
+var (
+	batchSize = env.ImportBatchSize // declared here to test files with several batches
+)
+
+// rateColumns are the columns of the exchange rates file, in any order
+var rateColumns = []string{"base", "quote", "date", "rate"}
+
+/*
+Struct that implements IExchangeRateService
+*/
+type exchangeRateService struct {
+	rExchangeRate interfaces.IExchangeRateRepository
+}
+
+/*
+	NewExchangeRateService creates a new service, receives repositories by dependency injection
+	and returns IExchangeRateService, so it needs to implement all its methods
+*/
+func NewExchangeRateService(rExchangeRate interfaces.IExchangeRateRepository) interfaces.IExchangeRateService {
+	return &exchangeRateService{rExchangeRate}
+}
+
+/*
+Rate returns the rate to convert from a currency to another effective on the date,
+a currency converts to itself with a rate of one
+*/
+func (s *exchangeRateService) Rate(from string, to string, date time.Time) (money.Rate, error) {
+	if from == to {
+		return money.OneRate, nil
+	}
+	exchangeRate, err := s.rExchangeRate.FindEffective(from, to, date)
+	if goerrors.Is(err, errors.ErrNotFound) {
+		return 0, errors.ErrMissingExchangeRate.WithTemplate(map[string]interface{}{
+			"From": from,
+			"To":   to,
+			"Date": date.Format(constant.DateLayouts[constant.DateFormatISO]),
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+	return exchangeRate.Rate, nil
+}
+
+/*
+Convert returns the amount in another currency with the rate effective on the date
+*/
+func (s *exchangeRateService) Convert(amount money.Amount, from string, to string, date time.Time) (money.Amount, error) {
+	rate, err := s.Rate(from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	return amount.Convert(rate), nil
+}
+
+/*
+ImportRates reads a CSV file with the columns base, quote, date (YYYY-MM-DD) and rate and saves its rates,
+replacing the ones of the same pair and date, the last line wins when the file repeats them.
+Nothing is saved when a line is invalid, it returns the imported rates
+*/
+func (s *exchangeRateService) ImportRates(file io.Reader) (int, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return 0, errors.ErrInvalidStatement
+	}
+	indexes, err := rateIndexes(header)
+	if err != nil {
+		return 0, err
+	}
+
+	rExchangeRate := s.rExchangeRate.Clone().(interfaces.IExchangeRateRepository)
+	rExchangeRate.Begin(nil)
+	defer rExchangeRate.Rollback()
+
+	imported := 0
+	exchangeRates := make([]entity.ExchangeRate, 0, batchSize)
+	positions := make(map[string]int, batchSize) // a batch can't have a pair and date twice for the ON CONFLICT
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, errors.ErrInvalidRateLine.WithTemplate(map[string]interface{}{"Line": line})
+		}
+		exchangeRate, err := parseRate(record, indexes)
+		if err != nil {
+			return 0, errors.ErrInvalidRateLine.WithTemplate(map[string]interface{}{"Line": line})
+		}
+		key := exchangeRate.BaseCurrency + exchangeRate.QuoteCurrency + exchangeRate.Date.Format(constant.DateLayouts[constant.DateFormatISO])
+		if position, ok := positions[key]; ok {
+			exchangeRates[position] = *exchangeRate
+			continue
+		}
+		positions[key] = len(exchangeRates)
+		exchangeRates = append(exchangeRates, *exchangeRate)
+		if len(exchangeRates) == batchSize {
+			if err := rExchangeRate.Upsert(exchangeRates); err != nil {
+				return 0, err
+			}
+			imported += len(exchangeRates)
+			exchangeRates = exchangeRates[:0]
+			positions = make(map[string]int, batchSize)
+		}
+	}
+	if err := rExchangeRate.Upsert(exchangeRates); err != nil {
+		return 0, err
+	}
+	imported += len(exchangeRates)
+	if err := rExchangeRate.Commit(); err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
+
+/*
+rateIndexes returns the position of each column in the header, failing when one is missing
+*/
+func rateIndexes(header []string) (map[string]int, error) {
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		indexes[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))] = i
+	}
+	var missing []string
+	for _, column := range rateColumns {
+		if _, ok := indexes[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.ErrMissingColumns.WithTemplate(map[string]interface{}{
+			"Columns": strings.Join(missing, ", "),
+		})
+	}
+	return indexes, nil
+}
+
+/*
+parseRate returns the exchange rate of a line, failing when a value is not valid or the currency is not supported
+*/
+func parseRate(record []string, indexes map[string]int) (*entity.ExchangeRate, error) {
+	value := func(column string) string {
+		if indexes[column] >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[indexes[column]])
+	}
+	date, err := time.Parse(constant.DateLayouts[constant.DateFormatISO], value("date"))
+	if err != nil {
+		return nil, err
+	}
+	rate, err := money.ParseRate(value("rate"))
+	if err != nil {
+		return nil, err
+	}
+	exchangeRate := &entity.ExchangeRate{
+		BaseCurrency:  strings.ToUpper(value("base")),
+		QuoteCurrency: strings.ToUpper(value("quote")),
+		Date:          date,
+		Rate:          rate,
+	}
+	if err := exchangeRate.Validate(); err != nil {
+		return nil, err
+	}
+	for _, currency := range []string{exchangeRate.BaseCurrency, exchangeRate.QuoteCurrency} {
+		if _, ok := constant.CurrencyDecimals[currency]; !ok {
+			return nil, errors.ErrInvalidRateLine
+		}
+	}
+	return exchangeRate, nil
+}
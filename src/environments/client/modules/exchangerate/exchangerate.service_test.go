@@ -0,0 +1,253 @@
+package exchangerate
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	customMocks "stori-service/src/utils/test/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExchangeRateService(t *testing.T) {
+	date := time.Date(2022, time.June, 30, 10, 0, 0, 0, time.UTC)
+	repositoryErr := goerrors.New("repository error")
+	usdRate := &entity.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "MXN", Date: date, Rate: 2000000000}
+	t.Run("Rate", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Rate of the pair", func(t *testing.T) {
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+				// mock preparation
+				mockExchangeRateRepo.On("FindEffective", "USD", "MXN", date).Return(usdRate, nil)
+
+				// action
+				rate, err := sExchangeRate.Rate("USD", "MXN", date)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, money.Rate(2000000000), rate)
+			})
+			t.Run("Same currency", func(t *testing.T) {
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+				// action
+				rate, err := sExchangeRate.Rate("MXN", "MXN", date)
+
+				// mock assertion
+				mockExchangeRateRepo.AssertNumberOfCalls(t, "FindEffective", 0)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, money.OneRate, rate)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Missing rate", func(t *testing.T) {
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+				// mock preparation
+				mockExchangeRateRepo.On("FindEffective", "USD", "MXN", date).Return(nil, errors.ErrNotFound)
+
+				// action
+				rate, err := sExchangeRate.Rate("USD", "MXN", date)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrMissingExchangeRate)
+				assert.Equal(t, map[string]interface{}{"From": "USD", "To": "MXN", "Date": "2022-06-30"}, err.(errors.MyError).GetData())
+				assert.Zero(t, rate)
+			})
+			t.Run("Repository error", func(t *testing.T) {
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+				// mock preparation
+				mockExchangeRateRepo.On("FindEffective", "USD", "MXN", date).Return(nil, repositoryErr)
+
+				// action
+				_, err := sExchangeRate.Rate("USD", "MXN", date)
+
+				// assertion
+				assert.Equal(t, repositoryErr, err)
+			})
+		})
+	})
+	t.Run("Convert", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+			sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+			// mock preparation
+			mockExchangeRateRepo.On("FindEffective", "USD", "MXN", date).Return(usdRate, nil)
+
+			// action
+			amount, err := sExchangeRate.Convert(1050, "USD", "MXN", date)
+
+			// assertion
+			assert.NoError(t, err)
+			assert.Equal(t, money.Amount(21000), amount)
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+			sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+			// mock preparation
+			mockExchangeRateRepo.On("FindEffective", "EUR", "MXN", date).Return(nil, errors.ErrNotFound)
+
+			// action
+			amount, err := sExchangeRate.Convert(1050, "EUR", "MXN", date)
+
+			// assertion
+			assert.ErrorIs(t, err, errors.ErrMissingExchangeRate)
+			assert.Zero(t, amount)
+		})
+	})
+	t.Run("ImportRates", func(t *testing.T) {
+		prepareMocks := func(mockExchangeRateRepo *customMocks.ClientExchangeRateRepository) {
+			mockExchangeRateRepo.On("Clone").Return(mockExchangeRateRepo)
+			mockExchangeRateRepo.On("Begin", nil).Return(nil)
+			mockExchangeRateRepo.On("Rollback").Return(nil)
+		}
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Importing rates in several batches", func(t *testing.T) {
+				batchSizeBackup := batchSize
+				batchSize = 2
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+				file := "\uFEFFdate,Base,quote,rate\n" +
+					"2022-06-01,usd,MXN,20.05\n" +
+					"2022-06-02,USD,MXN,19.9\n" +
+					"2022-06-01,EUR,MXN,21.12345678\n"
+				firstDay := time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+				secondDay := time.Date(2022, time.June, 2, 0, 0, 0, 0, time.UTC)
+
+				// mock preparation
+				prepareMocks(mockExchangeRateRepo)
+				mockExchangeRateRepo.On("Upsert", []entity.ExchangeRate{
+					{BaseCurrency: "USD", QuoteCurrency: "MXN", Date: firstDay, Rate: 2005000000},
+					{BaseCurrency: "USD", QuoteCurrency: "MXN", Date: secondDay, Rate: 1990000000},
+				}).Return(nil).Once()
+				mockExchangeRateRepo.On("Upsert", []entity.ExchangeRate{
+					{BaseCurrency: "EUR", QuoteCurrency: "MXN", Date: firstDay, Rate: 2112345678},
+				}).Return(nil).Once()
+				mockExchangeRateRepo.On("Commit").Return(nil)
+
+				// action
+				imported, err := sExchangeRate.ImportRates(strings.NewReader(file))
+
+				// mock assertion
+				mockExchangeRateRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 3, imported)
+
+				t.Cleanup(func() {
+					batchSize = batchSizeBackup
+				})
+			})
+			t.Run("Importing a pair and date repeated in the file", func(t *testing.T) {
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+				file := "base,quote,date,rate\n" +
+					"USD,MXN,2022-06-01,20.05\n" +
+					"EUR,MXN,2022-06-01,21.1\n" +
+					"usd,MXN,2022-06-01,20.1\n"
+				firstDay := time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+				// mock preparation
+				prepareMocks(mockExchangeRateRepo)
+				mockExchangeRateRepo.On("Upsert", []entity.ExchangeRate{
+					{BaseCurrency: "USD", QuoteCurrency: "MXN", Date: firstDay, Rate: 2010000000},
+					{BaseCurrency: "EUR", QuoteCurrency: "MXN", Date: firstDay, Rate: 2110000000},
+				}).Return(nil).Once()
+				mockExchangeRateRepo.On("Commit").Return(nil)
+
+				// action
+				imported, err := sExchangeRate.ImportRates(strings.NewReader(file))
+
+				// mock assertion
+				mockExchangeRateRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 2, imported)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Missing columns", func(t *testing.T) {
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+				// action
+				_, err := sExchangeRate.ImportRates(strings.NewReader("base,quote,day,rate\nUSD,MXN,2022-06-01,20\n"))
+
+				// mock assertion
+				mockExchangeRateRepo.AssertNumberOfCalls(t, "Begin", 0)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrMissingColumns)
+				assert.Equal(t, map[string]interface{}{"Columns": "date"}, err.(errors.MyError).GetData())
+			})
+			testCases := []struct {
+				name string
+				line string
+			}{
+				{name: "Invalid date", line: "USD,MXN,01/06/2022,20"},
+				{name: "Invalid rate", line: "USD,MXN,2022-06-01,veinte"},
+				{name: "Negative rate", line: "USD,MXN,2022-06-01,-20"},
+				{name: "Unsupported currency", line: "XXX,MXN,2022-06-01,20"},
+				{name: "Same currencies", line: "MXN,MXN,2022-06-01,1"},
+				{name: "Missing values", line: "USD,MXN"},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.name, func(t *testing.T) {
+					mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+					sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+					// mock preparation
+					prepareMocks(mockExchangeRateRepo)
+
+					// action
+					imported, err := sExchangeRate.ImportRates(strings.NewReader("base,quote,date,rate\nUSD,MXN,2022-06-01,20\n" + testCase.line + "\n"))
+
+					// mock assertion
+					mockExchangeRateRepo.AssertNumberOfCalls(t, "Upsert", 0)
+					mockExchangeRateRepo.AssertNumberOfCalls(t, "Commit", 0)
+					mockExchangeRateRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+					// assertion
+					assert.ErrorIs(t, err, errors.ErrInvalidRateLine)
+					assert.Equal(t, map[string]interface{}{"Line": 3}, err.(errors.MyError).GetData())
+					assert.Zero(t, imported)
+				})
+			}
+			t.Run("Repository error", func(t *testing.T) {
+				mockExchangeRateRepo := new(customMocks.ClientExchangeRateRepository)
+				sExchangeRate := NewExchangeRateService(mockExchangeRateRepo)
+
+				// mock preparation
+				prepareMocks(mockExchangeRateRepo)
+				mockExchangeRateRepo.On("Upsert", mock.Anything).Return(repositoryErr)
+
+				// action
+				imported, err := sExchangeRate.ImportRates(strings.NewReader("base,quote,date,rate\nUSD,MXN,2022-06-01,20\n"))
+
+				// mock assertion
+				mockExchangeRateRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.Equal(t, repositoryErr, err)
+				assert.Zero(t, imported)
+			})
+		})
+	})
+}
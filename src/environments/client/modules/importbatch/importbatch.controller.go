@@ -0,0 +1,50 @@
+package importbatch
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/controller"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils"
+	"stori-service/src/utils/helpers"
+)
+
+// struct that implements IImportBatchController
+type importBatchController struct {
+	controller.ClientController
+	sImportBatch interfaces.IImportBatchService
+}
+
+/*
+NewImportBatchController creates a new controller, receives service by dependency injection
+and returns IImportBatchController, so needs to implement all its methods
+*/
+func NewImportBatchController(sImportBatch interfaces.IImportBatchService) interfaces.IImportBatchController {
+	return &importBatchController{sImportBatch: sImportBatch}
+}
+
+/*
+Revert takes the batch ID from params and who reverts it and why from the body,
+then calls the service to revert the import
+*/
+func (c *importBatchController) Revert(response http.ResponseWriter, request *http.Request) {
+	importBatchID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	var importRevert dto.ImportRevert
+	if err := utils.GetBodyRequest(request, &importRevert); err != nil {
+		c.MakeErrorResponse(response, errors.ErrInvalidBody)
+		return
+	}
+	importBatch, err := c.sImportBatch.Revert(importBatchID, importRevert)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, importBatch, http.StatusOK, i18n.T(i18n.Message{MessageID: "IMPORT_BATCH.REVERTED"}))
+}
@@ -0,0 +1,114 @@
+package importbatch
+
+import (
+	"net/http"
+	"net/url"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils"
+	"stori-service/src/utils/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBatchController(t *testing.T) {
+	urlvalues := url.Values{}
+	path := `/{id}`
+	importRevert := dto.ImportRevert{RevertedBy: "ops@partner.com", Reason: "Wrong file sent"}
+	revertedAt := time.Date(2022, time.June, 30, 10, 0, 0, 0, time.UTC)
+	expectedImportBatch := &entity.ImportBatch{
+		ImportBatchID: 7,
+		CustomerID:    1,
+		ImportedRows:  2,
+		RevertedAt:    &revertedAt,
+		RevertedBy:    &importRevert.RevertedBy,
+		RevertReason:  &importRevert.Reason,
+	}
+	t.Run("Revert", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Reverting a batch", func(t *testing.T) {
+				// fixture
+				mockImportBatchService := new(mock.ClientImportBatchService)
+				importBatchController := NewImportBatchController(mockImportBatchService)
+
+				// mock expectations
+				mockImportBatchService.On("Revert", 7, importRevert).Return(expectedImportBatch, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, importBatchController.Revert, "7", urlvalues, importRevert)
+
+				//Mock Assertion
+				mockImportBatchService.AssertExpectations(t)
+				mockImportBatchService.AssertNumberOfCalls(t, "Revert", 1)
+
+				result := &entity.ImportBatch{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "IMPORT_BATCH.REVERTED"}), bodyResponse.Message)
+				assert.Empty(t, bodyResponse.Errors)
+				assert.Equal(t, importRevert.RevertedBy, *result.RevertedBy)
+				assert.Equal(t, importRevert.Reason, *result.RevertReason)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid id", func(t *testing.T) {
+				// fixture
+				importBatchController := NewImportBatchController(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, importBatchController.Revert, "asd", urlvalues, importRevert)
+
+				result := &entity.ImportBatch{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Invalid body", func(t *testing.T) {
+				// fixture
+				importBatchController := NewImportBatchController(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, importBatchController.Revert, "7", urlvalues, "reason")
+
+				result := &entity.ImportBatch{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, errors.ErrInvalidBody.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Batch already reverted", func(t *testing.T) {
+				// fixture
+				mockImportBatchService := new(mock.ClientImportBatchService)
+				importBatchController := NewImportBatchController(mockImportBatchService)
+
+				// mock expectations
+				mockImportBatchService.On("Revert", 7, importRevert).Return(nil, errors.ErrImportBatchReverted)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, importBatchController.Revert, "7", urlvalues, importRevert)
+
+				//Mock Assertion
+				mockImportBatchService.AssertExpectations(t)
+
+				result := &entity.ImportBatch{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+				assert.Equal(t, errors.ErrImportBatchReverted.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+		})
+	})
+}
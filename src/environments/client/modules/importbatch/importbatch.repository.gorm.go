@@ -0,0 +1,81 @@
+package importbatch
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/client/resources/interfaces"
+	database "stori-service/src/environments/common/resources/database/transaction"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+struct that implements IImportBatchRepository
+*/
+type importBatchGormRepo struct {
+	database.TransactionalGORMRepository
+}
+
+/*
+NewImportBatchGormRepo creates a new repo and returns IImportBatchRepository,
+so it needs to implement all its methods
+*/
+func NewImportBatchGormRepo(gormDb *gorm.DB) interfaces.IImportBatchRepository {
+	rImportBatch := &importBatchGormRepo{}
+	rImportBatch.DB = gormDb
+	return rImportBatch
+}
+
+/*
+Create receives an import batch and creates it, the generated ID is set on the received batch
+*/
+func (r *importBatchGormRepo) Create(importBatch *entity.ImportBatch) error {
+	return r.DB.Create(importBatch).Error
+}
+
+/*
+Update receives an import batch and saves all its fields
+*/
+func (r *importBatchGormRepo) Update(importBatch *entity.ImportBatch) error {
+	return r.DB.Save(importBatch).Error
+}
+
+/*
+FindByFileHash returns the import batch of the customer with the file fingerprint that wasn't reverted
+*/
+func (r *importBatchGormRepo) FindByFileHash(customerID int, fileHash string) (*entity.ImportBatch, error) {
+	var importBatch entity.ImportBatch
+	err := r.DB.Where("customer_id = ? AND file_hash = ? AND reverted_at IS NULL", customerID, fileHash).
+		First(&importBatch).Error
+	if goerrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &importBatch, nil
+}
+
+/*
+FindAndLockByID returns the import batch and locks it until the transaction ends
+*/
+func (r *importBatchGormRepo) FindAndLockByID(importBatchID int) (*entity.ImportBatch, error) {
+	var importBatch entity.ImportBatch
+	err := r.DB.Clauses(clause.Locking{Strength: "UPDATE"}).First(&importBatch, importBatchID).Error
+	if goerrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &importBatch, nil
+}
+
+/*
+Clone returns a new instance of the repository
+*/
+func (r *importBatchGormRepo) Clone() interface{} {
+	return NewImportBatchGormRepo(r.DB)
+}
@@ -0,0 +1,249 @@
+package importbatch
+
+import (
+	"os"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/database"
+	"stori-service/src/libs/errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// setup
+	database.SetupStoriGormDB()
+	code := m.Run()
+	os.Exit(code)
+}
+
+var startedAt = time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+var importBatches = []entity.ImportBatch{
+	{
+		ImportBatchID: 1,
+		CustomerID:    1,
+		FileHash:      strings.Repeat("a", 64),
+		TotalRows:     2,
+		ImportedRows:  2,
+		StartedAt:     startedAt,
+	},
+	{
+		ImportBatchID: 2,
+		CustomerID:    2,
+		FileHash:      strings.Repeat("a", 64),
+		TotalRows:     3,
+		ImportedRows:  2,
+		RejectedRows:  1,
+		StartedAt:     startedAt,
+	},
+}
+
+/*
+	Fixtures: two import batches of the same file for different customers
+*/
+func addFixtures(tx *gorm.DB) {
+	tx.Unscoped().Where("1=1").Delete(&entity.Movement{})    // cleaning the movements of the batches
+	tx.Unscoped().Where("1=1").Delete(&entity.ImportBatch{}) // cleaning batches
+	tx.Create(importBatches)
+}
+
+func TestImportBatchRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating a batch", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportBatch := NewImportBatchGormRepo(tx)
+				importBatch := &entity.ImportBatch{CustomerID: 1, FileHash: strings.Repeat("b", 64), StartedAt: startedAt}
+
+				err := rImportBatch.Create(importBatch)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.NotZero(t, importBatch.ImportBatchID)
+
+				// database assertion
+				var count int64
+				tx.Model(&entity.ImportBatch{}).Count(&count)
+				assert.Equal(t, int64(3), count)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Same file for the same customer", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportBatch := NewImportBatchGormRepo(tx)
+
+				err := rImportBatch.Create(&entity.ImportBatch{CustomerID: 1, FileHash: strings.Repeat("a", 64), StartedAt: startedAt})
+
+				// data assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Updating a batch", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportBatch := NewImportBatchGormRepo(tx)
+				importBatch := importBatches[0]
+				importBatch.Balances = entity.CurrencyBalances{{Currency: "MXN", FinalAvailable: 1050}}
+				finishedAt := startedAt.Add(time.Minute)
+				importBatch.FinishedAt = &finishedAt
+
+				err := rImportBatch.Update(&importBatch)
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var got entity.ImportBatch
+				tx.First(&got, importBatch.ImportBatchID)
+				assert.Equal(t, entity.CurrencyBalances{{Currency: "MXN", FinalAvailable: 1050}}, got.Balances)
+				assert.NotNil(t, got.FinishedAt)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindByFileHash", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the batch of the customer", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportBatch := NewImportBatchGormRepo(tx)
+
+				got, err := rImportBatch.FindByFileHash(2, strings.Repeat("a", 64))
+
+				assert.NoError(t, err)
+				assert.True(t, cmp.Equal(got, &importBatches[1], cmpopts.IgnoreTypes(time.Time{}, gorm.DeletedAt{})))
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("File not imported by the customer", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportBatch := NewImportBatchGormRepo(tx)
+
+				got, err := rImportBatch.FindByFileHash(3, strings.Repeat("a", 64))
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Batch reverted", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				tx.Model(&importBatches[1]).Update("reverted_at", startedAt)
+				rImportBatch := NewImportBatchGormRepo(tx)
+
+				got, err := rImportBatch.FindByFileHash(2, strings.Repeat("a", 64))
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rImportBatch := NewImportBatchGormRepo(tx)
+				tx.Exec("DROP TABLE import_batch CASCADE")
+
+				got, err := rImportBatch.FindByFileHash(1, strings.Repeat("a", 64))
+
+				assert.Nil(t, got)
+				assert.Error(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindAndLockByID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the batch", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportBatch := NewImportBatchGormRepo(tx)
+
+				got, err := rImportBatch.FindAndLockByID(2)
+
+				assert.NoError(t, err)
+				assert.True(t, cmp.Equal(got, &importBatches[1], cmpopts.IgnoreTypes(time.Time{}, gorm.DeletedAt{})))
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Batch doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportBatch := NewImportBatchGormRepo(tx)
+
+				got, err := rImportBatch.FindAndLockByID(50)
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rImportBatch := NewImportBatchGormRepo(tx)
+				tx.Exec("DROP TABLE import_batch CASCADE")
+
+				got, err := rImportBatch.FindAndLockByID(1)
+
+				assert.Nil(t, got)
+				assert.Error(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Clone", func(t *testing.T) {
+		db := database.GetStoriGormConnection()
+		rImportBatch := NewImportBatchGormRepo(db)
+
+		clone := rImportBatch.Clone()
+
+		assert.NotNil(t, clone)
+		assert.Equal(t, rImportBatch, clone)
+	})
+}
@@ -0,0 +1,33 @@
+package importbatch
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/utils/helpers"
+
+	"github.com/gorilla/mux"
+)
+
+type importBatchRouter struct {
+	cImportBatch interfaces.IImportBatchController
+}
+
+/*
+NewImportBatchRouter receives the controller and calls all functions for route versions
+*/
+func NewImportBatchRouter(subRouter *mux.Router, cImportBatch interfaces.IImportBatchController) {
+	routerImportBatch := importBatchRouter{cImportBatch}
+	routerImportBatch.routes(subRouter)
+}
+
+/*
+routes assigns controller function for routes
+*/
+func (r *importBatchRouter) routes(subRouter *mux.Router) {
+	subRouter.
+		Path(`/{id}/revert`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cImportBatch.Revert),
+		)).
+		Methods(http.MethodPost)
+}
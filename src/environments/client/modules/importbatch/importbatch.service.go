@@ -0,0 +1,100 @@
+package importbatch
+
+import (
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"time"
+)
+
+var (
+	timeNow = time.Now // declared here for easy testing with spy
+)
+
+/*
+Struct that implements IImportBatchService
+*/
+type importBatchService struct {
+	rImportBatch interfaces.IImportBatchRepository
+	rMovement    interfaces.IMovementRepository
+	rCustomer    interfaces.ICustomerRepository
+	sBalance     interfaces.IBalanceService
+	sLedger      interfaces.ILedgerService
+}
+
+/*
+	NewImportBatchService creates a new service, receives repositories and the balance and ledger services by dependency injection
+	and returns IImportBatchService, so it needs to implement all its methods
+*/
+func NewImportBatchService(rImportBatch interfaces.IImportBatchRepository, rMovement interfaces.IMovementRepository, rCustomer interfaces.ICustomerRepository, sBalance interfaces.IBalanceService, sLedger interfaces.ILedgerService) interfaces.IImportBatchService {
+	return &importBatchService{rImportBatch, rMovement, rCustomer, sBalance, sLedger}
+}
+
+/*
+Revert undoes an import: it deletes the movements of the batch and recalculates the available of the movements
+in each currency from the date of the first deleted one, as they can be before movements of other imports.
+Their journal entries are reversed in the ledger with new entries on the revert date.
+The batch is kept with who reverted it and why, and its file can be imported again
+*/
+func (s *importBatchService) Revert(importBatchID int, importRevert dto.ImportRevert) (*entity.ImportBatch, error) {
+	if err := importRevert.Validate(); err != nil {
+		return nil, err
+	}
+	rImportBatch := s.rImportBatch.Clone().(interfaces.IImportBatchRepository)
+	rMovement := s.rMovement.Clone().(interfaces.IMovementRepository)
+	rCustomer := s.rCustomer.Clone().(interfaces.ICustomerRepository)
+	tx := rImportBatch.Begin(nil)
+	rMovement.Begin(tx)
+	rCustomer.Begin(tx)
+	defer rImportBatch.Rollback()
+
+	// the batch is locked, so it can't be reverted twice at the same time
+	importBatch, err := rImportBatch.FindAndLockByID(importBatchID)
+	if err != nil {
+		return nil, err
+	}
+	if importBatch.RevertedAt != nil {
+		return nil, errors.ErrImportBatchReverted
+	}
+	// then lock the customer, so no import runs while the balances are changed
+	_, err = rCustomer.FindAndLockByCustomerID(importBatch.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	firstDates, err := rMovement.FindFirstDatesByImportBatchID(importBatchID)
+	if err != nil {
+		return nil, err
+	}
+	err = rMovement.DeleteByImportBatchID(importBatchID)
+	if err != nil {
+		return nil, err
+	}
+	revertedAt := timeNow()
+	err = s.sLedger.ReverseImportBatch(tx, importBatchID, revertedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range importBatch.Balances {
+		firstDate, ok := firstDates[balance.Currency]
+		if !ok {
+			continue
+		}
+		err = s.sBalance.Recalculate(tx, importBatch.CustomerID, balance.Currency, firstDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	importBatch.RevertedAt = &revertedAt
+	importBatch.RevertedBy = &importRevert.RevertedBy
+	importBatch.RevertReason = &importRevert.Reason
+	err = rImportBatch.Update(importBatch)
+	if err != nil {
+		return nil, err
+	}
+	err = rImportBatch.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return importBatch, nil
+}
@@ -0,0 +1,232 @@
+package importbatch
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	customMocks "stori-service/src/utils/test/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportBatchService(t *testing.T) {
+	fixedNow := time.Date(2022, time.June, 30, 10, 0, 0, 0, time.UTC)
+	timeNowBackup := timeNow
+	timeNow = func() time.Time { return fixedNow }
+	repositoryErr := goerrors.New("repository error")
+	importRevert := dto.ImportRevert{RevertedBy: "ops@partner.com", Reason: "Wrong file sent"}
+	firstDates := map[string]time.Time{
+		"MXN": time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC),
+		"USD": time.Date(2022, time.June, 15, 0, 0, 0, 0, time.UTC),
+	}
+	newImportBatch := func() *entity.ImportBatch {
+		return &entity.ImportBatch{
+			ImportBatchID: 7,
+			CustomerID:    1,
+			FileHash:      strings.Repeat("a", 64),
+			TotalRows:     3,
+			ImportedRows:  3,
+			Balances: entity.CurrencyBalances{
+				{Currency: "MXN", IncomeRows: 1, OutcomeRows: 1, TotalIncome: 6050, TotalOutcome: 1030, InitialAvailable: 1000, FinalAvailable: 6020},
+				{Currency: "USD", OutcomeRows: 1, TotalOutcome: 100, InitialAvailable: 200, FinalAvailable: 100},
+			},
+		}
+	}
+	prepareMocks := func(mockImportBatchRepo *customMocks.ClientImportBatchRepository, mockMovementRepo *customMocks.ClientMovementRepository, mockCustomerRepo *customMocks.ClientCustomerRepository) {
+		mockImportBatchRepo.On("Clone").Return(mockImportBatchRepo)
+		mockMovementRepo.On("Clone").Return(mockMovementRepo)
+		mockCustomerRepo.On("Clone").Return(mockCustomerRepo)
+		mockImportBatchRepo.On("Begin", nil).Return(nil)
+		mockMovementRepo.On("Begin", mock.Anything).Return(nil)
+		mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+		mockImportBatchRepo.On("Rollback").Return(nil)
+	}
+	t.Run("Revert", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Reverting an import batch", func(t *testing.T) {
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				mockLedgerService := new(customMocks.ClientLedgerService)
+				sImportBatch := NewImportBatchService(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo, mockBalanceService, mockLedgerService)
+
+				// mock preparation
+				prepareMocks(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo)
+				mockImportBatchRepo.On("FindAndLockByID", 7).Return(newImportBatch(), nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockMovementRepo.On("FindFirstDatesByImportBatchID", 7).Return(firstDates, nil)
+				mockMovementRepo.On("DeleteByImportBatchID", 7).Return(nil)
+				mockLedgerService.On("ReverseImportBatch", nil, 7, fixedNow).Return(nil)
+				mockBalanceService.On("Recalculate", nil, 1, "MXN", firstDates["MXN"]).Return(nil)
+				mockBalanceService.On("Recalculate", nil, 1, "USD", firstDates["USD"]).Return(nil)
+				mockImportBatchRepo.On("Update", mock.AnythingOfType("*entity.ImportBatch")).Return(nil)
+				mockImportBatchRepo.On("Commit").Return(nil)
+
+				// action
+				importBatch, err := sImportBatch.Revert(7, importRevert)
+
+				// mock assertion
+				mockImportBatchRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockCustomerRepo.AssertExpectations(t)
+				mockBalanceService.AssertExpectations(t)
+				mockLedgerService.AssertExpectations(t)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, fixedNow, *importBatch.RevertedAt)
+				assert.Equal(t, importRevert.RevertedBy, *importBatch.RevertedBy)
+				assert.Equal(t, importRevert.Reason, *importBatch.RevertReason)
+			})
+			t.Run("Reverting a batch without movements", func(t *testing.T) {
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				mockLedgerService := new(customMocks.ClientLedgerService)
+				sImportBatch := NewImportBatchService(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo, mockBalanceService, mockLedgerService)
+				importBatch := newImportBatch()
+				importBatch.ImportedRows = 0
+
+				// mock preparation
+				prepareMocks(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo)
+				mockImportBatchRepo.On("FindAndLockByID", 7).Return(importBatch, nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockMovementRepo.On("FindFirstDatesByImportBatchID", 7).Return(map[string]time.Time{}, nil)
+				mockMovementRepo.On("DeleteByImportBatchID", 7).Return(nil)
+				mockLedgerService.On("ReverseImportBatch", nil, 7, fixedNow).Return(nil)
+				mockImportBatchRepo.On("Update", mock.AnythingOfType("*entity.ImportBatch")).Return(nil)
+				mockImportBatchRepo.On("Commit").Return(nil)
+
+				// action
+				_, err := sImportBatch.Revert(7, importRevert)
+
+				// mock assertion
+				mockImportBatchRepo.AssertExpectations(t)
+				mockBalanceService.AssertNumberOfCalls(t, "Recalculate", 0)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Missing reason", func(t *testing.T) {
+				sImportBatch := NewImportBatchService(nil, nil, nil, nil, nil)
+
+				// action
+				importBatch, err := sImportBatch.Revert(7, dto.ImportRevert{RevertedBy: "ops@partner.com"})
+
+				// assertion
+				assert.Nil(t, importBatch)
+				assert.Error(t, err)
+			})
+			t.Run("Batch not found", func(t *testing.T) {
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				mockLedgerService := new(customMocks.ClientLedgerService)
+				sImportBatch := NewImportBatchService(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo, mockBalanceService, mockLedgerService)
+
+				// mock preparation
+				prepareMocks(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo)
+				mockImportBatchRepo.On("FindAndLockByID", 7).Return(nil, errors.ErrNotFound)
+
+				// action
+				importBatch, err := sImportBatch.Revert(7, importRevert)
+
+				// mock assertion
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "DeleteByImportBatchID", 0)
+
+				// assertion
+				assert.Nil(t, importBatch)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+			})
+			t.Run("Batch already reverted", func(t *testing.T) {
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				mockLedgerService := new(customMocks.ClientLedgerService)
+				sImportBatch := NewImportBatchService(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo, mockBalanceService, mockLedgerService)
+				importBatch := newImportBatch()
+				importBatch.RevertedAt = &fixedNow
+
+				// mock preparation
+				prepareMocks(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo)
+				mockImportBatchRepo.On("FindAndLockByID", 7).Return(importBatch, nil)
+
+				// action
+				got, err := sImportBatch.Revert(7, importRevert)
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "DeleteByImportBatchID", 0)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 0)
+
+				// assertion
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrImportBatchReverted.Error())
+			})
+			t.Run("Repository fails on", func(t *testing.T) {
+				testCases := []struct {
+					Name   string
+					Method string
+				}{
+					{Name: "Finding the first dates", Method: "FindFirstDatesByImportBatchID"},
+					{Name: "Deleting the movements", Method: "DeleteByImportBatchID"},
+					{Name: "Reversing the journal entries", Method: "ReverseImportBatch"},
+					{Name: "Recalculating the later balances", Method: "Recalculate"},
+					{Name: "Updating the batch", Method: "Update"},
+					{Name: "Committing", Method: "Commit"},
+				}
+				for _, testCase := range testCases {
+					t.Run(testCase.Name, func(t *testing.T) {
+						mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+						mockMovementRepo := new(customMocks.ClientMovementRepository)
+						mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+						mockBalanceService := new(customMocks.ClientBalanceService)
+						mockLedgerService := new(customMocks.ClientLedgerService)
+						sImportBatch := NewImportBatchService(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo, mockBalanceService, mockLedgerService)
+						errorOn := func(method string) error {
+							if method == testCase.Method {
+								return repositoryErr
+							}
+							return nil
+						}
+
+						// mock preparation
+						prepareMocks(mockImportBatchRepo, mockMovementRepo, mockCustomerRepo)
+						mockImportBatchRepo.On("FindAndLockByID", 7).Return(newImportBatch(), nil)
+						mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+						mockMovementRepo.On("FindFirstDatesByImportBatchID", 7).Return(firstDates, errorOn("FindFirstDatesByImportBatchID"))
+						mockMovementRepo.On("DeleteByImportBatchID", 7).Return(errorOn("DeleteByImportBatchID"))
+						mockLedgerService.On("ReverseImportBatch", nil, 7, fixedNow).Return(errorOn("ReverseImportBatch"))
+						mockBalanceService.On("Recalculate", nil, 1, "MXN", firstDates["MXN"]).Return(errorOn("Recalculate"))
+						mockBalanceService.On("Recalculate", nil, 1, "USD", firstDates["USD"]).Return(nil)
+						mockImportBatchRepo.On("Update", mock.AnythingOfType("*entity.ImportBatch")).Return(errorOn("Update"))
+						mockImportBatchRepo.On("Commit").Return(errorOn("Commit"))
+
+						// action
+						importBatch, err := sImportBatch.Revert(7, importRevert)
+
+						// mock assertion
+						mockImportBatchRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+						// assertion
+						assert.Nil(t, importBatch)
+						assert.EqualError(t, err, repositoryErr.Error())
+					})
+				}
+			})
+		})
+	})
+	timeNow = timeNowBackup
+}
@@ -0,0 +1,46 @@
+package importjob
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/controller"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils/constant"
+	"stori-service/src/utils/helpers"
+)
+
+// struct that implements IImportJobController
+type importJobController struct {
+	controller.ClientController
+	sImportJob interfaces.IImportJobService
+}
+
+/*
+NewImportJobController creates a new controller, receives service by dependency injection
+and returns IImportJobController, so needs to implement all its methods
+*/
+func NewImportJobController(sImportJob interfaces.IImportJobService) interfaces.IImportJobController {
+	return &importJobController{sImportJob: sImportJob}
+}
+
+/*
+FindByID takes the job ID from params and returns the job with its progress,
+the needs-action header is set when the job failed with an error that needs it
+*/
+func (c *importJobController) FindByID(response http.ResponseWriter, request *http.Request) {
+	importJobID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	importJob, err := c.sImportJob.FindByID(importJobID)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	if importJob.Action != nil {
+		response.Header().Set(constant.HeaderNeedsAction, *importJob.Action)
+	}
+
+	c.MakeSuccessResponse(response, importJob, http.StatusOK, i18n.T(i18n.Message{MessageID: "IMPORT_JOB.FOUND"}))
+}
@@ -0,0 +1,122 @@
+package importjob
+
+import (
+	goErrors "errors"
+	"net/http"
+	"net/url"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils"
+	"stori-service/src/utils/constant"
+	"stori-service/src/utils/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportJobController(t *testing.T) {
+	urlvalues := url.Values{}
+	serviceErr := goErrors.New("service error")
+	path := `/{id}`
+	expectedImportJob := &entity.ImportJob{
+		ImportJobID:  1,
+		CustomerID:   1,
+		Status:       constant.ImportJobSucceeded,
+		TotalRows:    49,
+		ImportedRows: 49,
+	}
+	t.Run("FindByID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding a job", func(t *testing.T) {
+				// fixture
+				mockImportJobService := new(mock.ClientImportJobService)
+				importJobController := NewImportJobController(mockImportJobService)
+
+				// mock expectations
+				mockImportJobService.On("FindByID", 1).Return(expectedImportJob, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, importJobController.FindByID, "1", urlvalues, nil)
+
+				//Mock Assertion
+				mockImportJobService.AssertExpectations(t)
+				mockImportJobService.AssertNumberOfCalls(t, "FindByID", 1)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "IMPORT_JOB.FOUND"}), bodyResponse.Message)
+				assert.Empty(t, bodyResponse.Errors)
+				assert.Equal(t, expectedImportJob.Status, result.Status)
+				assert.Equal(t, expectedImportJob.ImportedRows, result.ImportedRows)
+			})
+			t.Run("Finding a job that needs an action", func(t *testing.T) {
+				// fixture
+				mockImportJobService := new(mock.ClientImportJobService)
+				importJobController := NewImportJobController(mockImportJobService)
+				action := "overdraft"
+				failedImportJob := &entity.ImportJob{ImportJobID: 1, CustomerID: 1, Status: constant.ImportJobFailed, Action: &action}
+
+				// mock expectations
+				mockImportJobService.On("FindByID", 1).Return(failedImportJob, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, importJobController.FindByID, "1", urlvalues, nil)
+
+				//Mock Assertion
+				mockImportJobService.AssertExpectations(t)
+
+				result := &entity.ImportJob{}
+				utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, action, resp.Header.Get(constant.HeaderNeedsAction))
+				assert.Equal(t, &action, result.Action)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid id", func(t *testing.T) {
+				// fixture
+				importJobController := NewImportJobController(nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, importJobController.FindByID, "asd", urlvalues, nil)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, errors.GetStatusCode(serviceErr), resp.StatusCode)
+				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Job not found", func(t *testing.T) {
+				// fixture
+				mockImportJobService := new(mock.ClientImportJobService)
+				importJobController := NewImportJobController(mockImportJobService)
+
+				// mock expectations
+				mockImportJobService.On("FindByID", 1).Return(nil, errors.ErrNotFound)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, importJobController.FindByID, "1", urlvalues, nil)
+
+				//Mock Assertion
+				mockImportJobService.AssertExpectations(t)
+				mockImportJobService.AssertNumberOfCalls(t, "FindByID", 1)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrNotFound.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+		})
+	})
+}
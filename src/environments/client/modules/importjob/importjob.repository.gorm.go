@@ -0,0 +1,79 @@
+package importjob
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/client/resources/interfaces"
+	database "stori-service/src/environments/common/resources/database/transaction"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	"stori-service/src/utils/constant"
+
+	"gorm.io/gorm"
+)
+
+/*
+struct that implements IImportJobRepository
+*/
+type importJobGormRepo struct {
+	database.TransactionalGORMRepository
+}
+
+/*
+NewImportJobGormRepo creates a new repo and returns IImportJobRepository,
+so it needs to implement all its methods
+*/
+func NewImportJobGormRepo(gormDb *gorm.DB) interfaces.IImportJobRepository {
+	rImportJob := &importJobGormRepo{}
+	rImportJob.DB = gormDb
+	return rImportJob
+}
+
+/*
+Create receives an import job and creates it, the generated ID is set on the received job
+*/
+func (r *importJobGormRepo) Create(importJob *entity.ImportJob) error {
+	return r.DB.Create(importJob).Error
+}
+
+/*
+Update receives an import job and saves all its fields
+*/
+func (r *importJobGormRepo) Update(importJob *entity.ImportJob) error {
+	return r.DB.Save(importJob).Error
+}
+
+/*
+FindByID returns an import job by its id
+*/
+func (r *importJobGormRepo) FindByID(importJobID int) (*entity.ImportJob, error) {
+	var importJob entity.ImportJob
+	err := r.DB.Where("import_job_id = ?", importJobID).First(&importJob).Error
+	if goerrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &importJob, nil
+}
+
+/*
+FindUnfinished returns the jobs that are pending or running
+*/
+func (r *importJobGormRepo) FindUnfinished() ([]entity.ImportJob, error) {
+	importJobs := []entity.ImportJob{}
+	err := r.DB.Where("status IN ?", []string{constant.ImportJobPending, constant.ImportJobRunning}).
+		Order("import_job_id").
+		Find(&importJobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return importJobs, nil
+}
+
+/*
+Clone returns a new instance of the repository
+*/
+func (r *importJobGormRepo) Clone() interface{} {
+	return NewImportJobGormRepo(r.DB)
+}
@@ -0,0 +1,215 @@
+package importjob
+
+import (
+	"os"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/database"
+	"stori-service/src/libs/errors"
+	"stori-service/src/utils/constant"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// setup
+	database.SetupStoriGormDB()
+	code := m.Run()
+	os.Exit(code)
+}
+
+var importJobs = []entity.ImportJob{
+	{
+		ImportJobID: 1,
+		CustomerID:  1,
+		Status:      constant.ImportJobPending,
+	},
+	{
+		ImportJobID:  2,
+		CustomerID:   1,
+		Status:       constant.ImportJobSucceeded,
+		TotalRows:    10,
+		ImportedRows: 10,
+	},
+}
+
+/*
+	Fixtures: two import jobs
+*/
+func addFixtures(tx *gorm.DB) {
+	tx.Unscoped().Where("1=1").Delete(&entity.ImportJob{}) // cleaning jobs
+	tx.Create(importJobs)
+}
+
+func TestImportJobRepository(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating a job", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				tx.Unscoped().Where("1=1").Delete(&entity.ImportJob{}) // cleaning the table
+				rImportJob := NewImportJobGormRepo(tx)
+				importJob := &entity.ImportJob{CustomerID: 1, Status: constant.ImportJobPending}
+
+				err := rImportJob.Create(importJob)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.NotZero(t, importJob.ImportJobID)
+
+				// database assertion
+				var count int64
+				tx.Model(&entity.ImportJob{}).Count(&count)
+				assert.Equal(t, int64(1), count)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exists", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rImportJob := NewImportJobGormRepo(tx)
+				tx.Migrator().DropTable(&entity.ImportJob{})
+
+				err := rImportJob.Create(&entity.ImportJob{CustomerID: 1, Status: constant.ImportJobPending})
+
+				// data assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Update", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Updating a job", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportJob := NewImportJobGormRepo(tx)
+				importJob := importJobs[0]
+				importJob.Status = constant.ImportJobFailed
+				message := "Invalid file line"
+				importJob.Error = &message
+
+				err := rImportJob.Update(&importJob)
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var got entity.ImportJob
+				tx.First(&got, importJob.ImportJobID)
+				assert.Equal(t, constant.ImportJobFailed, got.Status)
+				assert.Equal(t, message, *got.Error)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindByID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding a job", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportJob := NewImportJobGormRepo(tx)
+
+				got, err := rImportJob.FindByID(importJobs[1].ImportJobID)
+
+				assert.NoError(t, err)
+				assert.True(t, cmp.Equal(got, &importJobs[1], cmpopts.IgnoreTypes(time.Time{}, gorm.DeletedAt{})))
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Job doesn't exists", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rImportJob := NewImportJobGormRepo(tx)
+
+				got, err := rImportJob.FindByID(78)
+
+				assert.Nil(t, got)
+				assert.EqualError(t, err, errors.ErrNotFound.Error())
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rImportJob := NewImportJobGormRepo(tx)
+				tx.Migrator().DropTable(&entity.ImportJob{})
+
+				got, err := rImportJob.FindByID(1)
+
+				assert.Nil(t, got)
+				assert.Error(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindUnfinished", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the pending and running jobs", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				tx.Create(&entity.ImportJob{ImportJobID: 3, CustomerID: 1, Status: constant.ImportJobRunning})
+				rImportJob := NewImportJobGormRepo(tx)
+
+				got, err := rImportJob.FindUnfinished()
+
+				assert.NoError(t, err)
+				assert.Len(t, got, 2)
+				assert.Equal(t, 1, got[0].ImportJobID)
+				assert.Equal(t, 3, got[1].ImportJobID)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rImportJob := NewImportJobGormRepo(tx)
+				tx.Migrator().DropTable(&entity.ImportJob{})
+
+				got, err := rImportJob.FindUnfinished()
+
+				assert.Nil(t, got)
+				assert.Error(t, err)
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Clone", func(t *testing.T) {
+		db := database.GetStoriGormConnection()
+		rImportJob := NewImportJobGormRepo(db)
+
+		clone := rImportJob.Clone()
+
+		assert.NotNil(t, clone)
+		assert.Equal(t, rImportJob, clone)
+	})
+}
@@ -0,0 +1,33 @@
+package importjob
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/utils/helpers"
+
+	"github.com/gorilla/mux"
+)
+
+type importJobRouter struct {
+	cImportJob interfaces.IImportJobController
+}
+
+/*
+NewImportJobRouter receives the controller and calls all functions for route versions
+*/
+func NewImportJobRouter(subRouter *mux.Router, cImportJob interfaces.IImportJobController) {
+	routerImportJob := importJobRouter{cImportJob}
+	routerImportJob.routes(subRouter)
+}
+
+/*
+routes assigns controller function for routes
+*/
+func (r *importJobRouter) routes(subRouter *mux.Router) {
+	subRouter.
+		Path(`/{id}`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cImportJob.FindByID),
+		)).
+		Methods(http.MethodGet)
+}
@@ -0,0 +1,160 @@
+package importjob
+
+import (
+	"runtime/debug"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/logger"
+	"stori-service/src/utils/constant"
+	"time"
+)
+
+var (
+	timeNow = time.Now // declared here for easy testing with spy
+)
+
+/*
+importTask is a queued job with the function that runs the import
+*/
+type importTask struct {
+	job *entity.ImportJob
+	run interfaces.ImportFunc
+}
+
+/*
+Struct that implements IImportJobService
+*/
+type importJobService struct {
+	rImportJob interfaces.IImportJobRepository
+	tasks      chan importTask
+}
+
+/*
+	NewImportJobService creates a new service, receives repository by dependency injection,
+	starts the pool of workers that process the queue and returns IImportJobService
+*/
+func NewImportJobService(rImportJob interfaces.IImportJobRepository, workers, queueSize int) interfaces.IImportJobService {
+	s := &importJobService{
+		rImportJob: rImportJob,
+		tasks:      make(chan importTask, queueSize),
+	}
+	for i := 0; i < workers; i++ {
+		go s.work()
+	}
+	return s
+}
+
+/*
+Enqueue creates a pending job for the customer and queues the import to be run by a worker
+*/
+func (s *importJobService) Enqueue(customerID int, run interfaces.ImportFunc) (*entity.ImportJob, error) {
+	importJob := &entity.ImportJob{
+		CustomerID: customerID,
+		Status:     constant.ImportJobPending,
+	}
+	if err := s.rImportJob.Create(importJob); err != nil {
+		return nil, err
+	}
+	select {
+	case s.tasks <- importTask{job: importJob, run: run}:
+		return importJob, nil
+	default:
+		s.fail(importJob, errors.ErrImportQueueFull)
+		return nil, errors.ErrImportQueueFull
+	}
+}
+
+/*
+FindByID returns the job with its current status
+*/
+func (s *importJobService) FindByID(importJobID int) (*entity.ImportJob, error) {
+	return s.rImportJob.FindByID(importJobID)
+}
+
+/*
+FailUnfinished marks as failed the jobs that were pending or running when the service stopped, the queue only lives
+in memory so they can't be resumed and the file has to be imported again. It must be called on startup before any job
+is queued, and only works with one instance of the service, other instances would lose the jobs they are running
+*/
+func (s *importJobService) FailUnfinished() error {
+	importJobs, err := s.rImportJob.FindUnfinished()
+	if err != nil {
+		return err
+	}
+	for index := range importJobs {
+		logger.GetInstance().Warnf("Import job %d was interrupted while %s", importJobs[index].ImportJobID, importJobs[index].Status)
+		s.fail(&importJobs[index], errors.ErrImportInterrupted)
+	}
+	return nil
+}
+
+/*
+work takes the queued tasks one by one until the queue is closed
+*/
+func (s *importJobService) work() {
+	for task := range s.tasks {
+		s.runTask(task)
+	}
+}
+
+/*
+runTask marks the job as running, runs the import and saves the result and the rejected lines on the job.
+A panic of the import fails the job instead of stopping the worker and the service
+*/
+func (s *importJobService) runTask(task importTask) {
+	importJob := task.job
+	defer func() {
+		if r := recover(); r != nil {
+			logger.GetInstance().Errorf("Import job %d panicked: %v\n%s", importJob.ImportJobID, r, debug.Stack())
+			s.fail(importJob, errors.ErrInternalServer)
+		}
+	}()
+	startedAt := timeNow()
+	importJob.Status = constant.ImportJobRunning
+	importJob.StartedAt = &startedAt
+	s.save(importJob)
+
+	movementList, err := task.run()
+	if movementList != nil && movementList.Summary != nil {
+		// the report of rejected lines is saved even when the import fails
+		importJob.TotalRows = movementList.Summary.TotalRows
+		importJob.RejectedRows = movementList.Summary.RejectedRows
+		importJob.RejectedLines = movementList.Rejected
+	}
+	if err != nil {
+		logger.GetInstance().Errorf("Import job %d failed: %s", importJob.ImportJobID, err)
+		s.fail(importJob, err)
+		return
+	}
+	finishedAt := timeNow()
+	importJob.Status = constant.ImportJobSucceeded
+	importJob.ImportedRows = movementList.Summary.ImportedRows
+	if movementList.ImportBatch != nil {
+		importJob.ImportBatchID = &movementList.ImportBatch.ImportBatchID
+	}
+	importJob.FinishedAt = &finishedAt
+	s.save(importJob)
+}
+
+/*
+fail marks the job as failed with the error message and its needs-action slug, internal errors are not exposed
+*/
+func (s *importJobService) fail(importJob *entity.ImportJob, err error) {
+	finishedAt := timeNow()
+	message := errors.GetErrorMessage(err)
+	importJob.Status = constant.ImportJobFailed
+	importJob.Error = &message
+	importJob.Action = errors.GetAction(err)
+	importJob.FinishedAt = &finishedAt
+	s.save(importJob)
+}
+
+/*
+save updates the job, there is no one to return the error to so it's logged
+*/
+func (s *importJobService) save(importJob *entity.ImportJob) {
+	if err := s.rImportJob.Update(importJob); err != nil {
+		logger.GetInstance().Errorf("Error saving import job %d: %s", importJob.ImportJobID, err)
+	}
+}
@@ -0,0 +1,342 @@
+package importjob
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/utils/constant"
+	customMocks "stori-service/src/utils/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportJobService(t *testing.T) {
+	fixedNow := time.Date(2022, time.June, 30, 10, 0, 0, 0, time.UTC)
+	timeNowBackup := timeNow
+	timeNow = func() time.Time { return fixedNow }
+	movementList := &dto.MovementList{
+		Customer:    &entity.Customer{CustomerID: 1},
+		ImportBatch: &entity.ImportBatch{ImportBatchID: 7},
+		Summary:     &dto.ImportSummary{TotalRows: 2, ImportedRows: 2},
+	}
+	t.Run("Enqueue", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Queueing a job", func(t *testing.T) {
+				mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+				sImportJob := NewImportJobService(mockImportJobRepo, 0, 1).(*importJobService)
+
+				// mock preparation
+				mockImportJobRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Return(nil).
+					Run(func(args mock.Arguments) {
+						args.Get(0).(*entity.ImportJob).ImportJobID = 7
+					})
+
+				// action
+				importJob, err := sImportJob.Enqueue(1, func() (*dto.MovementList, error) { return movementList, nil })
+
+				// mock assertion
+				mockImportJobRepo.AssertExpectations(t)
+				mockImportJobRepo.AssertNumberOfCalls(t, "Create", 1)
+				mockImportJobRepo.AssertNumberOfCalls(t, "Update", 0)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 7, importJob.ImportJobID)
+				assert.Equal(t, 1, importJob.CustomerID)
+				assert.Equal(t, constant.ImportJobPending, importJob.Status)
+				assert.Len(t, sImportJob.tasks, 1)
+			})
+			t.Run("Processing the job with a worker", func(t *testing.T) {
+				mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+				sImportJob := NewImportJobService(mockImportJobRepo, 1, 1)
+				done := make(chan bool)
+
+				// mock preparation
+				mockImportJobRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Return(nil)
+				mockImportJobRepo.On("Update", mock.AnythingOfType("*entity.ImportJob")).Return(nil).
+					Run(func(args mock.Arguments) {
+						if args.Get(0).(*entity.ImportJob).Status == constant.ImportJobSucceeded {
+							done <- true
+						}
+					})
+
+				// action
+				importJob, err := sImportJob.Enqueue(1, func() (*dto.MovementList, error) { return movementList, nil })
+				select {
+				case <-done:
+				case <-time.After(3 * time.Second):
+					t.Error("Test timeout")
+				}
+
+				// mock assertion
+				mockImportJobRepo.AssertExpectations(t)
+				mockImportJobRepo.AssertNumberOfCalls(t, "Update", 2)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, constant.ImportJobSucceeded, importJob.Status)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository fails on Create", func(t *testing.T) {
+				mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+				sImportJob := NewImportJobService(mockImportJobRepo, 0, 1)
+
+				// mock preparation
+				mockImportJobRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Return(goerrors.New("repository error"))
+
+				// action
+				importJob, err := sImportJob.Enqueue(1, func() (*dto.MovementList, error) { return movementList, nil })
+
+				// mock assertion
+				mockImportJobRepo.AssertExpectations(t)
+				mockImportJobRepo.AssertNumberOfCalls(t, "Update", 0)
+
+				// assertion
+				assert.Error(t, err)
+				assert.Nil(t, importJob)
+			})
+			t.Run("Queue is full", func(t *testing.T) {
+				var failedJob *entity.ImportJob
+				mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+				sImportJob := NewImportJobService(mockImportJobRepo, 0, 0)
+
+				// mock preparation
+				mockImportJobRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Return(nil)
+				mockImportJobRepo.On("Update", mock.AnythingOfType("*entity.ImportJob")).Return(nil).
+					Run(func(args mock.Arguments) {
+						failedJob = args.Get(0).(*entity.ImportJob)
+					})
+
+				// action
+				importJob, err := sImportJob.Enqueue(1, func() (*dto.MovementList, error) { return movementList, nil })
+
+				// mock assertion
+				mockImportJobRepo.AssertExpectations(t)
+				mockImportJobRepo.AssertNumberOfCalls(t, "Update", 1)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrImportQueueFull)
+				assert.Nil(t, importJob)
+				assert.Equal(t, constant.ImportJobFailed, failedJob.Status)
+			})
+		})
+	})
+	t.Run("runTask", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Running an import", func(t *testing.T) {
+				mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+				sImportJob := &importJobService{rImportJob: mockImportJobRepo}
+				importJob := &entity.ImportJob{ImportJobID: 1, CustomerID: 1, Status: constant.ImportJobPending}
+				var statuses []string
+
+				// mock preparation
+				mockImportJobRepo.On("Update", importJob).Return(nil).
+					Run(func(args mock.Arguments) {
+						statuses = append(statuses, args.Get(0).(*entity.ImportJob).Status)
+					})
+
+				// action
+				sImportJob.runTask(importTask{job: importJob, run: func() (*dto.MovementList, error) { return movementList, nil }})
+
+				// mock assertion
+				mockImportJobRepo.AssertExpectations(t)
+				mockImportJobRepo.AssertNumberOfCalls(t, "Update", 2)
+
+				// assertion
+				assert.Equal(t, []string{constant.ImportJobRunning, constant.ImportJobSucceeded}, statuses)
+				assert.Equal(t, 2, importJob.TotalRows)
+				assert.Equal(t, 2, importJob.ImportedRows)
+				assert.Equal(t, 7, *importJob.ImportBatchID)
+				assert.Equal(t, fixedNow, *importJob.StartedAt)
+				assert.Equal(t, fixedNow, *importJob.FinishedAt)
+				assert.Nil(t, importJob.Error)
+			})
+			t.Run("Running an import with rejected lines", func(t *testing.T) {
+				mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+				sImportJob := &importJobService{rImportJob: mockImportJobRepo}
+				importJob := &entity.ImportJob{ImportJobID: 1, CustomerID: 1, Status: constant.ImportJobPending}
+				partialList := &dto.MovementList{
+					Rejected: entity.ImportLineErrors{{Line: 3, Column: "date", Reason: "Invalid date"}},
+					Summary:  &dto.ImportSummary{TotalRows: 3, ImportedRows: 2, RejectedRows: 1},
+				}
+
+				// mock preparation
+				mockImportJobRepo.On("Update", importJob).Return(nil)
+
+				// action
+				sImportJob.runTask(importTask{job: importJob, run: func() (*dto.MovementList, error) { return partialList, nil }})
+
+				// mock assertion
+				mockImportJobRepo.AssertExpectations(t)
+				mockImportJobRepo.AssertNumberOfCalls(t, "Update", 2)
+
+				// assertion
+				assert.Equal(t, constant.ImportJobSucceeded, importJob.Status)
+				assert.Equal(t, 3, importJob.TotalRows)
+				assert.Equal(t, 2, importJob.ImportedRows)
+				assert.Equal(t, 1, importJob.RejectedRows)
+				assert.Equal(t, partialList.Rejected, importJob.RejectedLines)
+				assert.Nil(t, importJob.Error)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			testCases := []struct {
+				name     string
+				result   *dto.MovementList
+				err      error
+				expected string
+				action   *string
+				rejected int
+			}{
+				{
+					name:     "Import fails with a known error",
+					err:      errors.ErrDuplicatedID,
+					expected: errors.ErrDuplicatedID.Error(),
+				},
+				{
+					name: "Import fails with invalid lines",
+					result: &dto.MovementList{
+						Rejected: entity.ImportLineErrors{{Line: 3, Column: "date", Reason: "Invalid date"}},
+						Summary:  &dto.ImportSummary{TotalRows: 2, RejectedRows: 1},
+					},
+					err:      errors.ErrInvalidFileLines.WithTemplate(map[string]interface{}{"Count": 1}),
+					expected: errors.ErrInvalidFileLines.WithTemplate(map[string]interface{}{"Count": 1}).Error(),
+					rejected: 1,
+				},
+				{
+					name:     "Import fails with an error that needs an action",
+					err:      errors.ErrOverdraft,
+					expected: errors.ErrOverdraft.Error(),
+					action:   errors.GetAction(errors.ErrOverdraft),
+				},
+				{
+					name:     "Import fails with an internal error",
+					err:      goerrors.New("pq: connection refused"),
+					expected: errors.ErrInternalServer.Error(),
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+					sImportJob := &importJobService{rImportJob: mockImportJobRepo}
+					importJob := &entity.ImportJob{ImportJobID: 1, CustomerID: 1, Status: constant.ImportJobPending}
+
+					// mock preparation
+					mockImportJobRepo.On("Update", importJob).Return(nil)
+
+					// action
+					sImportJob.runTask(importTask{job: importJob, run: func() (*dto.MovementList, error) { return tC.result, tC.err }})
+
+					// mock assertion
+					mockImportJobRepo.AssertExpectations(t)
+					mockImportJobRepo.AssertNumberOfCalls(t, "Update", 2)
+
+					// assertion
+					assert.Equal(t, constant.ImportJobFailed, importJob.Status)
+					assert.Equal(t, tC.expected, *importJob.Error)
+					assert.Equal(t, tC.action, importJob.Action)
+					assert.Equal(t, 0, importJob.ImportedRows)
+					assert.Equal(t, tC.rejected, importJob.RejectedRows)
+					assert.Equal(t, tC.rejected, len(importJob.RejectedLines))
+					assert.NotNil(t, importJob.FinishedAt)
+				})
+			}
+			t.Run("Import panics", func(t *testing.T) {
+				mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+				sImportJob := &importJobService{rImportJob: mockImportJobRepo}
+				importJob := &entity.ImportJob{ImportJobID: 1, CustomerID: 1, Status: constant.ImportJobPending}
+
+				// mock preparation
+				mockImportJobRepo.On("Update", importJob).Return(nil)
+
+				// action
+				assert.NotPanics(t, func() {
+					sImportJob.runTask(importTask{job: importJob, run: func() (*dto.MovementList, error) { panic("malformed file") }})
+				})
+
+				// mock assertion
+				mockImportJobRepo.AssertNumberOfCalls(t, "Update", 2)
+
+				// assertion
+				assert.Equal(t, constant.ImportJobFailed, importJob.Status)
+				assert.Equal(t, errors.ErrInternalServer.Error(), *importJob.Error)
+				assert.NotNil(t, importJob.FinishedAt)
+			})
+		})
+	})
+	t.Run("FailUnfinished", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+			sImportJob := NewImportJobService(mockImportJobRepo, 0, 1)
+			unfinished := []entity.ImportJob{
+				{ImportJobID: 1, Status: constant.ImportJobPending},
+				{ImportJobID: 2, Status: constant.ImportJobRunning},
+			}
+			var failed []int
+
+			// mock preparation
+			mockImportJobRepo.On("FindUnfinished").Return(unfinished, nil)
+			mockImportJobRepo.On("Update", mock.AnythingOfType("*entity.ImportJob")).Return(nil).
+				Run(func(args mock.Arguments) {
+					importJob := args.Get(0).(*entity.ImportJob)
+					assert.Equal(t, constant.ImportJobFailed, importJob.Status)
+					assert.Equal(t, errors.ErrImportInterrupted.Error(), *importJob.Error)
+					failed = append(failed, importJob.ImportJobID)
+				})
+
+			// action
+			err := sImportJob.FailUnfinished()
+
+			// mock assertion
+			mockImportJobRepo.AssertExpectations(t)
+
+			// assertion
+			assert.NoError(t, err)
+			assert.Equal(t, []int{1, 2}, failed)
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+			sImportJob := NewImportJobService(mockImportJobRepo, 0, 1)
+			repositoryErr := goerrors.New("repository error")
+			mockImportJobRepo.On("FindUnfinished").Return(nil, repositoryErr)
+
+			err := sImportJob.FailUnfinished()
+
+			mockImportJobRepo.AssertNumberOfCalls(t, "Update", 0)
+			assert.EqualError(t, err, repositoryErr.Error())
+		})
+	})
+	t.Run("FindByID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+			sImportJob := NewImportJobService(mockImportJobRepo, 0, 1)
+			expected := &entity.ImportJob{ImportJobID: 1}
+			mockImportJobRepo.On("FindByID", 1).Return(expected, nil)
+
+			importJob, err := sImportJob.FindByID(1)
+
+			mockImportJobRepo.AssertExpectations(t)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, importJob)
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			mockImportJobRepo := new(customMocks.ClientImportJobRepository)
+			sImportJob := NewImportJobService(mockImportJobRepo, 0, 1)
+			mockImportJobRepo.On("FindByID", 1).Return(nil, errors.ErrNotFound)
+
+			importJob, err := sImportJob.FindByID(1)
+
+			mockImportJobRepo.AssertExpectations(t)
+			assert.ErrorIs(t, err, errors.ErrNotFound)
+			assert.Nil(t, importJob)
+		})
+	})
+	t.Cleanup(func() {
+		timeNow = timeNowBackup
+	})
+}
@@ -0,0 +1,35 @@
+package ledger
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/controller"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/libs/i18n"
+)
+
+// struct that implements ILedgerController
+type ledgerController struct {
+	controller.ClientController
+	sLedger interfaces.ILedgerService
+}
+
+/*
+NewLedgerController creates a new controller, receives service by dependency injection
+and returns ILedgerController, so needs to implement all its methods
+*/
+func NewLedgerController(sLedger interfaces.ILedgerService) interfaces.ILedgerController {
+	return &ledgerController{sLedger: sLedger}
+}
+
+/*
+TrialBalance returns the debits and credits of the ledger accounts and if they balance, to reconcile against the bank
+*/
+func (c *ledgerController) TrialBalance(response http.ResponseWriter, request *http.Request) {
+	trialBalance, err := c.sLedger.TrialBalance()
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeSuccessResponse(response, trialBalance, http.StatusOK, i18n.T(i18n.Message{MessageID: "LEDGER.TRIAL_BALANCE"}))
+}
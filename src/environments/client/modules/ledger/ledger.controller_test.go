@@ -0,0 +1,81 @@
+package ledger
+
+import (
+	goErrors "errors"
+	"net/http"
+	"net/url"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/utils"
+	"stori-service/src/utils/constant"
+	"stori-service/src/utils/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerController(t *testing.T) {
+	urlvalues := url.Values{}
+	serviceErr := goErrors.New("service error")
+	path := `/trial-balance`
+	expectedTrialBalance := &dto.TrialBalance{
+		Balanced: true,
+		Currencies: []dto.CurrencyTrialBalance{
+			{Currency: "MXN", Debit: 350, Credit: 350, Balanced: true},
+		},
+		Accounts: []dto.AccountBalance{
+			{AccountID: 2, Code: "CLEARING-MXN", Type: constant.ClearingAccount, Currency: "MXN", Debit: 350, Balance: 350},
+			{AccountID: 1, Code: "CUSTOMER-1-MXN", Type: constant.CustomerAccount, Currency: "MXN", Credit: 350, Balance: -350},
+		},
+	}
+	t.Run("TrialBalance", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Getting the trial balance", func(t *testing.T) {
+				// fixture
+				mockLedgerService := new(mock.ClientLedgerService)
+				ledgerController := NewLedgerController(mockLedgerService)
+
+				// mock expectations
+				mockLedgerService.On("TrialBalance").Return(expectedTrialBalance, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, ledgerController.TrialBalance, "trial-balance", urlvalues, nil)
+
+				//Mock Assertion
+				mockLedgerService.AssertExpectations(t)
+				mockLedgerService.AssertNumberOfCalls(t, "TrialBalance", 1)
+
+				result := &dto.TrialBalance{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "LEDGER.TRIAL_BALANCE"}), bodyResponse.Message)
+				assert.Empty(t, bodyResponse.Errors)
+				assert.Equal(t, expectedTrialBalance, result)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Service error", func(t *testing.T) {
+				// fixture
+				mockLedgerService := new(mock.ClientLedgerService)
+				ledgerController := NewLedgerController(mockLedgerService)
+
+				// mock expectations
+				mockLedgerService.On("TrialBalance").Return(nil, serviceErr)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, path, ledgerController.TrialBalance, "trial-balance", urlvalues, nil)
+
+				result := &dto.TrialBalance{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, errors.GetStatusCode(serviceErr), resp.StatusCode)
+				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package ledger
+
+import (
+	"stori-service/src/environments/client/resources/interfaces"
+	database "stori-service/src/environments/common/resources/database/transaction"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+struct that implements ILedgerRepository
+*/
+type ledgerGormRepo struct {
+	database.TransactionalGORMRepository
+}
+
+/*
+NewLedgerGormRepo creates a new repo and returns ILedgerRepository,
+so it needs to implement all its methods
+*/
+func NewLedgerGormRepo(gormDb *gorm.DB) interfaces.ILedgerRepository {
+	rLedger := &ledgerGormRepo{}
+	rLedger.DB = gormDb
+	return rLedger
+}
+
+// maxInsertRows keeps each INSERT under the PostgreSQL limit of 65535 parameters
+const maxInsertRows = 1000
+
+/*
+UpsertAccount creates the account when there isn't one with its code and sets its ID,
+so concurrent imports can share the clearing accounts
+*/
+func (r *ledgerGormRepo) UpsertAccount(account *entity.Account) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at"}),
+	}).Create(account).Error
+}
+
+/*
+CreateEntries creates the journal entries with their postings, with one INSERT each maxInsertRows rows
+*/
+func (r *ledgerGormRepo) CreateEntries(journalEntries []entity.JournalEntry) error {
+	if len(journalEntries) == 0 {
+		return nil
+	}
+	return r.DB.CreateInBatches(&journalEntries, maxInsertRows).Error
+}
+
+/*
+FindEntriesByImportBatchID returns the journal entries of the import batch with their postings, without the reversals
+*/
+func (r *ledgerGormRepo) FindEntriesByImportBatchID(importBatchID int) ([]entity.JournalEntry, error) {
+	var journalEntries []entity.JournalEntry
+	err := r.DB.Preload("Postings").
+		Where("import_batch_id = ? AND reversal_of_id IS NULL", importBatchID).
+		Order("journal_entry_id").
+		Find(&journalEntries).Error
+	if err != nil {
+		return nil, err
+	}
+	return journalEntries, nil
+}
+
+/*
+FindAccountBalances returns the debits and credits posted to each account, sorted by currency and code
+*/
+func (r *ledgerGormRepo) FindAccountBalances() ([]dto.AccountBalance, error) {
+	var accountBalances []dto.AccountBalance
+	err := r.DB.Model(&entity.Account{}).
+		Select(`account.account_id, account.code, account.type, account.customer_id, account.currency,
+			COALESCE(SUM(posting.amount) FILTER (WHERE posting.amount > 0), 0) AS debit,
+			COALESCE(-SUM(posting.amount) FILTER (WHERE posting.amount < 0), 0) AS credit`).
+		Joins("JOIN posting ON posting.account_id = account.account_id").
+		Group("account.account_id").
+		Order("account.currency, account.code").
+		Scan(&accountBalances).Error
+	if err != nil {
+		return nil, err
+	}
+	return accountBalances, nil
+}
+
+/*
+Clone returns a new instance of the repository
+*/
+func (r *ledgerGormRepo) Clone() interface{} {
+	return NewLedgerGormRepo(r.DB)
+}
@@ -0,0 +1,317 @@
+package ledger
+
+import (
+	"os"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/database"
+	"stori-service/src/libs/dto"
+	"stori-service/src/utils/constant"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// setup
+	database.SetupStoriGormDB()
+	code := m.Run()
+	os.Exit(code)
+}
+
+var (
+	date          = time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+	customerID    = 1
+	importBatchID = 1
+)
+
+var accounts = []entity.Account{
+	{AccountID: 1, Code: "CUSTOMER-1-MXN", Type: constant.CustomerAccount, CustomerID: &customerID, Currency: "MXN"},
+	{AccountID: 2, Code: "CLEARING-MXN", Type: constant.ClearingAccount, Currency: "MXN"},
+}
+
+var journalEntries = []entity.JournalEntry{
+	{
+		JournalEntryID: 1, ImportBatchID: &importBatchID, Date: date, Description: "Movement 1 of default",
+		Postings: []entity.Posting{{AccountID: 1, Amount: -1000}, {AccountID: 2, Amount: 1000}},
+	},
+	{
+		JournalEntryID: 2, ImportBatchID: &importBatchID, Date: date, Description: "Movement 2 of default",
+		Postings: []entity.Posting{{AccountID: 1, Amount: 500}, {AccountID: 2, Amount: -500}},
+	},
+	{
+		JournalEntryID: 3, Date: date, Description: "Movement 3 of latam",
+		Postings: []entity.Posting{{AccountID: 1, Amount: -200}, {AccountID: 2, Amount: 200}},
+	},
+}
+
+/*
+	Fixtures: the peso accounts of the customer 1 with two entries of the import batch 1 and one without batch
+*/
+func addFixtures(tx *gorm.DB) {
+	tx.Where("1=1").Delete(&entity.Posting{}) // cleaning postings
+	tx.Where("1=1").Delete(&entity.JournalEntry{})
+	tx.Where("1=1").Delete(&entity.Account{})
+	tx.Model(&entity.Movement{}).Where("import_batch_id IS NOT NULL").Update("import_batch_id", nil)
+	tx.Unscoped().Where("1=1").Delete(&entity.ImportBatch{})
+	tx.Create(&entity.ImportBatch{ImportBatchID: importBatchID, CustomerID: customerID, FileHash: strings.Repeat("a", 64), StartedAt: date})
+	tx.Create(accounts)
+	tx.Create(journalEntries)
+	tx.Exec("SELECT setval('account_account_id_seq', MAX(account_id)) FROM account") // the fixtures have their ids
+	tx.Exec("SELECT setval('journal_entry_journal_entry_id_seq', MAX(journal_entry_id)) FROM journal_entry")
+}
+
+func TestLedgerRepository(t *testing.T) {
+	t.Run("UpsertAccount", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating a new account", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rLedger := NewLedgerGormRepo(tx)
+				account := entity.NewClearingAccount("USD")
+
+				err := rLedger.UpsertAccount(&account)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Greater(t, account.AccountID, 2)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Getting the ID of an existing account", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rLedger := NewLedgerGormRepo(tx)
+				account := entity.NewCustomerAccount(1, "MXN")
+
+				err := rLedger.UpsertAccount(&account)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 1, account.AccountID)
+
+				// database assertion
+				var accountCount int64
+				tx.Model(&entity.Account{}).Count(&accountCount)
+				assert.Equal(t, int64(len(accounts)), accountCount)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rLedger := NewLedgerGormRepo(tx)
+				tx.Exec("DROP TABLE account CASCADE")
+				account := entity.NewClearingAccount("USD")
+
+				err := rLedger.UpsertAccount(&account)
+
+				// data assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("CreateEntries", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Creating the entries with their postings", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rLedger := NewLedgerGormRepo(tx)
+
+				err := rLedger.CreateEntries([]entity.JournalEntry{
+					{Date: date, Postings: []entity.Posting{{AccountID: 1, Amount: -300}, {AccountID: 2, Amount: 300}}},
+				})
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var postingCount int64
+				tx.Model(&entity.Posting{}).Count(&postingCount)
+				assert.Equal(t, int64(8), postingCount)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Unbalanced entry", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rLedger := NewLedgerGormRepo(tx)
+
+				err := rLedger.CreateEntries([]entity.JournalEntry{
+					{Date: date, Postings: []entity.Posting{{AccountID: 1, Amount: -300}, {AccountID: 2, Amount: 299}}},
+				})
+
+				// data assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Entry unbalanced in one of its currencies", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				tx.Create(&entity.Account{AccountID: 3, Code: "CLEARING-USD", Type: constant.ClearingAccount, Currency: "USD"})
+				rLedger := NewLedgerGormRepo(tx)
+
+				err := rLedger.CreateEntries([]entity.JournalEntry{
+					{Date: date, Postings: []entity.Posting{{AccountID: 1, Amount: -300}, {AccountID: 3, Amount: 300}}},
+				})
+
+				// data assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Posting updated without the rest of its entry", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+
+				err := tx.Model(&entity.Posting{}).
+					Where("journal_entry_id = ? AND account_id = ?", 1, 1).
+					Update("amount", -999).Error
+
+				// data assertion
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "journal entry 1 does not balance to zero")
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rLedger := NewLedgerGormRepo(tx)
+				tx.Exec("DROP TABLE journal_entry CASCADE")
+
+				err := rLedger.CreateEntries(journalEntries)
+
+				// data assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindEntriesByImportBatchID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the entries of the batch", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				tx.Create(&entity.JournalEntry{
+					ImportBatchID: &importBatchID, ReversalOfID: &journalEntries[0].JournalEntryID, Date: date,
+					Postings: []entity.Posting{{AccountID: 1, Amount: 1000}, {AccountID: 2, Amount: -1000}},
+				})
+				rLedger := NewLedgerGormRepo(tx)
+
+				got, err := rLedger.FindEntriesByImportBatchID(importBatchID)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Len(t, got, 2) // without the reversal
+				assert.Equal(t, 1, got[0].JournalEntryID)
+				assert.Len(t, got[0].Postings, 2)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rLedger := NewLedgerGormRepo(tx)
+				tx.Exec("DROP TABLE journal_entry CASCADE")
+
+				got, err := rLedger.FindEntriesByImportBatchID(importBatchID)
+
+				// data assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindAccountBalances", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Summing the postings of each account", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addFixtures(tx)
+				rLedger := NewLedgerGormRepo(tx)
+
+				got, err := rLedger.FindAccountBalances()
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Equal(t, []dto.AccountBalance{
+					{AccountID: 2, Code: "CLEARING-MXN", Type: constant.ClearingAccount, Currency: "MXN", Debit: 1200, Credit: 500},
+					{AccountID: 1, Code: "CUSTOMER-1-MXN", Type: constant.CustomerAccount, CustomerID: &customerID, Currency: "MXN", Debit: 500, Credit: 1200},
+				}, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rLedger := NewLedgerGormRepo(tx)
+				tx.Exec("DROP TABLE posting")
+
+				got, err := rLedger.FindAccountBalances()
+
+				// data assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("Clone", func(t *testing.T) {
+		db := database.GetStoriGormConnection()
+		rLedger := NewLedgerGormRepo(db)
+
+		clone := rLedger.Clone()
+
+		assert.NotNil(t, clone)
+		assert.Equal(t, rLedger, clone)
+	})
+}
@@ -0,0 +1,33 @@
+package ledger
+
+import (
+	"net/http"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/utils/helpers"
+
+	"github.com/gorilla/mux"
+)
+
+type ledgerRouter struct {
+	cLedger interfaces.ILedgerController
+}
+
+/*
+NewLedgerRouter receives the controller and calls all functions for route versions
+*/
+func NewLedgerRouter(subRouter *mux.Router, cLedger interfaces.ILedgerController) {
+	routerLedger := ledgerRouter{cLedger}
+	routerLedger.routes(subRouter)
+}
+
+/*
+routes assigns controller function for routes
+*/
+func (r *ledgerRouter) routes(subRouter *mux.Router) {
+	subRouter.
+		Path(`/trial-balance`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cLedger.TrialBalance),
+		)).
+		Methods(http.MethodGet)
+}
@@ -0,0 +1,177 @@
+package ledger
+
+import (
+	"fmt"
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/money"
+	"time"
+)
+
+/*
+Struct that implements ILedgerService
+*/
+type ledgerService struct {
+	rLedger interfaces.ILedgerRepository
+}
+
+/*
+	NewLedgerService creates a new service, receives repositories by dependency injection
+	and returns ILedgerService, so it needs to implement all its methods
+*/
+func NewLedgerService(rLedger interfaces.ILedgerRepository) interfaces.ILedgerService {
+	return &ledgerService{rLedger}
+}
+
+/*
+PostMovements creates the journal entry of each movement, already saved with its ID, against the account
+of the customer and the clearing account of its currency: incomes credit the customer and debit the clearing
+account and outcomes the other way around. It runs in the transaction received, or in a new one when it's nil
+*/
+func (s *ledgerService) PostMovements(tx interface{}, movements []entity.Movement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+	rLedger := s.rLedger.Clone().(interfaces.ILedgerRepository)
+	rLedger.Begin(tx)
+	if tx == nil {
+		defer rLedger.Rollback()
+	}
+
+	accountIDs := make(map[string]int)
+	journalEntries := make([]entity.JournalEntry, 0, len(movements))
+	for index := range movements {
+		movement := &movements[index]
+		customerAccountID, err := accountID(rLedger, accountIDs, entity.NewCustomerAccount(movement.CustomerID, movement.Currency))
+		if err != nil {
+			return err
+		}
+		clearingAccountID, err := accountID(rLedger, accountIDs, entity.NewClearingAccount(movement.Currency))
+		if err != nil {
+			return err
+		}
+		amount := movement.Quantity * money.Amount(movement.Type)
+		journalEntries = append(journalEntries, entity.JournalEntry{
+			MovementID:    &movement.MovementID,
+			ImportBatchID: movement.ImportBatchID,
+			Date:          movement.Date,
+			Description:   fmt.Sprintf("Movement %d of %s", movement.ExternalID, movement.Source),
+			Postings: []entity.Posting{
+				{AccountID: customerAccountID, Amount: -amount},
+				{AccountID: clearingAccountID, Amount: amount},
+			},
+		})
+	}
+	err := createEntries(rLedger, journalEntries)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		return rLedger.Commit()
+	}
+	return nil
+}
+
+/*
+ReverseImportBatch creates for each journal entry of the import batch a new one on the date with the opposite
+postings, the original entries are kept. It runs in the transaction received, or in a new one when it's nil
+*/
+func (s *ledgerService) ReverseImportBatch(tx interface{}, importBatchID int, date time.Time) error {
+	rLedger := s.rLedger.Clone().(interfaces.ILedgerRepository)
+	rLedger.Begin(tx)
+	if tx == nil {
+		defer rLedger.Rollback()
+	}
+
+	journalEntries, err := rLedger.FindEntriesByImportBatchID(importBatchID)
+	if err != nil {
+		return err
+	}
+	reversals := make([]entity.JournalEntry, 0, len(journalEntries))
+	for index := range journalEntries {
+		journalEntry := &journalEntries[index]
+		postings := make([]entity.Posting, 0, len(journalEntry.Postings))
+		for _, posting := range journalEntry.Postings {
+			postings = append(postings, entity.Posting{AccountID: posting.AccountID, Amount: -posting.Amount})
+		}
+		reversals = append(reversals, entity.JournalEntry{
+			MovementID:    journalEntry.MovementID,
+			ImportBatchID: journalEntry.ImportBatchID,
+			ReversalOfID:  &journalEntry.JournalEntryID,
+			Date:          date,
+			Description:   "Reversal of " + journalEntry.Description,
+			Postings:      postings,
+		})
+	}
+	err = createEntries(rLedger, reversals)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		return rLedger.Commit()
+	}
+	return nil
+}
+
+/*
+TrialBalance returns the debits and credits of every account and their totals by currency,
+each currency must have the same debits and credits, as every entry balances to zero
+*/
+func (s *ledgerService) TrialBalance() (*dto.TrialBalance, error) {
+	accountBalances, err := s.rLedger.FindAccountBalances()
+	if err != nil {
+		return nil, err
+	}
+	trialBalance := &dto.TrialBalance{
+		Balanced:   true,
+		Currencies: []dto.CurrencyTrialBalance{},
+		Accounts:   []dto.AccountBalance{},
+	}
+	for _, accountBalance := range accountBalances {
+		accountBalance.Balance = accountBalance.Debit - accountBalance.Credit
+		trialBalance.Accounts = append(trialBalance.Accounts, accountBalance)
+		// the accounts are sorted by currency
+		last := len(trialBalance.Currencies) - 1
+		if last < 0 || trialBalance.Currencies[last].Currency != accountBalance.Currency {
+			trialBalance.Currencies = append(trialBalance.Currencies, dto.CurrencyTrialBalance{Currency: accountBalance.Currency})
+			last++
+		}
+		trialBalance.Currencies[last].Debit += accountBalance.Debit
+		trialBalance.Currencies[last].Credit += accountBalance.Credit
+	}
+	for index := range trialBalance.Currencies {
+		currency := &trialBalance.Currencies[index]
+		currency.Balanced = currency.Debit == currency.Credit
+		trialBalance.Balanced = trialBalance.Balanced && currency.Balanced
+	}
+	return trialBalance, nil
+}
+
+/*
+createEntries validates the journal entries and creates them, none is created when one of them is invalid
+or doesn't balance to zero
+*/
+func createEntries(rLedger interfaces.ILedgerRepository, journalEntries []entity.JournalEntry) error {
+	for index := range journalEntries {
+		if err := journalEntries[index].Validate(); err != nil {
+			return err
+		}
+	}
+	return rLedger.CreateEntries(journalEntries)
+}
+
+/*
+accountID returns the ID of the account, creating it the first time its code is found
+*/
+func accountID(rLedger interfaces.ILedgerRepository, accountIDs map[string]int, account entity.Account) (int, error) {
+	if id, ok := accountIDs[account.Code]; ok {
+		return id, nil
+	}
+	err := rLedger.UpsertAccount(&account)
+	if err != nil {
+		return 0, err
+	}
+	accountIDs[account.Code] = account.AccountID
+	return account.AccountID, nil
+}
@@ -0,0 +1,364 @@
+package ledger
+
+import (
+	goerrors "errors"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
+	customMocks "stori-service/src/utils/test/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLedgerService(t *testing.T) {
+	date := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+	repositoryErr := goerrors.New("repository error")
+	transaction := struct{}{}
+	importBatchID := 7
+	accountIDs := map[string]int{"CUSTOMER-1-MXN": 1, "CLEARING-MXN": 2, "CUSTOMER-1-USD": 3, "CLEARING-USD": 4}
+	// the account mock sets the ID of the code as the repository does
+	prepareAccountMock := func(mockLedgerRepo *customMocks.ClientLedgerRepository, err error) {
+		mockLedgerRepo.On("UpsertAccount", mock.AnythingOfType("*entity.Account")).Run(func(args mock.Arguments) {
+			account := args.Get(0).(*entity.Account)
+			account.AccountID = accountIDs[account.Code]
+		}).Return(err)
+	}
+	movements := []entity.Movement{
+		{MovementID: 10, ExternalID: 1, Source: "default", CustomerID: 1, Currency: "MXN", Quantity: 350, Type: constant.IncomeType, Date: date, ImportBatchID: &importBatchID},
+		{MovementID: 11, ExternalID: 2, Source: "default", CustomerID: 1, Currency: "USD", Quantity: 100, Type: constant.OutcomeType, Date: date, ImportBatchID: &importBatchID},
+		{MovementID: 12, ExternalID: 3, Source: "default", CustomerID: 1, Currency: "MXN", Quantity: 160, Type: constant.OutcomeType, Date: date, ImportBatchID: &importBatchID},
+	}
+	movementIDs := []int{10, 11, 12}
+	expectedEntries := []entity.JournalEntry{
+		{
+			MovementID: &movementIDs[0], ImportBatchID: &importBatchID, Date: date, Description: "Movement 1 of default",
+			Postings: []entity.Posting{{AccountID: 1, Amount: -350}, {AccountID: 2, Amount: 350}},
+		},
+		{
+			MovementID: &movementIDs[1], ImportBatchID: &importBatchID, Date: date, Description: "Movement 2 of default",
+			Postings: []entity.Posting{{AccountID: 3, Amount: 100}, {AccountID: 4, Amount: -100}},
+		},
+		{
+			MovementID: &movementIDs[2], ImportBatchID: &importBatchID, Date: date, Description: "Movement 3 of default",
+			Postings: []entity.Posting{{AccountID: 1, Amount: 160}, {AccountID: 2, Amount: -160}},
+		},
+	}
+	t.Run("PostMovements", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Posting movements in the transaction of the caller", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+
+				// mock preparation
+				mockLedgerRepo.On("Clone").Return(mockLedgerRepo)
+				mockLedgerRepo.On("Begin", transaction).Return(transaction)
+				prepareAccountMock(mockLedgerRepo, nil)
+				mockLedgerRepo.On("CreateEntries", expectedEntries).Return(nil)
+
+				// action
+				err := sLedger.PostMovements(transaction, movements)
+
+				// mock assertion
+				mockLedgerRepo.AssertExpectations(t)
+				mockLedgerRepo.AssertNumberOfCalls(t, "UpsertAccount", 4) // each account once
+				mockLedgerRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+			t.Run("Posting movements in a new transaction", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+
+				// mock preparation
+				mockLedgerRepo.On("Clone").Return(mockLedgerRepo)
+				mockLedgerRepo.On("Begin", nil).Return(nil)
+				mockLedgerRepo.On("Rollback").Return(nil)
+				prepareAccountMock(mockLedgerRepo, nil)
+				mockLedgerRepo.On("CreateEntries", expectedEntries).Return(nil)
+				mockLedgerRepo.On("Commit").Return(nil)
+
+				// action
+				err := sLedger.PostMovements(nil, movements)
+
+				// mock assertion
+				mockLedgerRepo.AssertExpectations(t)
+				mockLedgerRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+			t.Run("Without movements", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+
+				// action
+				err := sLedger.PostMovements(transaction, []entity.Movement{})
+
+				// mock assertion
+				mockLedgerRepo.AssertNumberOfCalls(t, "CreateEntries", 0)
+
+				// assertion
+				assert.NoError(t, err)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository fails on", func(t *testing.T) {
+				testCases := []struct {
+					Name   string
+					Method string
+				}{
+					{Name: "Creating the accounts", Method: "UpsertAccount"},
+					{Name: "Creating the entries", Method: "CreateEntries"},
+					{Name: "Committing", Method: "Commit"},
+				}
+				for _, testCase := range testCases {
+					t.Run(testCase.Name, func(t *testing.T) {
+						mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+						sLedger := NewLedgerService(mockLedgerRepo)
+						errorOn := func(method string) error {
+							if method == testCase.Method {
+								return repositoryErr
+							}
+							return nil
+						}
+
+						// mock preparation
+						mockLedgerRepo.On("Clone").Return(mockLedgerRepo)
+						mockLedgerRepo.On("Begin", nil).Return(nil)
+						mockLedgerRepo.On("Rollback").Return(nil)
+						prepareAccountMock(mockLedgerRepo, errorOn("UpsertAccount"))
+						mockLedgerRepo.On("CreateEntries", expectedEntries).Return(errorOn("CreateEntries"))
+						mockLedgerRepo.On("Commit").Return(errorOn("Commit"))
+
+						// action
+						err := sLedger.PostMovements(nil, movements)
+
+						// mock assertion
+						mockLedgerRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+						// assertion
+						assert.EqualError(t, err, repositoryErr.Error())
+					})
+				}
+			})
+			t.Run("Movement without date", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+				undated := []entity.Movement{movements[0], movements[1]}
+				undated[1].Date = time.Time{}
+
+				// mock preparation
+				mockLedgerRepo.On("Clone").Return(mockLedgerRepo)
+				mockLedgerRepo.On("Begin", nil).Return(nil)
+				mockLedgerRepo.On("Rollback").Return(nil)
+				prepareAccountMock(mockLedgerRepo, nil)
+
+				// action
+				err := sLedger.PostMovements(nil, undated)
+
+				// mock assertion
+				mockLedgerRepo.AssertNumberOfCalls(t, "CreateEntries", 0)
+				mockLedgerRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockLedgerRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+				// assertion
+				assert.Error(t, err)
+			})
+		})
+	})
+	t.Run("ReverseImportBatch", func(t *testing.T) {
+		revertedAt := time.Date(2022, time.July, 5, 10, 0, 0, 0, time.UTC)
+		newEntries := func() []entity.JournalEntry {
+			entries := make([]entity.JournalEntry, len(expectedEntries))
+			copy(entries, expectedEntries)
+			for index := range entries {
+				entries[index].JournalEntryID = 100 + index
+			}
+			return entries
+		}
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Reversing the entries of the batch", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+				entries := newEntries()
+
+				// mock preparation
+				mockLedgerRepo.On("Clone").Return(mockLedgerRepo)
+				mockLedgerRepo.On("Begin", transaction).Return(transaction)
+				mockLedgerRepo.On("FindEntriesByImportBatchID", 7).Return(entries, nil)
+				mockLedgerRepo.On("CreateEntries", mock.AnythingOfType("[]entity.JournalEntry")).Return(nil)
+
+				// action
+				err := sLedger.ReverseImportBatch(transaction, 7, revertedAt)
+
+				// mock assertion
+				mockLedgerRepo.AssertExpectations(t)
+				mockLedgerRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.NoError(t, err)
+				reversals := mockLedgerRepo.Calls[3].Arguments.Get(0).([]entity.JournalEntry)
+				assert.Len(t, reversals, 3)
+				assert.Equal(t, entity.JournalEntry{
+					MovementID:    &movementIDs[0],
+					ImportBatchID: &importBatchID,
+					ReversalOfID:  &entries[0].JournalEntryID,
+					Date:          revertedAt,
+					Description:   "Reversal of Movement 1 of default",
+					Postings:      []entity.Posting{{AccountID: 1, Amount: 350}, {AccountID: 2, Amount: -350}},
+				}, reversals[0])
+				assert.Equal(t, 102, *reversals[2].ReversalOfID)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository fails on", func(t *testing.T) {
+				testCases := []struct {
+					Name   string
+					Method string
+				}{
+					{Name: "Finding the entries", Method: "FindEntriesByImportBatchID"},
+					{Name: "Creating the reversals", Method: "CreateEntries"},
+					{Name: "Committing", Method: "Commit"},
+				}
+				for _, testCase := range testCases {
+					t.Run(testCase.Name, func(t *testing.T) {
+						mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+						sLedger := NewLedgerService(mockLedgerRepo)
+						errorOn := func(method string) error {
+							if method == testCase.Method {
+								return repositoryErr
+							}
+							return nil
+						}
+
+						// mock preparation
+						mockLedgerRepo.On("Clone").Return(mockLedgerRepo)
+						mockLedgerRepo.On("Begin", nil).Return(nil)
+						mockLedgerRepo.On("Rollback").Return(nil)
+						mockLedgerRepo.On("FindEntriesByImportBatchID", 7).Return(newEntries(), errorOn("FindEntriesByImportBatchID"))
+						mockLedgerRepo.On("CreateEntries", mock.AnythingOfType("[]entity.JournalEntry")).Return(errorOn("CreateEntries"))
+						mockLedgerRepo.On("Commit").Return(errorOn("Commit"))
+
+						// action
+						err := sLedger.ReverseImportBatch(nil, 7, revertedAt)
+
+						// mock assertion
+						mockLedgerRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+						// assertion
+						assert.EqualError(t, err, repositoryErr.Error())
+					})
+				}
+			})
+			t.Run("Unbalanced entry in the batch", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+				entries := newEntries()
+				entries[1].Postings = []entity.Posting{{AccountID: 3, Amount: 100}, {AccountID: 4, Amount: -90}}
+
+				// mock preparation
+				mockLedgerRepo.On("Clone").Return(mockLedgerRepo)
+				mockLedgerRepo.On("Begin", nil).Return(nil)
+				mockLedgerRepo.On("Rollback").Return(nil)
+				mockLedgerRepo.On("FindEntriesByImportBatchID", 7).Return(entries, nil)
+
+				// action
+				err := sLedger.ReverseImportBatch(nil, 7, revertedAt)
+
+				// mock assertion
+				mockLedgerRepo.AssertNumberOfCalls(t, "CreateEntries", 0)
+				mockLedgerRepo.AssertNumberOfCalls(t, "Rollback", 1)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrUnbalancedEntry)
+			})
+		})
+	})
+	t.Run("TrialBalance", func(t *testing.T) {
+		customerID := 1
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Balanced ledger", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+
+				// mock preparation
+				mockLedgerRepo.On("FindAccountBalances").Return([]dto.AccountBalance{
+					{AccountID: 2, Code: "CLEARING-MXN", Type: constant.ClearingAccount, Currency: "MXN", Debit: 350, Credit: 160},
+					{AccountID: 1, Code: "CUSTOMER-1-MXN", Type: constant.CustomerAccount, CustomerID: &customerID, Currency: "MXN", Debit: 160, Credit: 350},
+					{AccountID: 4, Code: "CLEARING-USD", Type: constant.ClearingAccount, Currency: "USD", Credit: 100},
+					{AccountID: 3, Code: "CUSTOMER-1-USD", Type: constant.CustomerAccount, CustomerID: &customerID, Currency: "USD", Debit: 100},
+				}, nil)
+
+				// action
+				trialBalance, err := sLedger.TrialBalance()
+
+				// assertion
+				assert.NoError(t, err)
+				assert.True(t, trialBalance.Balanced)
+				assert.Equal(t, []dto.CurrencyTrialBalance{
+					{Currency: "MXN", Debit: 510, Credit: 510, Balanced: true},
+					{Currency: "USD", Debit: 100, Credit: 100, Balanced: true},
+				}, trialBalance.Currencies)
+				assert.Len(t, trialBalance.Accounts, 4)
+				assert.Equal(t, money.Amount(190), trialBalance.Accounts[0].Balance)
+				assert.Equal(t, money.Amount(-190), trialBalance.Accounts[1].Balance)
+			})
+			t.Run("Unbalanced currency", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+
+				// mock preparation
+				mockLedgerRepo.On("FindAccountBalances").Return([]dto.AccountBalance{
+					{AccountID: 2, Code: "CLEARING-MXN", Type: constant.ClearingAccount, Currency: "MXN", Debit: 350},
+					{AccountID: 1, Code: "CUSTOMER-1-MXN", Type: constant.CustomerAccount, CustomerID: &customerID, Currency: "MXN", Credit: 300},
+				}, nil)
+
+				// action
+				trialBalance, err := sLedger.TrialBalance()
+
+				// assertion
+				assert.NoError(t, err)
+				assert.False(t, trialBalance.Balanced)
+				assert.False(t, trialBalance.Currencies[0].Balanced)
+			})
+			t.Run("Empty ledger", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+
+				// mock preparation
+				mockLedgerRepo.On("FindAccountBalances").Return([]dto.AccountBalance{}, nil)
+
+				// action
+				trialBalance, err := sLedger.TrialBalance()
+
+				// assertion
+				assert.NoError(t, err)
+				assert.True(t, trialBalance.Balanced)
+				assert.Empty(t, trialBalance.Currencies)
+				assert.Empty(t, trialBalance.Accounts)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Repository error", func(t *testing.T) {
+				mockLedgerRepo := new(customMocks.ClientLedgerRepository)
+				sLedger := NewLedgerService(mockLedgerRepo)
+
+				// mock preparation
+				mockLedgerRepo.On("FindAccountBalances").Return(nil, repositoryErr)
+
+				// action
+				trialBalance, err := sLedger.TrialBalance()
+
+				// assertion
+				assert.Nil(t, trialBalance)
+				assert.Equal(t, repositoryErr, err)
+			})
+		})
+	})
+}
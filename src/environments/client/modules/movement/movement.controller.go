@@ -1,29 +1,46 @@
 package movement
 
 import (
+	goerrors "errors"
+	"fmt"
 	"net/http"
+	"os"
 	"stori-service/src/environments/client/resources/controller"
 	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/env"
+	"stori-service/src/libs/errors"
 	"stori-service/src/libs/i18n"
 	"stori-service/src/utils/helpers"
+	"stori-service/src/utils/importoptions"
+	"stori-service/src/utils/movementfilter"
+	"stori-service/src/utils/pagination"
 )
 
+// importJobLocation is the route of an import job, where the status of a queued import is checked
+const importJobLocation = "/v1/client/import-jobs/%d"
+
 // struct that implements IMovementController
 type movementController struct {
 	controller.ClientController
-	sMovement interfaces.IMovementService
+	sMovement  interfaces.IMovementService
+	sImportJob interfaces.IImportJobService
 }
 
 /*
-NewMovementController creates a new controller, receives service by dependency injection
+NewMovementController creates a new controller, receives services by dependency injection
 and returns IMovementController, so needs to implement all its methods
 */
-func NewMovementController(sMovement interfaces.IMovementService) interfaces.IMovementController {
-	return &movementController{sMovement: sMovement}
+func NewMovementController(sMovement interfaces.IMovementService, sImportJob interfaces.IImportJobService) interfaces.IMovementController {
+	return &movementController{sMovement: sMovement, sImportJob: sImportJob}
 }
 
 /*
-ProcessFile takes the customerID from params and calls the service to process the file
+ProcessFile takes the customerID and import options from params and queues a job that calls the service to process the file,
+on preview the file is processed in the request. When the customer already imported the same file its import batch
+is returned instead. It's the POST of the imports of a customer, the GET of the old route is deprecated because
+processing a file isn't safe to repeat
 */
 func (c *movementController) ProcessFile(response http.ResponseWriter, request *http.Request) {
 	customerID, err := helpers.IDFromRequestToInt(request)
@@ -31,11 +48,189 @@ func (c *movementController) ProcessFile(response http.ResponseWriter, request *
 		c.MakeErrorResponse(response, err)
 		return
 	}
-	movementList, err := c.sMovement.ProcessFile(customerID)
+	options, err := importoptions.GetImportOptionsFromQuery(request.URL.Query())
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	if options.Preview {
+		c.preview(response, func() (*dto.MovementList, error) {
+			return c.sMovement.ProcessFile(customerID, *options)
+		})
+		return
+	}
+	importBatch, err := c.sMovement.FindImportedFile(customerID, *options)
+	if err == nil {
+		c.imported(response, importBatch)
+		return
+	}
+	if !goerrors.Is(err, errors.ErrNotFound) {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	importJob, err := c.sImportJob.Enqueue(customerID, func() (*dto.MovementList, error) {
+		return c.sMovement.ProcessFile(customerID, *options)
+	})
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.queued(response, importJob)
+}
+
+/*
+UploadFile takes the customerID and import options from params and the file from the request body, up to
+the maximum upload size, saves the file
+until it's processed, so it can be read twice, and queues a job that calls the service to process the uploaded file,
+on preview the file is processed in the request. When the customer already imported the same file its import batch
+is returned instead
+*/
+func (c *movementController) UploadFile(response http.ResponseWriter, request *http.Request) {
+	customerID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	options, err := importoptions.GetImportOptionsFromQuery(request.URL.Query())
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	file, err := helpers.FileFromRequest(response, request, "file", int64(env.MaxUploadSize))
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	defer file.Close()
+	path, err := helpers.SaveTempFile(file, "upload-*")
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	process := func() (*dto.MovementList, error) {
+		defer os.Remove(path)
+		upload, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer upload.Close()
+		return c.sMovement.ProcessUpload(customerID, upload, *options)
+	}
+	if options.Preview {
+		c.preview(response, process)
+		return
+	}
+	importBatch, err := c.findImportedUpload(customerID, path)
+	if err == nil {
+		os.Remove(path)
+		c.imported(response, importBatch)
+		return
+	}
+	if !goerrors.Is(err, errors.ErrNotFound) {
+		os.Remove(path)
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	importJob, err := c.sImportJob.Enqueue(customerID, process)
+	if err != nil {
+		os.Remove(path)
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.queued(response, importJob)
+}
+
+/*
+List takes the customerID, the movement filter and the pagination from params and calls the service
+to get the page of movements of the customer, the pagination goes in the headers. With a cursor, or pagination=cursor,
+the pages are read with the cursor pagination instead of page numbers
+*/
+func (c *movementController) List(response http.ResponseWriter, request *http.Request) {
+	customerID, err := helpers.IDFromRequestToInt(request)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	filter, err := movementfilter.GetMovementFilterFromQuery(request.URL.Query())
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	if pagination.IsCursorQuery(request.URL.Query()) {
+		c.listPage(response, request, customerID, *filter)
+		return
+	}
+	paging, err := pagination.GetPaginationFromQuery(request.URL.Query())
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	movements, err := c.sMovement.FindByCustomerID(customerID, *filter, paging)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakePaginateResponse(response, movements, http.StatusOK, paging)
+}
+
+/*
+listPage takes the cursor pagination from params and calls the service to get the page of movements of the customer
+at the cursor, the cursors of the next and previous pages go in the headers
+*/
+func (c *movementController) listPage(response http.ResponseWriter, request *http.Request, customerID int, filter dto.MovementFilter) {
+	paging, err := pagination.GetCursorPaginationFromQuery(request.URL.Query())
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+	movements, err := c.sMovement.FindPageByCustomerID(customerID, filter, paging)
+	if err != nil {
+		c.MakeErrorResponse(response, err)
+		return
+	}
+
+	c.MakeCursorPaginateResponse(response, request.URL, movements, http.StatusOK, paging)
+}
+
+/*
+queued responds 202 Accepted with the queued import job and its Location, where its status can be checked
+*/
+func (c *movementController) queued(response http.ResponseWriter, importJob *entity.ImportJob) {
+	response.Header().Set("Location", fmt.Sprintf(importJobLocation, importJob.ImportJobID))
+	c.MakeSuccessResponse(response, importJob, http.StatusAccepted, i18n.T(i18n.Message{MessageID: "IMPORT_JOB.QUEUED"}))
+}
+
+/*
+imported responds 201 Created with the import batch of a file that the customer already imported
+*/
+func (c *movementController) imported(response http.ResponseWriter, importBatch *entity.ImportBatch) {
+	c.MakeSuccessResponse(response, importBatch, http.StatusCreated, i18n.T(i18n.Message{MessageID: "IMPORT_BATCH.ALREADY_IMPORTED"}))
+}
+
+/*
+findImportedUpload calls the service to find the import batch of the uploaded file saved on the path
+*/
+func (c *movementController) findImportedUpload(customerID int, path string) (*entity.ImportBatch, error) {
+	upload, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer upload.Close()
+	return c.sMovement.FindImportedUpload(customerID, upload)
+}
+
+/*
+preview runs the import in preview mode and responds the movement list with its summary
+*/
+func (c *movementController) preview(response http.ResponseWriter, run interfaces.ImportFunc) {
+	movementList, err := run()
 	if err != nil {
 		c.MakeErrorResponse(response, err)
 		return
 	}
 
-	c.MakeSuccessResponse(response, movementList, http.StatusOK, i18n.T(i18n.Message{MessageID: "MOVEMENT_LIST.CREATED"}))
+	c.MakeSuccessResponse(response, movementList, http.StatusOK, i18n.T(i18n.Message{MessageID: "MOVEMENT_LIST.PREVIEWED"}))
 }
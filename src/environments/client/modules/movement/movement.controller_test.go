@@ -1,9 +1,15 @@
 package movement
 
 import (
+	"bytes"
 	goErrors "errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"stori-service/src/environments/client/resources/interfaces"
 	"stori-service/src/environments/common/resources/entity"
 	"stori-service/src/libs/dto"
 	"stori-service/src/libs/errors"
@@ -11,16 +17,18 @@ import (
 	"stori-service/src/utils"
 	"stori-service/src/utils/constant"
 	"stori-service/src/utils/test/mock"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
 )
 
 func TestMovementController(t *testing.T) {
 	urlvalues := url.Values{}
 	serviceErr := goErrors.New("service error")
-	path := `/{id}`
+	path := `/{id}/imports`
 	expectedMovementList := &dto.MovementList{
 		Customer: &entity.Customer{
 			CustomerID: 1,
@@ -31,73 +39,479 @@ func TestMovementController(t *testing.T) {
 			{
 				MovementID: 1,
 				CustomerID: 1,
-				Available:  100.00,
-				Quantity:   100.00,
+				Available:  10000,
+				Quantity:   10000,
 				Type:       constant.IncomeType,
 				Date:       time.Now(),
 			},
 		},
 	}
+	expectedImportJob := &entity.ImportJob{
+		ImportJobID: 1,
+		CustomerID:  1,
+		Status:      constant.ImportJobPending,
+	}
+	expectedImportBatch := &entity.ImportBatch{
+		ImportBatchID: 7,
+		CustomerID:    1,
+		TotalRows:     1,
+		ImportedRows:  1,
+	}
 	t.Run("ProcessFile", func(t *testing.T) {
 		t.Run("Should success on", func(t *testing.T) {
-			t.Run("Processing file", func(t *testing.T) {
+			testCases := []struct {
+				name    string
+				query   url.Values
+				options dto.ImportOptions
+			}{
+				{
+					name:    "Queueing the file",
+					query:   urlvalues,
+					options: dto.ImportOptions{Mode: constant.ImportModeStrict, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay},
+				},
+				{
+					name:    "Queueing the file in partial mode",
+					query:   url.Values{"mode": []string{"partial"}},
+					options: dto.ImportOptions{Mode: constant.ImportModePartial, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay},
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					// fixture
+					var run interfaces.ImportFunc
+					mockMovementService := new(mock.ClientMovementService)
+					mockImportJobService := new(mock.ClientImportJobService)
+					movementControler := NewMovementController(mockMovementService, mockImportJobService)
+
+					// mock expectations
+					mockImportJobService.On("Enqueue", 1, testifyMock.Anything).Return(expectedImportJob, nil).
+						Run(func(args testifyMock.Arguments) {
+							run = args.Get(1).(interfaces.ImportFunc)
+						})
+					mockMovementService.On("FindImportedFile", 1, tC.options).Return(nil, errors.ErrNotFound)
+					mockMovementService.On("ProcessFile", 1, tC.options).Return(expectedMovementList, nil)
+
+					//Action
+					resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "1/imports", tC.query, nil)
+					movementList, err := run()
+
+					//Mock Assertion
+					mockImportJobService.AssertExpectations(t)
+					mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 1)
+					mockMovementService.AssertExpectations(t)
+					mockMovementService.AssertNumberOfCalls(t, "ProcessFile", 1)
+
+					result := &entity.ImportJob{}
+					bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+					//Data Assertion
+					assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+					assert.Equal(t, "/v1/client/import-jobs/1", resp.Header.Get("Location"))
+					assert.Equal(t, i18n.T(i18n.Message{MessageID: "IMPORT_JOB.QUEUED"}), bodyResponse.Message)
+					assert.Empty(t, bodyResponse.Errors)
+					assert.Equal(t, expectedImportJob.ImportJobID, result.ImportJobID)
+					assert.NoError(t, err)
+					assert.Equal(t, expectedMovementList, movementList)
+				})
+			}
+			t.Run("Returning the batch of a file already imported", func(t *testing.T) {
+				// fixture
+				mockMovementService := new(mock.ClientMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
+				options := dto.ImportOptions{Mode: constant.ImportModeStrict, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay}
+
+				// mock expectations
+				mockMovementService.On("FindImportedFile", 1, options).Return(expectedImportBatch, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "1/imports", urlvalues, nil)
+
+				//Mock Assertion
+				mockMovementService.AssertExpectations(t)
+				mockMovementService.AssertNumberOfCalls(t, "ProcessFile", 0)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 0)
+
+				result := &entity.ImportBatch{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "IMPORT_BATCH.ALREADY_IMPORTED"}), bodyResponse.Message)
+				assert.Empty(t, bodyResponse.Errors)
+				assert.Equal(t, expectedImportBatch.ImportBatchID, result.ImportBatchID)
+				assert.Equal(t, expectedImportBatch.ImportedRows, result.ImportedRows)
+			})
+			t.Run("Previewing the file", func(t *testing.T) {
 				// fixture
 				mockMovementService := new(mock.ClientMovementService)
-				movementControler := NewMovementController(mockMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
+				options := dto.ImportOptions{Mode: constant.ImportModeStrict, Preview: true, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay}
 
 				// mock expectations
-				mockMovementService.On("ProcessFile", 1).Return(expectedMovementList, nil)
+				mockMovementService.On("ProcessFile", 1, options).Return(expectedMovementList, nil)
 
 				//Action
-				resp := mock.MHTTPHandle(http.MethodGet, path, movementControler.ProcessFile, "1", urlvalues, nil)
+				resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "1/imports", url.Values{"preview": []string{"true"}}, nil)
 
 				//Mock Assertion
 				mockMovementService.AssertExpectations(t)
-				mockMovementService.AssertNumberOfCalls(t, "ProcessFile", 1)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 0)
 
 				result := &dto.MovementList{}
 				bodyResponse, _ := utils.GetBodyResponse(resp, result)
 
 				//Data Assertion
 				assert.Equal(t, http.StatusOK, resp.StatusCode)
-				assert.Equal(t, i18n.T(i18n.Message{MessageID: "MOVEMENT_LIST.CREATED"}), bodyResponse.Message)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "MOVEMENT_LIST.PREVIEWED"}), bodyResponse.Message)
 				assert.Empty(t, bodyResponse.Errors)
+				assert.Equal(t, len(expectedMovementList.Movements), len(result.Movements))
+				assert.Equal(t, expectedMovementList.Movements[0].MovementID, result.Movements[0].MovementID)
 			})
 		})
 		t.Run("Should fail on", func(t *testing.T) {
-			t.Run("Invalid id", func(t *testing.T) {
+			t.Run("Service fails previewing the file", func(t *testing.T) {
 				// fixture
-				movementControler := NewMovementController(nil)
+				mockMovementService := new(mock.ClientMovementService)
+				movementControler := NewMovementController(mockMovementService, nil)
+				options := dto.ImportOptions{Mode: constant.ImportModeStrict, Preview: true, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay}
+
+				// mock expectations
+				mockMovementService.On("ProcessFile", 1, options).Return(nil, errors.ErrFileNotFound)
 
 				//Action
-				resp := mock.MHTTPHandle(http.MethodGet, path, movementControler.ProcessFile, "asd", urlvalues, nil)
+				resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "1/imports", url.Values{"preview": []string{"true"}}, nil)
+
+				//Mock Assertion
+				mockMovementService.AssertExpectations(t)
 
 				result := &dto.MovementList{}
 				bodyResponse, _ := utils.GetBodyResponse(resp, result)
 
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrFileNotFound.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result.Movements)
+			})
+			t.Run("Invalid id", func(t *testing.T) {
+				// fixture
+				movementControler := NewMovementController(nil, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "asd/imports", urlvalues, nil)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
 				//Data Assertion
 				assert.Equal(t, errors.GetStatusCode(serviceErr), resp.StatusCode)
 				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
 				assert.Empty(t, result)
 			})
-			t.Run("Service fails processing file", func(t *testing.T) {
+			t.Run("Invalid mode", func(t *testing.T) {
+				// fixture
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(nil, mockImportJobService)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "1/imports", url.Values{"mode": []string{"lenient"}}, nil)
+
+				//Mock Assertion
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 0)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, errors.ErrFieldValidation("mode", "oneof", "strict partial").Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Service fails finding the imported file", func(t *testing.T) {
 				// fixture
 				mockMovementService := new(mock.ClientMovementService)
-				movementControler := NewMovementController(mockMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
 
 				// mock expectations
-				mockMovementService.On("ProcessFile", 1).Return(nil, serviceErr)
+				mockMovementService.On("FindImportedFile", 1, testifyMock.AnythingOfType("dto.ImportOptions")).Return(nil, errors.ErrFileNotFound)
 
 				//Action
-				resp := mock.MHTTPHandle(http.MethodGet, path, movementControler.ProcessFile, "1", urlvalues, nil)
+				resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "1/imports", urlvalues, nil)
 
 				//Mock Assertion
 				mockMovementService.AssertExpectations(t)
-				mockMovementService.AssertNumberOfCalls(t, "ProcessFile", 1)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 0)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrFileNotFound.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Service fails queueing the file", func(t *testing.T) {
+				// fixture
+				mockMovementService := new(mock.ClientMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
+
+				// mock expectations
+				mockMovementService.On("FindImportedFile", 1, testifyMock.AnythingOfType("dto.ImportOptions")).Return(nil, errors.ErrNotFound)
+				mockImportJobService.On("Enqueue", 1, testifyMock.Anything).Return(nil, errors.ErrImportQueueFull)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodPost, path, movementControler.ProcessFile, "1/imports", urlvalues, nil)
+
+				//Mock Assertion
+				mockImportJobService.AssertExpectations(t)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 1)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+				assert.Equal(t, errors.ErrImportQueueFull.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+		})
+	})
+	t.Run("UploadFile", func(t *testing.T) {
+		uploadPath := `/{id}/files`
+		fileContent := "id,date,transaction\n1,05/25,+3.5"
+		t.Run("Should success on", func(t *testing.T) {
+			multipartBody := &bytes.Buffer{}
+			writer := multipart.NewWriter(multipartBody)
+			part, _ := writer.CreateFormFile("file", "customer_1.csv")
+			part.Write([]byte(fileContent))
+			writer.Close()
+			testCases := []struct {
+				name        string
+				params      string
+				contentType string
+				body        *bytes.Buffer
+				options     dto.ImportOptions
+			}{
+				{
+					name:        "Uploading a multipart file",
+					params:      "1/files",
+					contentType: writer.FormDataContentType(),
+					body:        multipartBody,
+					options:     dto.ImportOptions{Mode: constant.ImportModeStrict, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay},
+				},
+				{
+					name:        "Uploading a raw csv body",
+					params:      "1/files",
+					contentType: "text/csv",
+					body:        bytes.NewBufferString(fileContent),
+					options:     dto.ImportOptions{Mode: constant.ImportModeStrict, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay},
+				},
+				{
+					name:        "Uploading a raw csv body in partial mode",
+					params:      "1/files?mode=partial",
+					contentType: "text/csv",
+					body:        bytes.NewBufferString(fileContent),
+					options:     dto.ImportOptions{Mode: constant.ImportModePartial, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay},
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					// fixture
+					var run interfaces.ImportFunc
+					var uploaded []byte
+					mockMovementService := new(mock.ClientMovementService)
+					mockImportJobService := new(mock.ClientImportJobService)
+					movementControler := NewMovementController(mockMovementService, mockImportJobService)
+
+					// mock expectations
+					mockImportJobService.On("Enqueue", 1, testifyMock.Anything).Return(expectedImportJob, nil).
+						Run(func(args testifyMock.Arguments) {
+							run = args.Get(1).(interfaces.ImportFunc)
+						})
+					mockMovementService.On("FindImportedUpload", 1, testifyMock.Anything).Return(nil, errors.ErrNotFound)
+					mockMovementService.On("ProcessUpload", 1, testifyMock.Anything, tC.options).Return(expectedMovementList, nil).
+						Run(func(args testifyMock.Arguments) {
+							uploaded, _ = ioutil.ReadAll(args.Get(1).(io.Reader))
+						})
+
+					//Action
+					resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, tC.params, tC.contentType, tC.body)
+					movementList, err := run()
+
+					//Mock Assertion
+					mockImportJobService.AssertExpectations(t)
+					mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 1)
+					mockMovementService.AssertExpectations(t)
+					mockMovementService.AssertNumberOfCalls(t, "ProcessUpload", 1)
+
+					result := &entity.ImportJob{}
+					bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+					//Data Assertion
+					assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+					assert.Equal(t, "/v1/client/import-jobs/1", resp.Header.Get("Location"))
+					assert.Equal(t, i18n.T(i18n.Message{MessageID: "IMPORT_JOB.QUEUED"}), bodyResponse.Message)
+					assert.Empty(t, bodyResponse.Errors)
+					assert.Equal(t, fileContent, string(uploaded))
+					assert.NoError(t, err)
+					assert.Equal(t, expectedMovementList, movementList)
+				})
+			}
+			t.Run("Returning the batch of an uploaded file already imported", func(t *testing.T) {
+				// fixture
+				var uploaded []byte
+				mockMovementService := new(mock.ClientMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
+
+				// mock expectations
+				mockMovementService.On("FindImportedUpload", 1, testifyMock.Anything).Return(expectedImportBatch, nil).
+					Run(func(args testifyMock.Arguments) {
+						uploaded, _ = ioutil.ReadAll(args.Get(1).(io.Reader))
+					})
+
+				//Action
+				resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, "1/files", "text/csv", bytes.NewBufferString(fileContent))
+
+				//Mock Assertion
+				mockMovementService.AssertExpectations(t)
+				mockMovementService.AssertNumberOfCalls(t, "ProcessUpload", 0)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 0)
+
+				result := &entity.ImportBatch{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "IMPORT_BATCH.ALREADY_IMPORTED"}), bodyResponse.Message)
+				assert.Empty(t, bodyResponse.Errors)
+				assert.Equal(t, fileContent, string(uploaded))
+				assert.Equal(t, expectedImportBatch.ImportBatchID, result.ImportBatchID)
+			})
+			t.Run("Previewing a raw csv body", func(t *testing.T) {
+				// fixture
+				var uploaded []byte
+				mockMovementService := new(mock.ClientMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
+				options := dto.ImportOptions{Mode: constant.ImportModePartial, Preview: true, Profile: "default", Source: "default", SourceCurrency: constant.DefaultCurrency, DateFormat: constant.DateFormatMonthDay}
+
+				// mock expectations
+				mockMovementService.On("ProcessUpload", 1, testifyMock.Anything, options).Return(expectedMovementList, nil).
+					Run(func(args testifyMock.Arguments) {
+						uploaded, _ = ioutil.ReadAll(args.Get(1).(io.Reader))
+					})
+
+				//Action
+				resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, "1/files?mode=partial&preview=true", "text/csv", bytes.NewBufferString(fileContent))
+
+				//Mock Assertion
+				mockMovementService.AssertExpectations(t)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 0)
 
 				result := &dto.MovementList{}
 				bodyResponse, _ := utils.GetBodyResponse(resp, result)
 
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, i18n.T(i18n.Message{MessageID: "MOVEMENT_LIST.PREVIEWED"}), bodyResponse.Message)
+				assert.Equal(t, fileContent, string(uploaded))
+				assert.Equal(t, len(expectedMovementList.Movements), len(result.Movements))
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid id", func(t *testing.T) {
+				// fixture
+				movementControler := NewMovementController(nil, nil)
+
+				//Action
+				resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, "asd/files", "text/csv", bytes.NewBufferString(fileContent))
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, errors.GetStatusCode(serviceErr), resp.StatusCode)
+				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Invalid mode", func(t *testing.T) {
+				// fixture
+				movementControler := NewMovementController(nil, nil)
+
+				//Action
+				resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, "1/files?mode=lenient", "text/csv", bytes.NewBufferString(fileContent))
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, errors.ErrFieldValidation("mode", "oneof", "strict partial").Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Unsupported content type", func(t *testing.T) {
+				// fixture
+				movementControler := NewMovementController(nil, nil)
+
+				//Action
+				resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, "1/files", "application/json", bytes.NewBufferString("{}"))
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+				assert.Equal(t, errors.ErrUnsupportedMediaType.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Service fails finding the imported file", func(t *testing.T) {
+				// fixture
+				mockMovementService := new(mock.ClientMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
+
+				// mock expectations
+				mockMovementService.On("FindImportedUpload", 1, testifyMock.Anything).Return(nil, serviceErr)
+
+				//Action
+				resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, "1/files", "text/csv", bytes.NewBufferString(fileContent))
+
+				//Mock Assertion
+				mockMovementService.AssertExpectations(t)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 0)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, errors.GetStatusCode(serviceErr), resp.StatusCode)
+				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
+				assert.Empty(t, result)
+			})
+			t.Run("Service fails queueing the file", func(t *testing.T) {
+				// fixture
+				mockMovementService := new(mock.ClientMovementService)
+				mockImportJobService := new(mock.ClientImportJobService)
+				movementControler := NewMovementController(mockMovementService, mockImportJobService)
+
+				// mock expectations
+				mockMovementService.On("FindImportedUpload", 1, testifyMock.Anything).Return(nil, errors.ErrNotFound)
+				mockImportJobService.On("Enqueue", 1, testifyMock.Anything).Return(nil, serviceErr)
+
+				//Action
+				resp := mock.MHTTPHandleRawBody(http.MethodPost, uploadPath, movementControler.UploadFile, "1/files", "text/csv", bytes.NewBufferString(fileContent))
+
+				//Mock Assertion
+				mockImportJobService.AssertExpectations(t)
+				mockImportJobService.AssertNumberOfCalls(t, "Enqueue", 1)
+
+				result := &entity.ImportJob{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
 				//Data Assertion
 				assert.Equal(t, errors.GetStatusCode(serviceErr), resp.StatusCode)
 				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
@@ -105,4 +519,186 @@ func TestMovementController(t *testing.T) {
 			})
 		})
 	})
+	t.Run("List", func(t *testing.T) {
+		listPath := `/{id}/movements`
+		movements := []entity.Movement{
+			{MovementID: 2, CustomerID: 1, Quantity: 2500, HomeQuantity: 2500, Type: constant.OutcomeType, Date: time.Date(2022, time.July, 2, 0, 0, 0, 0, time.UTC)},
+			{MovementID: 1, CustomerID: 1, Quantity: 10000, HomeQuantity: 10000, Type: constant.IncomeType, Date: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		t.Run("Should success on", func(t *testing.T) {
+			from := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+			testCases := []struct {
+				name       string
+				query      url.Values
+				filter     dto.MovementFilter
+				pagination *dto.Pagination
+				pageCount  string
+			}{
+				{
+					name:       "Listing with the default filter and pagination",
+					query:      urlvalues,
+					filter:     dto.MovementFilter{Sort: constant.MovementSortDateDesc},
+					pagination: dto.NewPagination(1, 20, 0),
+					pageCount:  "1",
+				},
+				{
+					name:       "Listing a page of the filtered movements",
+					query:      url.Values{"page": []string{"2"}, "page_size": []string{"2"}, "from": []string{"2022-07-01"}, "sort": []string{"amount"}},
+					filter:     dto.MovementFilter{From: &from, Sort: constant.MovementSortAmount},
+					pagination: dto.NewPagination(2, 2, 0),
+					pageCount:  "3",
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					// fixture
+					mockMovementService := new(mock.ClientMovementService)
+					movementControler := NewMovementController(mockMovementService, nil)
+
+					// mock expectations
+					mockMovementService.On("FindByCustomerID", 1, tC.filter, tC.pagination).Return(movements, nil).
+						Run(func(args testifyMock.Arguments) {
+							args.Get(2).(*dto.Pagination).TotalCount = 5
+						})
+
+					//Action
+					resp := mock.MHTTPHandle(http.MethodGet, listPath, movementControler.List, "1/movements", tC.query, nil)
+
+					//Mock Assertion
+					mockMovementService.AssertExpectations(t)
+					mockMovementService.AssertNumberOfCalls(t, "FindByCustomerID", 1)
+
+					result := &[]entity.Movement{}
+					bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+					//Data Assertion
+					assert.Equal(t, http.StatusOK, resp.StatusCode)
+					assert.Empty(t, bodyResponse.Errors)
+					assert.Equal(t, "5", resp.Header.Get("X-pagination-total-count"))
+					assert.Equal(t, tC.pageCount, resp.Header.Get("X-pagination-page-count"))
+					assert.Equal(t, strconv.Itoa(tC.pagination.Page), resp.Header.Get("X-pagination-current-page"))
+					assert.Equal(t, strconv.Itoa(tC.pagination.PageSize), resp.Header.Get("X-pagination-page-size"))
+					assert.Len(t, *result, 2)
+					assert.Equal(t, movements[0].MovementID, (*result)[0].MovementID)
+				})
+			}
+			t.Run("Listing a page of the cursor pagination", func(t *testing.T) {
+				// fixture
+				mockMovementService := new(mock.ClientMovementService)
+				movementControler := NewMovementController(mockMovementService, nil)
+				cursor := &dto.Cursor{Date: time.Date(2022, time.July, 3, 0, 0, 0, 0, time.UTC), MovementID: 3}
+				next := &dto.Cursor{Date: movements[1].Date, MovementID: movements[1].MovementID}
+				prev := &dto.Cursor{Date: movements[0].Date, MovementID: movements[0].MovementID, Backward: true}
+				filter := dto.MovementFilter{Type: constant.OutcomeType, Sort: constant.MovementSortDateDesc}
+
+				// mock expectations
+				mockMovementService.On("FindPageByCustomerID", 1, filter, dto.NewCursorPagination(2, cursor)).Return(movements, nil).
+					Run(func(args testifyMock.Arguments) {
+						pagination := args.Get(2).(*dto.CursorPagination)
+						pagination.Next, pagination.Prev = next, prev
+					})
+
+				//Action
+				query := url.Values{"cursor": []string{cursor.Encode()}, "page_size": []string{"2"}, "type": []string{"outcome"}}
+				resp := mock.MHTTPHandle(http.MethodGet, listPath, movementControler.List, "1/movements", query, nil)
+
+				//Mock Assertion
+				mockMovementService.AssertExpectations(t)
+				mockMovementService.AssertNumberOfCalls(t, "FindByCustomerID", 0)
+
+				result := &[]entity.Movement{}
+				bodyResponse, _ := utils.GetBodyResponse(resp, result)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Empty(t, bodyResponse.Errors)
+				assert.Equal(t, next.Encode(), resp.Header.Get("X-pagination-next-cursor"))
+				assert.Equal(t, prev.Encode(), resp.Header.Get("X-pagination-prev-cursor"))
+				assert.Contains(t, resp.Header.Get("Link"), fmt.Sprintf(`</1/movements?cursor=%s&page_size=2&type=outcome>; rel="next"`, next.Encode()))
+				assert.Contains(t, resp.Header.Get("Link"), fmt.Sprintf(`</1/movements?cursor=%s&page_size=2&type=outcome>; rel="prev"`, prev.Encode()))
+				assert.Empty(t, resp.Header.Get("X-pagination-total-count"))
+				assert.Len(t, *result, 2)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid id", func(t *testing.T) {
+				// fixture
+				movementControler := NewMovementController(nil, nil)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, listPath, movementControler.List, "asd/movements", urlvalues, nil)
+
+				bodyResponse, _ := utils.GetBodyResponse(resp, nil)
+
+				//Data Assertion
+				assert.Equal(t, errors.GetStatusCode(serviceErr), resp.StatusCode)
+				assert.Equal(t, errors.ErrInternalServer.Error(), bodyResponse.Errors[0]["error"])
+			})
+			testCases := []struct {
+				name  string
+				query url.Values
+				err   error
+			}{
+				{
+					name:  "Invalid type",
+					query: url.Values{"type": []string{"transfer"}},
+					err:   errors.ErrFieldValidation("type", "oneof", "income outcome"),
+				},
+				{
+					name:  "Invalid page",
+					query: url.Values{"page": []string{"first"}},
+					err:   errors.ErrFieldValidation("page", "not_number", ""),
+				},
+				{
+					name:  "Invalid cursor",
+					query: url.Values{"cursor": []string{"not_a_cursor"}},
+					err:   errors.ErrInvalidCursor,
+				},
+				{
+					name:  "Too high page size",
+					query: url.Values{"page_size": []string{"101"}},
+					err:   errors.ErrPageSizeTooHigh,
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					// fixture
+					mockMovementService := new(mock.ClientMovementService)
+					movementControler := NewMovementController(mockMovementService, nil)
+
+					//Action
+					resp := mock.MHTTPHandle(http.MethodGet, listPath, movementControler.List, "1/movements", tC.query, nil)
+
+					//Mock Assertion
+					mockMovementService.AssertNumberOfCalls(t, "FindByCustomerID", 0)
+
+					bodyResponse, _ := utils.GetBodyResponse(resp, nil)
+
+					//Data Assertion
+					assert.Equal(t, errors.GetStatusCode(tC.err), resp.StatusCode)
+					assert.Equal(t, tC.err.Error(), bodyResponse.Errors[0]["error"])
+				})
+			}
+			t.Run("Service fails finding the customer", func(t *testing.T) {
+				// fixture
+				mockMovementService := new(mock.ClientMovementService)
+				movementControler := NewMovementController(mockMovementService, nil)
+
+				// mock expectations
+				mockMovementService.On("FindByCustomerID", 1, dto.MovementFilter{Sort: constant.MovementSortDateDesc}, dto.NewPagination(1, 20, 0)).Return(nil, errors.ErrNotFound)
+
+				//Action
+				resp := mock.MHTTPHandle(http.MethodGet, listPath, movementControler.List, "1/movements", urlvalues, nil)
+
+				//Mock Assertion
+				mockMovementService.AssertExpectations(t)
+
+				bodyResponse, _ := utils.GetBodyResponse(resp, nil)
+
+				//Data Assertion
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, errors.ErrNotFound.Error(), bodyResponse.Errors[0]["error"])
+			})
+		})
+	})
 }
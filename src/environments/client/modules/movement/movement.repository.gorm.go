@@ -5,7 +5,12 @@ import (
 	"stori-service/src/environments/client/resources/interfaces"
 	database "stori-service/src/environments/common/resources/database/transaction"
 	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/database/scopes"
+	"stori-service/src/libs/dto"
 	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -28,22 +33,29 @@ func NewMovementGormRepo(gormDb *gorm.DB) interfaces.IMovementRepository {
 	return rMovement
 }
 
+// maxInsertRows keeps each INSERT under the PostgreSQL limit of 65535 parameters
+const maxInsertRows = 1000
+
 /*
-BulkCreate receives a list of movements to be created and creates them
+BulkCreate receives a list of movements to be created and creates them, with one INSERT
+each maxInsertRows movements
 */
 func (r *movementGormRepo) BulkCreate(movements []entity.Movement) error {
-	return r.DB.Create(&movements).Error
+	return r.DB.CreateInBatches(&movements, maxInsertRows).Error
 }
 
 /*
-GetLastMovementByCustomerID receives a customerID, locks the table and returns the last movement
+GetLastMovementByCustomerID receives a customerID and a currency, locks the table and returns the last movement
+of the customer in that currency, the last one inserted when several have the same date
 */
-func (r *movementGormRepo) GetLastMovementByCustomerID(customerID int) (*entity.Movement, error) {
+func (r *movementGormRepo) GetLastMovementByCustomerID(customerID int, currency string) (*entity.Movement, error) {
 	var movement entity.Movement
 	db := r.DB.Clauses(clause.Locking{Strength: "UPDATE"})
-	err := db.Model(&entity.Movement{}).
-		Where(&entity.Movement{CustomerID: customerID}).
-		Order("date DESC").
+	err := db.Scopes(
+		scopes.MovementByCustomerID(customerID),
+		scopes.MovementsByCurrency(currency),
+		scopes.MovementsByDate(true),
+	).
 		Take(&movement).Error
 	if goerrors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, errors.ErrNotFound
@@ -54,6 +66,280 @@ func (r *movementGormRepo) GetLastMovementByCustomerID(customerID int) (*entity.
 	return &movement, err
 }
 
+/*
+GetLastMovementsAtDate returns the last movement of the customer at or before the date in each currency,
+the last one inserted when several have the same date, sorted by currency
+*/
+func (r *movementGormRepo) GetLastMovementsAtDate(customerID int, date time.Time) ([]entity.Movement, error) {
+	movements := []entity.Movement{}
+	err := r.DB.Scopes(
+		scopes.MovementByCustomerID(customerID),
+		scopes.MovementsUntilDate(date),
+		scopes.MovementsOrderByCurrency(),
+		scopes.MovementsByDate(true),
+	).
+		Select("DISTINCT ON (currency) *").
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+/*
+GetTotalsByCustomerID returns the total incomes and outcomes of the customer in each currency from the day from
+until the day to, both included, a nil day doesn't limit the range. The totals are sorted by currency
+*/
+func (r *movementGormRepo) GetTotalsByCustomerID(customerID int, from, to *time.Time) ([]dto.CurrencyTotals, error) {
+	totals := []dto.CurrencyTotals{}
+	err := r.DB.Scopes(
+		scopes.MovementByCustomerID(customerID),
+		scopes.MovementsByDateRange(from, to),
+		scopes.MovementsOrderByCurrency(),
+	).
+		Select(`currency,
+			COALESCE(SUM(quantity) FILTER (WHERE type = ?), 0) AS total_income,
+			COALESCE(SUM(quantity) FILTER (WHERE type = ?), 0) AS total_outcome`, constant.IncomeType, constant.OutcomeType).
+		Group("currency").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+/*
+FindExistingExternalRefs receives the customer, the source of the file and a list of external references
+and returns the ones that the customer already has for that source
+*/
+func (r *movementGormRepo) FindExistingExternalRefs(customerID int, source string, externalRefs []string) ([]string, error) {
+	existingRefs := []string{}
+	err := r.DB.Scopes(scopes.MovementsByExternalRefs(customerID, source, externalRefs)).
+		Pluck("external_ref", &existingRefs).Error
+	if err != nil {
+		return nil, err
+	}
+	return existingRefs, nil
+}
+
+/*
+DeleteByImportBatchID soft deletes the movements created by the import batch
+*/
+func (r *movementGormRepo) DeleteByImportBatchID(importBatchID int) error {
+	return r.DB.Scopes(scopes.MovementsByImportBatchID(importBatchID)).Delete(&entity.Movement{}).Error
+}
+
+/*
+GetLastMovementBeforeDate returns the last movement of the customer in the currency with a date before the given one,
+ordered by date and ID
+*/
+func (r *movementGormRepo) GetLastMovementBeforeDate(customerID int, currency string, date time.Time) (*entity.Movement, error) {
+	var movement entity.Movement
+	err := r.DB.Scopes(
+		scopes.MovementByCustomerID(customerID),
+		scopes.MovementsByCurrency(currency),
+		scopes.MovementsBeforeDate(date),
+		scopes.MovementsByDate(true),
+	).
+		Take(&movement).Error
+	if goerrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &movement, nil
+}
+
+/*
+UpdateAvailableFromDate sets the available of the customer movements in the currency from the date on
+as the running balance ordered by date and ID, starting from the opening amount
+*/
+func (r *movementGormRepo) UpdateAvailableFromDate(customerID int, currency string, date time.Time, opening money.Amount) error {
+	running := r.DB.Table("movement").
+		Select("movement_id, CAST(? AS numeric) + SUM(quantity * type) OVER (ORDER BY date, movement_id) AS available", opening).
+		Where(&entity.Movement{CustomerID: customerID}).
+		Scopes(scopes.MovementsNotDeleted(), scopes.MovementsByCurrency(currency), scopes.MovementsByDateRange(&date, nil))
+	return r.DB.Exec(`
+		UPDATE movement SET available = running.available, updated_at = NOW()
+		FROM (?) running
+		WHERE movement.movement_id = running.movement_id AND movement.available <> running.available`,
+		running).Error
+}
+
+/*
+FindFirstOverdrawn returns the first movement of the import batch in each currency, ordered by date and ID,
+that leaves the available below zero, sorted by currency. The movements of other batches aren't checked,
+so the overdrafts accepted before aren't found again
+*/
+func (r *movementGormRepo) FindFirstOverdrawn(importBatchID int) ([]entity.Movement, error) {
+	movements := []entity.Movement{}
+	err := r.DB.Scopes(
+		scopes.MovementsByImportBatchID(importBatchID),
+		scopes.MovementsOverdrawn(),
+		scopes.MovementsOrderByCurrency(),
+		scopes.MovementsByDate(false),
+	).
+		Select("DISTINCT ON (currency) *").
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+/*
+FindFirstOverCreditLimit returns the first movement of the import batch, ordered by date and ID, that leaves the balance
+of the customer in all its currencies below the negative of the credit limit. That balance is the sum of the last available
+of each currency converted to the home currency with the rate of its movement, and it's the available of the overdraft
+*/
+func (r *movementGormRepo) FindFirstOverCreditLimit(customerID int, importBatchID int, creditLimit money.Amount) (*entity.Overdraft, error) {
+	var overdraft entity.Overdraft
+	homeMovements := r.DB.Table("movement").
+		Select(`movement_id, external_id, source, date, import_batch_id,
+			ROUND(available * exchange_rate, 2) AS home_available,
+			LAG(ROUND(available * exchange_rate, 2), 1, 0::numeric) OVER (PARTITION BY currency ORDER BY date, movement_id) AS previous_home_available`).
+		Where(&entity.Movement{CustomerID: customerID}).
+		Scopes(scopes.MovementsNotDeleted())
+	// each movement changes the balance by the difference with the previous available of its currency
+	result := r.DB.Raw(`
+		SELECT movement_id, external_id, source, date, home_balance AS available
+		FROM (
+			SELECT movement_id, external_id, source, date, import_batch_id,
+				SUM(home_available - previous_home_available) OVER (ORDER BY date, movement_id) AS home_balance
+			FROM (?) home_movement
+		) running
+		WHERE import_batch_id = ? AND home_balance < -CAST(? AS numeric)
+		ORDER BY date, movement_id
+		LIMIT 1`, homeMovements, importBatchID, creditLimit).
+		Scan(&overdraft)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.ErrNotFound
+	}
+	return &overdraft, nil
+}
+
+/*
+FindFirstDatesByImportBatchID returns the date of the first movement of the import batch in each currency
+*/
+func (r *movementGormRepo) FindFirstDatesByImportBatchID(importBatchID int) (map[string]time.Time, error) {
+	var rows []struct {
+		Currency string
+		Date     time.Time
+	}
+	err := r.DB.Scopes(scopes.MovementsByImportBatchID(importBatchID)).
+		Select("currency, MIN(date) AS date").
+		Group("currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	firstDates := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		firstDates[row.Currency] = row.Date
+	}
+	return firstDates, nil
+}
+
+/*
+FindByCustomerID returns the page of movements of the customer that pass the filter, in its sort order,
+and sets the total count of movements that pass it on the pagination
+*/
+func (r *movementGormRepo) FindByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.Pagination) ([]entity.Movement, error) {
+	db := r.DB.Scopes(scopes.MovementByCustomerID(customerID), scopes.MovementsByFilter(filter)).
+		Session(&gorm.Session{}) // the conditions are shared by the count and the page
+	err := db.Count(&pagination.TotalCount).Error
+	if err != nil {
+		return nil, err
+	}
+	movements := []entity.Movement{}
+	err = db.Scopes(scopes.MovementsOrderBy(filter.Sort)).
+		Offset(pagination.Offset()).
+		Limit(pagination.PageSize).
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+/*
+FindPageByCustomerID returns the page of movements of the customer that pass the filter after the cursor of the pagination,
+or before it for backward cursors, in order of date and movement_id, the newest first unless the filter sorts by date.
+The pagination gets the cursors of the next and previous pages, when there are movements after and before the page
+*/
+func (r *movementGormRepo) FindPageByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.CursorPagination) ([]entity.Movement, error) {
+	movements := []entity.Movement{}
+	err := r.DB.Scopes(
+		scopes.MovementByCustomerID(customerID),
+		scopes.MovementsByFilter(filter),
+		scopes.MovementKeyset(pagination, filter.Sort != constant.MovementSortDate),
+	).Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	backward := pagination.Cursor != nil && pagination.Cursor.Backward
+	more := len(movements) > pagination.PageSize
+	if more {
+		movements = movements[:pagination.PageSize]
+	}
+	if backward {
+		for i, j := 0, len(movements)-1; i < j; i, j = i+1, j-1 {
+			movements[i], movements[j] = movements[j], movements[i]
+		}
+	}
+	pagination.Next, pagination.Prev = nil, nil
+	if len(movements) == 0 {
+		return movements, nil
+	}
+	// going forward there are movements after the page when more were found and before it when it isn't the first one,
+	// going backward the page is always before another one
+	hasNext, hasPrev := more, pagination.Cursor != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		last := movements[len(movements)-1]
+		pagination.Next = &dto.Cursor{Date: last.Date, MovementID: last.MovementID}
+	}
+	if hasPrev {
+		first := movements[0]
+		pagination.Prev = &dto.Cursor{Date: first.Date, MovementID: first.MovementID, Backward: true}
+	}
+	return movements, nil
+}
+
+/*
+FindCategorizable returns up to limit movements of the customer after the ID, ordered by ID,
+whose category was set by a rule or is empty, the categories of the files are kept
+*/
+func (r *movementGormRepo) FindCategorizable(customerID int, afterID int, limit int) ([]entity.Movement, error) {
+	movements := []entity.Movement{}
+	err := r.DB.Scopes(
+		scopes.MovementByCustomerID(customerID),
+		scopes.MovementsAfterID(afterID),
+		scopes.MovementsCategorizable(),
+		scopes.MovementsOrderByID(),
+	).
+		Limit(limit).
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+/*
+UpdateCategory sets the category of the movement and the rule that set it, nil when no rule did
+*/
+func (r *movementGormRepo) UpdateCategory(movementID int, category string, categoryRuleID *int) error {
+	return r.DB.Model(&entity.Movement{MovementID: movementID}).
+		Updates(map[string]interface{}{"category": category, "category_rule_id": categoryRuleID}).Error
+}
+
 /*
 Clone returns a new instance of the repository
 */
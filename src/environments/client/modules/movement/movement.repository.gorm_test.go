@@ -4,7 +4,11 @@ import (
 	"os"
 	"stori-service/src/environments/common/resources/entity"
 	"stori-service/src/libs/database"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
 	"stori-service/src/utils/constant"
+	"strings"
 	"testing"
 	"time"
 
@@ -52,60 +56,92 @@ var customers = []entity.Customer{
 
 var movements = []entity.Movement{
 	{
-		MovementID: 1,
-		CustomerID: 1,
-		Quantity:   10,
-		Available:  10,
-		Type:       constant.IncomeType,
+		MovementID:  1,
+		ExternalID:  1,
+		ExternalRef: "1",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  1,
+		Quantity:    1000,
+		Available:   1000,
+		Type:        constant.IncomeType,
 	},
 	{
-		MovementID: 2,
-		CustomerID: 1,
-		Quantity:   5,
-		Available:  5,
-		Type:       constant.OutcomeType,
+		MovementID:  2,
+		ExternalID:  2,
+		ExternalRef: "2",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  1,
+		Quantity:    500,
+		Available:   500,
+		Type:        constant.OutcomeType,
 	},
 	{
-		MovementID: 3,
-		CustomerID: 1,
-		Quantity:   10,
-		Available:  15,
-		Type:       constant.IncomeType,
+		MovementID:  3,
+		ExternalID:  3,
+		ExternalRef: "3",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  1,
+		Quantity:    1000,
+		Available:   1500,
+		Type:        constant.IncomeType,
 	},
 	{
-		MovementID: 4,
-		CustomerID: 4,
-		Quantity:   17,
-		Available:  17,
-		Type:       constant.IncomeType,
+		MovementID:  4,
+		ExternalID:  1,
+		ExternalRef: "1",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  4,
+		Quantity:    1700,
+		Available:   1700,
+		Type:        constant.IncomeType,
 	},
 	{
-		MovementID: 5,
-		CustomerID: 1,
-		Quantity:   20,
-		Available:  20,
-		Type:       constant.IncomeType,
+		MovementID:  5,
+		ExternalID:  5,
+		ExternalRef: "5",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  1,
+		Quantity:    2000,
+		Available:   2000,
+		Type:        constant.IncomeType,
 	},
 	{
-		MovementID: 6,
-		CustomerID: 1,
-		Quantity:   100,
-		Available:  100,
-		Type:       constant.IncomeType,
+		MovementID:  6,
+		ExternalID:  6,
+		ExternalRef: "6",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  1,
+		Quantity:    10000,
+		Available:   10000,
+		Type:        constant.IncomeType,
 	},
 	{
-		MovementID: 7,
-		CustomerID: 1,
-		Quantity:   75,
-		Available:  25,
-		Type:       constant.OutcomeType,
+		MovementID:  7,
+		ExternalID:  7,
+		ExternalRef: "7",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  1,
+		Quantity:    7500,
+		Available:   2500,
+		Type:        constant.OutcomeType,
 	},
 	{
-		MovementID: 8,
-		CustomerID: 1,
-		Quantity:   12,
-		Available:  12,
-		Type:       constant.IncomeType,
+		MovementID:  8,
+		ExternalID:  8,
+		ExternalRef: "8",
+		Source:      "default",
+		Currency:    "MXN",
+		CustomerID:  1,
+		Quantity:    1200,
+		Available:   1200,
+		Type:        constant.IncomeType,
 	},
 }
 
@@ -124,6 +160,49 @@ addFixtures adds fixtures to the database
 func addFixtures(tx *gorm.DB) {
 	tx.Unscoped().Where("1=1").Delete(&entity.Movement{}) // cleaning users
 	tx.Create(movements)
+	tx.Exec("SELECT setval('movement_movement_id_seq', MAX(movement_id)) FROM movement") // the fixtures have their ids
+}
+
+/*
+addImportBatchFixtures adds two import batches of the customer 1, the first one with the movements 5 and 6
+and the second one with the movements 7 and 8
+*/
+func addImportBatchFixtures(tx *gorm.DB) {
+	tx.Unscoped().Where("1=1").Delete(&entity.ImportBatch{}) // cleaning batches
+	tx.Create([]entity.ImportBatch{
+		{ImportBatchID: 1, CustomerID: 1, FileHash: strings.Repeat("a", 64), StartedAt: time.Now()},
+		{ImportBatchID: 2, CustomerID: 1, FileHash: strings.Repeat("b", 64), StartedAt: time.Now()},
+	})
+	tx.Model(&entity.Movement{}).Where("movement_id IN ?", []int{5, 6}).Update("import_batch_id", 1)
+	tx.Model(&entity.Movement{}).Where("movement_id IN ?", []int{7, 8}).Update("import_batch_id", 2)
+}
+
+/*
+addDateFixtures sets the dates of the movements of the customer 1, the movement 7 is older than the movements 5 and 6
+*/
+func addDateFixtures(tx *gorm.DB) {
+	dates := map[int]time.Time{
+		1: time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC),
+		2: time.Date(2022, time.January, 2, 0, 0, 0, 0, time.UTC),
+		3: time.Date(2022, time.January, 3, 0, 0, 0, 0, time.UTC),
+		5: time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC),
+		6: time.Date(2022, time.February, 2, 0, 0, 0, 0, time.UTC),
+		7: time.Date(2022, time.January, 15, 0, 0, 0, 0, time.UTC),
+		8: time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for movementID, date := range dates {
+		tx.Model(&entity.Movement{}).Where("movement_id = ?", movementID).Update("date", date)
+	}
+}
+
+/*
+addCategoryFixtures adds a rule that categorized the movement 3 and a category from the file to the movements 2 and 4
+*/
+func addCategoryFixtures(tx *gorm.DB) {
+	tx.Unscoped().Where("1=1").Delete(&entity.CategoryRule{}) // cleaning rules
+	tx.Create(&entity.CategoryRule{CategoryRuleID: 1, Category: "Salary"})
+	tx.Model(&entity.Movement{}).Where("movement_id = ?", 3).Updates(map[string]interface{}{"category": "Salary", "category_rule_id": 1})
+	tx.Model(&entity.Movement{}).Where("movement_id IN ?", []int{2, 4}).Update("category", "Rent")
 }
 
 func TestGormRepository(t *testing.T) {
@@ -146,12 +225,56 @@ func TestGormRepository(t *testing.T) {
 				tx.Model(&entity.Movement{}).Count(&movementCount)
 				assert.Equal(t, int64(len(movements)), movementCount)
 
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Generating the movement ids", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+				newMovements := []entity.Movement{
+					{ExternalID: 1, ExternalRef: "1", Source: "default", Currency: "MXN", CustomerID: 2, Quantity: 1000, Available: 1000, Type: constant.IncomeType},
+					{ExternalID: 1, ExternalRef: "1", Source: "latam", Currency: "MXN", CustomerID: 1, Quantity: 1000, Available: 2500, Type: constant.IncomeType},
+				}
+
+				err := rMovement.BulkCreate(newMovements)
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var movementIDs []int
+				tx.Model(&entity.Movement{}).Where("external_id = ?", 1).Order("movement_id").Pluck("movement_id", &movementIDs)
+				assert.Len(t, movementIDs, 4)
+				assert.Greater(t, movementIDs[3], movementIDs[2])
+
 				t.Cleanup(func() {
 					tx.Rollback()
 				})
 			})
 		})
 		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("External id repeated for the customer and source", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				err := rMovement.BulkCreate([]entity.Movement{
+					{ExternalID: 1, ExternalRef: "1", Source: "default", Currency: "MXN", CustomerID: 1, Quantity: 1000, Available: 2500, Type: constant.IncomeType},
+				})
+
+				// data assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
 			t.Run("Table doesn't exists", func(t *testing.T) {
 				connection := database.GetStoriGormConnection()
 				tx := connection.Begin()
@@ -184,7 +307,7 @@ func TestGormRepository(t *testing.T) {
 				rMovement := NewMovementGormRepo(tx)
 
 				go func() {
-					got, err := rMovement.GetLastMovementByCustomerID(customerIDToFind)
+					got, err := rMovement.GetLastMovementByCustomerID(customerIDToFind, "MXN")
 					channelResult1 <- result{got, err, time.Now()}
 					time.Sleep(500 * time.Millisecond)
 					tx.Commit()
@@ -199,7 +322,7 @@ func TestGormRepository(t *testing.T) {
 				// assertions
 				result1 := <-channelResult1
 				assert.NoError(t, result1.Err)
-				assert.True(t, cmp.Equal(result1.Movement, &movements[0], cmpopts.IgnoreTypes(time.Time{})))
+				assert.True(t, cmp.Equal(result1.Movement, &movements[7], cmpopts.IgnoreTypes(time.Time{})))
 				// tries to update but still nil because the lock is not released
 				result2 := <-channelResult2
 				assert.Nil(t, result2.Movement)
@@ -222,7 +345,7 @@ func TestGormRepository(t *testing.T) {
 				rMovement := NewMovementGormRepo(tx)
 
 				// action
-				got, err := rMovement.GetLastMovementByCustomerID(customerIDToFind)
+				got, err := rMovement.GetLastMovementByCustomerID(customerIDToFind, "MXN")
 
 				// assertions
 				assert.Nil(t, got)
@@ -233,6 +356,24 @@ func TestGormRepository(t *testing.T) {
 					connection.Unscoped().Where("1=1").Delete(&entity.Customer{})
 				})
 			})
+			t.Run("Currency without movements", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				addFixtures(connection)
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+
+				// action
+				got, err := rMovement.GetLastMovementByCustomerID(customers[0].CustomerID, "USD")
+
+				// assertions
+				assert.Nil(t, got)
+				assert.ErrorIs(t, err, errors.ErrNotFound)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+					connection.Unscoped().Where("1=1").Delete(&entity.Customer{})
+				})
+			})
 			t.Run("Table doesn't exist", func(t *testing.T) {
 				connection := database.GetStoriGormConnection()
 				tx := connection.Begin()
@@ -240,7 +381,229 @@ func TestGormRepository(t *testing.T) {
 				tx.Unscoped().Where("1=1").Delete(&entity.Movement{}) //Cleaning customers
 				tx.Migrator().DropTable(&entity.Movement{})
 
-				got, err := rMovement.GetLastMovementByCustomerID(1)
+				got, err := rMovement.GetLastMovementByCustomerID(1, "MXN")
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("GetLastMovementsAtDate", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Getting the last movement at the date of each currency", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				tx.Model(&entity.Movement{}).Where("movement_id = ?", 8).Update("currency", "USD")
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.GetLastMovementsAtDate(1, time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC))
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Len(t, got, 2)
+				assert.Equal(t, "MXN", got[0].Currency)
+				assert.Equal(t, 6, got[0].MovementID)
+				assert.Equal(t, "USD", got[1].Currency)
+				assert.Equal(t, 8, got[1].MovementID)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Getting a backdated movement", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.GetLastMovementsAtDate(1, time.Date(2022, time.January, 20, 0, 0, 0, 0, time.UTC))
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Len(t, got, 1)
+				assert.Equal(t, 7, got[0].MovementID)
+				assert.Equal(t, money.Amount(2500), got[0].Available)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("No movements at the date", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.GetLastMovementsAtDate(1, time.Date(2021, time.December, 31, 0, 0, 0, 0, time.UTC))
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.GetLastMovementsAtDate(1, time.Now())
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("GetTotalsByCustomerID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Getting the totals of each currency in the window", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				tx.Model(&entity.Movement{}).Where("movement_id = ?", 8).Update("currency", "USD")
+				rMovement := NewMovementGormRepo(tx)
+				from := time.Date(2022, time.January, 2, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+				got, err := rMovement.GetTotalsByCustomerID(1, &from, &to)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Equal(t, []dto.CurrencyTotals{
+					{Currency: "MXN", TotalIncome: 13000, TotalOutcome: 8000},
+					{Currency: "USD", TotalIncome: 1200, TotalOutcome: 0},
+				}, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Getting the totals until a day", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+				to := time.Date(2022, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+				got, err := rMovement.GetTotalsByCustomerID(1, nil, &to)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Equal(t, []dto.CurrencyTotals{{Currency: "MXN", TotalIncome: 1000, TotalOutcome: 500}}, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.GetTotalsByCustomerID(1, nil, nil)
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindExistingExternalRefs", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding existing references", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				tx.Delete(&movements[1]) // deleted movements can be imported again
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.FindExistingExternalRefs(1, "default", []string{"1", "2", "3", "50", "51"})
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, []string{"1", "3"}, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("References of another customer", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.FindExistingExternalRefs(2, "default", []string{"1", "2", "3"})
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("References of another source", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.FindExistingExternalRefs(1, "latam", []string{"1", "2", "3"})
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.FindExistingExternalRefs(1, "default", []string{"1"})
 
 				//Data Assertion
 				assert.Nil(t, got)
@@ -252,6 +615,725 @@ func TestGormRepository(t *testing.T) {
 			})
 		})
 	})
+	t.Run("DeleteByImportBatchID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Deleting the movements of the batch", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addImportBatchFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				err := rMovement.DeleteByImportBatchID(1)
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var remainingIDs, deletedIDs []int
+				tx.Model(&entity.Movement{}).Where("customer_id = ?", 1).Pluck("movement_id", &remainingIDs)
+				tx.Unscoped().Model(&entity.Movement{}).Where("deleted_at IS NOT NULL").Pluck("movement_id", &deletedIDs)
+				assert.ElementsMatch(t, []int{1, 2, 3, 7, 8}, remainingIDs)
+				assert.ElementsMatch(t, []int{5, 6}, deletedIDs)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				err := rMovement.DeleteByImportBatchID(1)
+
+				//Data Assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("GetLastMovementBeforeDate", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Getting the last movement before the date", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.GetLastMovementBeforeDate(1, "MXN", time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC))
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 7, got.MovementID)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("No movements before the date", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.GetLastMovementBeforeDate(1, "MXN", time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+				// data assertion
+				assert.Nil(t, got)
+				assert.ErrorIs(t, err, errors.ErrNotFound)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.GetLastMovementBeforeDate(1, "MXN", time.Now())
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("UpdateAvailableFromDate", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				Name      string
+				Currency  string
+				DeletedID int
+				Expected  map[int]money.Amount
+			}{
+				{
+					Name:     "Changing the available from the date in order of date",
+					Currency: "MXN",
+					Expected: map[int]money.Amount{1: 1000, 2: 500, 3: 1500, 5: -4000, 6: 6000, 7: -6000, 8: 7200},
+				},
+				{
+					Name:      "Skipping the deleted movements",
+					Currency:  "MXN",
+					DeletedID: 6,
+					Expected:  map[int]money.Amount{1: 1000, 2: 500, 3: 1500, 5: -4000, 6: 10000, 7: -6000, 8: -2800},
+				},
+				{
+					Name:     "Keeping the available of other currencies",
+					Currency: "USD",
+					Expected: map[int]money.Amount{1: 1000, 2: 500, 3: 1500, 5: 2000, 6: 10000, 7: 2500, 8: 1200},
+				},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					connection := database.GetStoriGormConnection()
+					tx := connection.Begin()
+					addForeignFixtures(tx)
+					addFixtures(tx)
+					addDateFixtures(tx)
+					if testCase.DeletedID != 0 {
+						tx.Delete(&entity.Movement{}, testCase.DeletedID)
+					}
+					rMovement := NewMovementGormRepo(tx)
+
+					err := rMovement.UpdateAvailableFromDate(1, testCase.Currency, time.Date(2022, time.January, 15, 0, 0, 0, 0, time.UTC), 1500)
+
+					// data assertion
+					assert.NoError(t, err)
+
+					// database assertion
+					var got []entity.Movement
+					tx.Unscoped().Where("customer_id = ?", 1).Find(&got)
+					available := map[int]money.Amount{}
+					for _, movement := range got {
+						available[movement.MovementID] = movement.Available
+					}
+					assert.Equal(t, testCase.Expected, available)
+
+					t.Cleanup(func() {
+						tx.Rollback()
+					})
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				err := rMovement.UpdateAvailableFromDate(1, "MXN", time.Now(), 0)
+
+				//Data Assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	/*
+		addOverdraftFixtures leaves the movement 7 of the import batch 2 below zero in MXN, the movement 8 of the same batch
+		below zero in USD and the movement 5 of the import batch 1 below zero in MXN
+	*/
+	addOverdraftFixtures := func(tx *gorm.DB, usdAvailable money.Amount) {
+		tx.Model(&entity.Movement{}).Where("1=1").Update("exchange_rate", money.OneRate)
+		tx.Model(&entity.Movement{}).Where("movement_id = ?", 7).Update("available", money.Amount(-2500))
+		tx.Model(&entity.Movement{}).Where("movement_id = ?", 5).Update("available", money.Amount(-4000))
+		tx.Model(&entity.Movement{}).Where("movement_id = ?", 8).Updates(map[string]interface{}{
+			"currency":      "USD",
+			"available":     usdAvailable,
+			"exchange_rate": 20 * money.OneRate,
+		})
+	}
+	t.Run("FindFirstOverdrawn", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				Name          string
+				ImportBatchID int
+				Expected      []int
+			}{
+				{
+					Name:          "Finding the first movement below zero of each currency",
+					ImportBatchID: 2,
+					Expected:      []int{7, 8},
+				},
+				{
+					Name:          "Finding only the movements of the import batch",
+					ImportBatchID: 1,
+					Expected:      []int{5},
+				},
+				{
+					Name:          "Not finding movements below zero",
+					ImportBatchID: 3,
+					Expected:      []int{},
+				},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					connection := database.GetStoriGormConnection()
+					tx := connection.Begin()
+					addForeignFixtures(tx)
+					addFixtures(tx)
+					addDateFixtures(tx)
+					addImportBatchFixtures(tx)
+					addOverdraftFixtures(tx, -100)
+					rMovement := NewMovementGormRepo(tx)
+
+					got, err := rMovement.FindFirstOverdrawn(testCase.ImportBatchID)
+
+					// data assertion
+					assert.NoError(t, err)
+					movementIDs := []int{}
+					for _, movement := range got {
+						movementIDs = append(movementIDs, movement.MovementID)
+					}
+					assert.Equal(t, testCase.Expected, movementIDs)
+
+					t.Cleanup(func() {
+						tx.Rollback()
+					})
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.FindFirstOverdrawn(1)
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindFirstOverCreditLimit", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the movement that leaves the balance of all currencies below the credit limit", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				addImportBatchFixtures(tx)
+				// 100.00 MXN of the movement 6 minus 10.00 USD at 20 MXN each
+				addOverdraftFixtures(tx, -1000)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.FindFirstOverCreditLimit(1, 2, 5000)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Equal(t, 8, got.MovementID)
+				assert.Equal(t, 8, got.ExternalID)
+				assert.Equal(t, money.Amount(-10000), got.Available)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			testCases := []struct {
+				Name          string
+				ImportBatchID int
+				USDAvailable  money.Amount
+				CreditLimit   money.Amount
+				DeletedID     int
+			}{
+				{
+					Name:          "Overdraft of a currency covered by the other currencies",
+					ImportBatchID: 2,
+					USDAvailable:  -100,
+				},
+				{
+					Name:          "Balance of all currencies within the credit limit",
+					ImportBatchID: 2,
+					USDAvailable:  -1000,
+					CreditLimit:   20000,
+				},
+				{
+					Name:          "Balance below the credit limit with a deleted movement",
+					ImportBatchID: 2,
+					USDAvailable:  -1000,
+					DeletedID:     8,
+				},
+				{
+					Name:          "Balance below the credit limit in another import batch",
+					ImportBatchID: 2,
+					USDAvailable:  0,
+				},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					connection := database.GetStoriGormConnection()
+					tx := connection.Begin()
+					addForeignFixtures(tx)
+					addFixtures(tx)
+					addDateFixtures(tx)
+					addImportBatchFixtures(tx)
+					addOverdraftFixtures(tx, testCase.USDAvailable)
+					if testCase.DeletedID != 0 {
+						tx.Delete(&entity.Movement{}, testCase.DeletedID)
+					}
+					rMovement := NewMovementGormRepo(tx)
+
+					got, err := rMovement.FindFirstOverCreditLimit(1, testCase.ImportBatchID, testCase.CreditLimit)
+
+					// data assertion
+					assert.Nil(t, got)
+					assert.ErrorIs(t, err, errors.ErrNotFound)
+
+					t.Cleanup(func() {
+						tx.Rollback()
+					})
+				})
+			}
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.FindFirstOverCreditLimit(1, 1, 0)
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindFirstDatesByImportBatchID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the first date of each currency", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addDateFixtures(tx)
+				addImportBatchFixtures(tx)
+				tx.Model(&entity.Movement{}).Where("movement_id = ?", 8).Update("currency", "USD")
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.FindFirstDatesByImportBatchID(2)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Len(t, got, 2)
+				assert.True(t, got["MXN"].Equal(time.Date(2022, time.January, 15, 0, 0, 0, 0, time.UTC)))
+				assert.True(t, got["USD"].Equal(time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)))
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+			t.Run("Batch without movements", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.FindFirstDatesByImportBatchID(50)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.FindFirstDatesByImportBatchID(1)
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindByCustomerID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			from := time.Date(2022, time.January, 2, 0, 0, 0, 0, time.UTC)
+			to := time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC)
+			minAmount := money.Amount(1000)
+			maxAmount := money.Amount(2000)
+			testCases := []struct {
+				Name          string
+				Filter        dto.MovementFilter
+				Page          int
+				PageSize      int
+				ExpectedIDs   []int
+				ExpectedCount int64
+			}{
+				{
+					Name:          "Finding the first page from the newest",
+					Filter:        dto.MovementFilter{Sort: constant.MovementSortDateDesc},
+					Page:          1,
+					PageSize:      3,
+					ExpectedIDs:   []int{8, 6, 5},
+					ExpectedCount: 7,
+				},
+				{
+					Name:          "Finding the second page from the newest",
+					Filter:        dto.MovementFilter{Sort: constant.MovementSortDateDesc},
+					Page:          2,
+					PageSize:      3,
+					ExpectedIDs:   []int{7, 3, 2},
+					ExpectedCount: 7,
+				},
+				{
+					Name:          "Finding the incomes of a date range",
+					Filter:        dto.MovementFilter{From: &from, To: &to, Type: constant.IncomeType, Sort: constant.MovementSortDate},
+					Page:          1,
+					PageSize:      20,
+					ExpectedIDs:   []int{3, 5},
+					ExpectedCount: 2,
+				},
+				{
+					Name:          "Finding an amount range from the smallest",
+					Filter:        dto.MovementFilter{MinAmount: &minAmount, MaxAmount: &maxAmount, Sort: constant.MovementSortAmount},
+					Page:          1,
+					PageSize:      20,
+					ExpectedIDs:   []int{1, 3, 8, 5},
+					ExpectedCount: 4,
+				},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					connection := database.GetStoriGormConnection()
+					tx := connection.Begin()
+					addForeignFixtures(tx)
+					addFixtures(tx)
+					addDateFixtures(tx)
+					tx.Exec("UPDATE movement SET home_quantity = quantity")
+					rMovement := NewMovementGormRepo(tx)
+					pagination := dto.NewPagination(testCase.Page, testCase.PageSize, 0)
+
+					got, err := rMovement.FindByCustomerID(1, testCase.Filter, pagination)
+
+					// data assertion
+					assert.NoError(t, err)
+					gotIDs := []int{}
+					for _, movement := range got {
+						gotIDs = append(gotIDs, movement.MovementID)
+					}
+					assert.Equal(t, testCase.ExpectedIDs, gotIDs)
+					assert.Equal(t, testCase.ExpectedCount, pagination.TotalCount)
+
+					t.Cleanup(func() {
+						tx.Rollback()
+					})
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.FindByCustomerID(1, dto.MovementFilter{}, dto.NewPagination(1, 20, 0))
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindPageByCustomerID", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			date := func(month time.Month, day int) time.Time {
+				return time.Date(2022, month, day, 0, 0, 0, 0, time.UTC)
+			}
+			testCases := []struct {
+				Name         string
+				Sort         string
+				Cursor       *dto.Cursor
+				ExpectedIDs  []int
+				ExpectedNext int
+				ExpectedPrev int
+			}{
+				{
+					Name:         "Finding the first page from the newest",
+					Sort:         constant.MovementSortDateDesc,
+					ExpectedIDs:  []int{8, 6, 5},
+					ExpectedNext: 5,
+				},
+				{
+					Name:         "Finding the page after a cursor",
+					Sort:         constant.MovementSortDateDesc,
+					Cursor:       &dto.Cursor{Date: date(time.February, 1), MovementID: 5},
+					ExpectedIDs:  []int{7, 3, 2},
+					ExpectedNext: 2,
+					ExpectedPrev: 7,
+				},
+				{
+					Name:         "Finding the last page",
+					Sort:         constant.MovementSortDateDesc,
+					Cursor:       &dto.Cursor{Date: date(time.January, 2), MovementID: 2},
+					ExpectedIDs:  []int{1},
+					ExpectedPrev: 1,
+				},
+				{
+					Name:         "Finding the page before a cursor",
+					Sort:         constant.MovementSortDateDesc,
+					Cursor:       &dto.Cursor{Date: date(time.January, 15), MovementID: 7, Backward: true},
+					ExpectedIDs:  []int{8, 6, 5},
+					ExpectedNext: 5,
+				},
+				{
+					Name:         "Finding the page before a cursor with more pages before it",
+					Sort:         constant.MovementSortDateDesc,
+					Cursor:       &dto.Cursor{Date: date(time.January, 1), MovementID: 1, Backward: true},
+					ExpectedIDs:  []int{7, 3, 2},
+					ExpectedNext: 2,
+					ExpectedPrev: 7,
+				},
+				{
+					Name:         "Finding the first page from the oldest",
+					Sort:         constant.MovementSortDate,
+					ExpectedIDs:  []int{1, 2, 3},
+					ExpectedNext: 3,
+				},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					connection := database.GetStoriGormConnection()
+					tx := connection.Begin()
+					addForeignFixtures(tx)
+					addFixtures(tx)
+					addDateFixtures(tx)
+					rMovement := NewMovementGormRepo(tx)
+					pagination := dto.NewCursorPagination(3, testCase.Cursor)
+
+					got, err := rMovement.FindPageByCustomerID(1, dto.MovementFilter{Sort: testCase.Sort}, pagination)
+
+					// data assertion
+					assert.NoError(t, err)
+					gotIDs := []int{}
+					for _, movement := range got {
+						gotIDs = append(gotIDs, movement.MovementID)
+					}
+					assert.Equal(t, testCase.ExpectedIDs, gotIDs)
+					if testCase.ExpectedNext != 0 {
+						assert.Equal(t, testCase.ExpectedNext, pagination.Next.MovementID)
+						assert.False(t, pagination.Next.Backward)
+					} else {
+						assert.Nil(t, pagination.Next)
+					}
+					if testCase.ExpectedPrev != 0 {
+						assert.Equal(t, testCase.ExpectedPrev, pagination.Prev.MovementID)
+						assert.True(t, pagination.Prev.Backward)
+					} else {
+						assert.Nil(t, pagination.Prev)
+					}
+
+					t.Cleanup(func() {
+						tx.Rollback()
+					})
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.FindPageByCustomerID(1, dto.MovementFilter{}, dto.NewCursorPagination(20, nil))
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("FindCategorizable", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the movements without a category from the file", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addCategoryFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				got, err := rMovement.FindCategorizable(1, 1, 3)
+
+				// data assertion
+				assert.NoError(t, err)
+				gotIDs := []int{}
+				for _, movement := range got {
+					gotIDs = append(gotIDs, movement.MovementID)
+				}
+				assert.Equal(t, []int{3, 5, 6}, gotIDs)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Table doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				rMovement := NewMovementGormRepo(tx)
+				tx.Migrator().DropTable(&entity.Movement{})
+
+				got, err := rMovement.FindCategorizable(1, 0, 3)
+
+				//Data Assertion
+				assert.Nil(t, got)
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
+	t.Run("UpdateCategory", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Clearing the category of a rule", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				addCategoryFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+
+				err := rMovement.UpdateCategory(3, "", nil)
+
+				// data assertion
+				assert.NoError(t, err)
+
+				// database assertion
+				var got entity.Movement
+				tx.First(&got, 3)
+				assert.Empty(t, got.Category)
+				assert.Nil(t, got.CategoryRuleID)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Rule doesn't exist", func(t *testing.T) {
+				connection := database.GetStoriGormConnection()
+				tx := connection.Begin()
+				addForeignFixtures(tx)
+				addFixtures(tx)
+				rMovement := NewMovementGormRepo(tx)
+				categoryRuleID := 50
+
+				err := rMovement.UpdateCategory(3, "Groceries", &categoryRuleID)
+
+				//Data Assertion
+				assert.Error(t, err)
+
+				t.Cleanup(func() {
+					tx.Rollback()
+				})
+			})
+		})
+	})
 	t.Run("Clone", func(t *testing.T) {
 		db := database.GetStoriGormConnection()
 		rMovement := NewMovementGormRepo(db)
@@ -3,6 +3,7 @@ package movement
 import (
 	"net/http"
 	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/libs/middleware"
 	"stori-service/src/utils/helpers"
 
 	"github.com/gorilla/mux"
@@ -29,6 +30,50 @@ func (r *movementRouter) routes(subRouter *mux.Router) {
 		Path(`/{id}`).
 		Handler(helpers.Middleware(
 			http.HandlerFunc(r.cMovement.ProcessFile),
+			middleware.DeprecationMiddleware(importsRoute),
+		)).
+		Methods(http.MethodGet)
+	subRouter.
+		Path(`/{id}/imports`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cMovement.ProcessFile),
+		)).
+		Methods(http.MethodPost)
+	subRouter.
+		Path(`/{id}/files`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cMovement.UploadFile),
+		)).
+		Methods(http.MethodPost)
+}
+
+/*
+importsRoute returns the route that replaces the deprecated GET of a customer file, with its query
+*/
+func importsRoute(request *http.Request) string {
+	route := request.URL.Path + "/imports"
+	if request.URL.RawQuery != "" {
+		route += "?" + request.URL.RawQuery
+	}
+	return route
+}
+
+/*
+NewCustomerMovementRouter assigns the routes of the movements under a customer
+*/
+func NewCustomerMovementRouter(subRouter *mux.Router, cMovement interfaces.IMovementController) {
+	routerMovement := movementRouter{cMovement}
+	routerMovement.customerRoutes(subRouter)
+}
+
+/*
+customerRoutes assigns controller function for routes under a customer
+*/
+func (r *movementRouter) customerRoutes(subRouter *mux.Router) {
+	subRouter.
+		Path(`/{id}/movements`).
+		Handler(helpers.Middleware(
+			http.HandlerFunc(r.cMovement.List),
 		)).
 		Methods(http.MethodGet)
 }
@@ -16,15 +16,27 @@ func TestNewMovementRouter(t *testing.T) {
 	t.Run("Routes with controller mock", func(t *testing.T) {
 		t.Run("Should success on", func(t *testing.T) {
 			testCases := []struct {
-				Path    string
-				Method  string
-				Handler string
+				Path       string
+				Method     string
+				Handler    string
+				Deprecated bool
 			}{
 				{
-					Path:    "/{id}",
-					Method:  http.MethodGet,
+					Path:       "/{id}",
+					Method:     http.MethodGet,
+					Handler:    "ProcessFile",
+					Deprecated: true,
+				},
+				{
+					Path:    "/{id}/imports",
+					Method:  http.MethodPost,
 					Handler: "ProcessFile",
 				},
+				{
+					Path:    "/{id}/files",
+					Method:  http.MethodPost,
+					Handler: "UploadFile",
+				},
 			}
 
 			for _, testCase := range testCases {
@@ -56,8 +68,50 @@ func TestNewMovementRouter(t *testing.T) {
 					assert.NoError(t, err)
 					assert.NotNil(t, res)
 					assert.Equal(t, http.StatusTeapot, res.StatusCode)
+					if testCase.Deprecated {
+						assert.Equal(t, "true", res.Header.Get("Deprecation"))
+						assert.Equal(t, fmt.Sprintf(`<%s%s/imports>; rel="successor-version"`, subRouterPath, testCase.Path), res.Header.Get("Link"))
+					} else {
+						assert.Empty(t, res.Header.Get("Deprecation"))
+					}
 				})
 			}
 		})
 	})
 }
+
+func TestNewCustomerMovementRouter(t *testing.T) {
+	t.Run("Routes with controller mock", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Method: GET Path: /{id}/movements Handler: List", func(t *testing.T) {
+				muxRouter := mux.NewRouter()
+				subRouterPath := "/test"
+				subRouter := muxRouter.PathPrefix(subRouterPath).Subrouter()
+				mockMovementC := new(mock.ClientMovementController)
+				NewCustomerMovementRouter(subRouter, mockMovementC)
+				mockMovementC.On(
+					"List",
+					testifyMock.AnythingOfType("*http.response"),
+					testifyMock.AnythingOfType("*http.Request"),
+				).Run(func(args testifyMock.Arguments) {
+					firstArgument := args[0]
+					response := firstArgument.(http.ResponseWriter)
+					response.WriteHeader(http.StatusTeapot) //using teapot status, to ensure it in assertions
+				})
+				ts := httptest.NewServer(muxRouter)
+				URL := fmt.Sprint(ts.URL, subRouterPath, "/{id}/movements")
+				req, _ := http.NewRequest(http.MethodGet, URL, nil)
+				res, err := ts.Client().Do(req)
+
+				// mock assertion: Behavioural
+				mockMovementC.AssertExpectations(t)
+				mockMovementC.AssertNumberOfCalls(t, "List", 1)
+
+				// data assertion
+				assert.NoError(t, err)
+				assert.NotNil(t, res)
+				assert.Equal(t, http.StatusTeapot, res.StatusCode)
+			})
+		})
+	})
+}
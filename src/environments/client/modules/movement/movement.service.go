@@ -1,140 +1,879 @@
 package movement
 
 import (
-	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	goerrors "errors"
 	"fmt"
-	"math"
-	"os"
+	"io"
+	"sort"
 	"stori-service/src/environments/client/resources/interfaces"
 	"stori-service/src/environments/common/resources/entity"
+	commonInterfaces "stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/categorizer"
 	"stori-service/src/libs/dto"
 	"stori-service/src/libs/email"
 	"stori-service/src/libs/env"
 	"stori-service/src/libs/errors"
+	"stori-service/src/libs/i18n"
+	"stori-service/src/libs/money"
+	"stori-service/src/libs/statement"
+	"stori-service/src/utils/constant"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+)
+
+// maximum number of characters of the texts of a movement, the size of their columns
+const (
+	maxDescriptionLength = 255
+	maxMerchantLength    = 100
+	maxCategoryLength    = 50
+	maxReferenceLength   = 255
 )
 
 var (
-	getPath = func(customerID int) string { // declared here for easy testing with spy
-		return env.FileRoute + "/customer_" + strconv.Itoa(customerID) + ".csv"
-	}
+	timeNow   = time.Now            // declared here for easy testing with spy
+	batchSize = env.ImportBatchSize // declared here to test files with several batches
 )
 
 /*
 Struct that implements IMovementService
 */
 type movementService struct {
-	rMovement interfaces.IMovementRepository
-	rCustomer interfaces.ICustomerRepository
+	rMovement     interfaces.IMovementRepository
+	rCustomer     interfaces.ICustomerRepository
+	rImportBatch  interfaces.IImportBatchRepository
+	sExchangeRate interfaces.IExchangeRateService
+	sBalance      interfaces.IBalanceService
+	sLedger       interfaces.ILedgerService
+	sCategoryRule interfaces.ICategoryRuleService
+	fileSource    commonInterfaces.IFileSource
 }
 
 /*
-	NewMovementService creates a new service, receives repository by dependency injection
-	and returns IRepositoryService, so it needs to implement all its methods
+	NewMovementService creates a new service, receives repositories, exchange rate, balance, ledger and category rule services
+	and file source by dependency injection and returns IRepositoryService, so it needs to implement all its methods
 */
-func NewMovementService(rMovement interfaces.IMovementRepository, rCustomer interfaces.ICustomerRepository) interfaces.IMovementService {
-	return &movementService{rMovement, rCustomer}
+func NewMovementService(rMovement interfaces.IMovementRepository, rCustomer interfaces.ICustomerRepository, rImportBatch interfaces.IImportBatchRepository, sExchangeRate interfaces.IExchangeRateService, sBalance interfaces.IBalanceService, sLedger interfaces.ILedgerService, sCategoryRule interfaces.ICategoryRuleService, fileSource commonInterfaces.IFileSource) interfaces.IMovementService {
+	return &movementService{rMovement, rCustomer, rImportBatch, sExchangeRate, sBalance, sLedger, sCategoryRule, fileSource}
 }
 
 /*
 ProcessFile takes a customerID, check if the customer exists and process that user file
 */
-func (s *movementService) ProcessFile(customerID int) (*dto.MovementList, error) {
+func (s *movementService) ProcessFile(customerID int, options dto.ImportOptions) (*dto.MovementList, error) {
+	name := getFileName(customerID, options.Format)
+	return s.processMovements(customerID, options, name, func() (io.ReadSeekCloser, error) {
+		return s.fileSource.Open(name)
+	})
+}
+
+/*
+ProcessUpload takes a customerID and an uploaded file, check if the customer exists
+and process the uploaded file the same way as ProcessFile does, the format is guessed by the content
+when the options don't set it
+*/
+func (s *movementService) ProcessUpload(customerID int, file io.ReadSeeker, options dto.ImportOptions) (*dto.MovementList, error) {
+	return s.processMovements(customerID, options, "", func() (io.ReadSeekCloser, error) {
+		return nopSeekCloser{file}, nil
+	})
+}
+
+/*
+FindImportedFile returns the import batch of the customer file when the customer already imported the same content,
+ErrNotFound when it wasn't imported
+*/
+func (s *movementService) FindImportedFile(customerID int, options dto.ImportOptions) (*entity.ImportBatch, error) {
+	file, err := s.fileSource.Open(getFileName(customerID, options.Format))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return s.findImportBatch(customerID, file)
+}
+
+/*
+FindImportedUpload returns the import batch of the uploaded file when the customer already imported the same content,
+ErrNotFound when it wasn't imported. The file is left at its start
+*/
+func (s *movementService) FindImportedUpload(customerID int, file io.ReadSeeker) (*entity.ImportBatch, error) {
+	return s.findImportBatch(customerID, file)
+}
+
+/*
+FindByCustomerID takes a customerID, check if the customer exists and returns the page of its movements
+that pass the filter, the pagination gets the total count of them
+*/
+func (s *movementService) FindByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.Pagination) ([]entity.Movement, error) {
+	_, err := s.rCustomer.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.rMovement.FindByCustomerID(customerID, filter, pagination)
+}
+
+/*
+FindPageByCustomerID takes a customerID, check if the customer exists and returns the page of its movements
+that pass the filter at the cursor of the pagination, the pagination gets the cursors of the pages around it.
+Cursors are positions in the order of date, so the filter can only sort by date
+*/
+func (s *movementService) FindPageByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.CursorPagination) ([]entity.Movement, error) {
+	if filter.Sort != constant.MovementSortDate && filter.Sort != constant.MovementSortDateDesc {
+		return nil, errors.ErrFieldValidation("sort", "oneof", constant.MovementSortDate+" "+constant.MovementSortDateDesc)
+	}
+	_, err := s.rCustomer.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.rMovement.FindPageByCustomerID(customerID, filter, pagination)
+}
+
+/*
+processMovements locks the customer, opens the file with the given function and reads it in the format
+of the options or the one of its name and content, CSV files with the columns of the import profile,
+then creates the movements with their balance in batches, while the file is read, and sends the email
+with the summary of the import. The balance of the customer is kept for each currency, the lines without
+currency are in the source currency of the options. Each movement keeps its amount and currency and is converted
+to the home currency of the customer with the exchange rate effective on its date, lines without rate are rejected.
+The movements can be older than the ones already imported, so after inserting them the balances of each currency
+are recalculated from the first imported date in order of date and ID. Each inserted movement is posted
+to the ledger in the same transaction.
+The movements without a category in the file get the one of the first category rule of the customer,
+or global, that matches them.
+A movement that leaves a balance below zero breaks the overdraft policy of the customer unless it allows it:
+with the reject policy, or a credit limit that the balance goes over, the import is rolled back and the error
+has the first movement that broke it, with the allow and flag policy it's kept in the overdraft of the balance.
+Each import is recorded in an import batch with the SHA-256 of the file, when the customer already imported
+the same content the original batch result is returned without importing it again.
+Invalid lines and IDs that were already imported are collected in the Rejected report, in strict mode
+any of them aborts the import and the list is returned along with the error so the caller still gets
+the report. In partial mode the valid lines are imported and the rejected ones are only reported.
+In preview mode the list and its summary are returned, with the first batch of movements, without saving
+anything nor sending the email.
+*/
+func (s *movementService) processMovements(customerID int, options dto.ImportOptions, name string, openFile func() (io.ReadSeekCloser, error)) (*dto.MovementList, error) {
+	profile, err := statement.GetProfile(options.Profile)
+	if err != nil {
+		return nil, err
+	}
 	rCustomer := s.rCustomer.Clone().(interfaces.ICustomerRepository)
 	rMovement := s.rMovement.Clone().(interfaces.IMovementRepository)
+	rImportBatch := s.rImportBatch.Clone().(interfaces.IImportBatchRepository)
 	tx := rMovement.Begin(nil)
 	rCustomer.Begin(tx)
+	rImportBatch.Begin(tx)
 	defer rMovement.Rollback()
 
-	customer, err := rCustomer.FindAndLockByCustomerID(customerID) // first check that user exists
+	customer, err := rCustomer.FindAndLockByCustomerID(customerID) // then check that user exists
 	if err != nil {
 		return nil, err
 	}
-	path := getPath(customerID)
-	file, err := os.Open(path)
+	homeCurrency := customer.HomeCurrency
+	if homeCurrency == "" {
+		homeCurrency = constant.DefaultCurrency
+	}
+	file, err := openFile()
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
 	}
 	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	scanner.Scan() // skip the first line because it doesnt't have data
 	var movementList dto.MovementList
 	movementList.Customer = customer
-	// declare variables outside for loop to avoid memory leaks
-	var movement *entity.Movement
-	var line []string
-	var lastAvailable float64
-	// get the last movement of the customer to calculate the new balance
-	lastMovement, err := rMovement.GetLastMovementByCustomerID(customerID)
+	fileHash, err := fingerprint(file)
+	if err != nil {
+		return nil, err
+	}
+	// the customer is locked, so the same file can't be imported twice at the same time
+	importBatch, err := rImportBatch.FindByFileHash(customerID, fileHash)
+	if err == nil {
+		movementList.ImportBatch = importBatch
+		movementList.AlreadyImported = true
+		movementList.Summary = summaryFromBatch(importBatch, homeCurrency)
+		return &movementList, nil
+	}
 	if !goerrors.Is(err, errors.ErrNotFound) {
+		return nil, err
+	}
+	if options.DateFormat == "" {
+		options.DateFormat = profile.DateFormat
+	}
+	if options.Source == "" {
+		options.Source = profile.Name
+	}
+	if options.SourceCurrency == "" {
+		options.SourceCurrency = constant.DefaultCurrency
+	}
+	if options.StatementDate.IsZero() {
+		options.StatementDate = timeNow() // without statement period the file is from the last year
+	}
+	profile.DateFormat = options.DateFormat // QIF dates follow the day and month order of the format
+	reader, err := statement.NewReader(file, name, options.Format, profile)
+	if err != nil {
+		return nil, err
+	}
+	rulesCategorizer, err := s.sCategoryRule.NewCategorizer(tx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	movementImport := &movementImport{
+		tx:            tx,
+		rMovement:     rMovement,
+		sExchangeRate: s.sExchangeRate,
+		sLedger:       s.sLedger,
+		categorizer:   rulesCategorizer,
+		homeCurrency:  homeCurrency,
+		options:       options,
+		columns:       reader.Columns(),
+		list:          &movementList,
+	}
+	movementList.Summary = &dto.ImportSummary{
+		MonthlyRows:      make(map[string]int),
+		CategoryOutcomes: make(map[string]money.Amount),
+		Balances:         entity.CurrencyBalances{},
+	}
+	if !options.Preview {
+		movementList.ImportBatch = &entity.ImportBatch{
+			CustomerID: customerID,
+			FileHash:   fileHash,
+			StartedAt:  timeNow(),
+		}
+		err = rImportBatch.Create(movementList.ImportBatch)
 		if err != nil {
 			return nil, err
 		}
-		lastAvailable = lastMovement.Available
 	}
-	for scanner.Scan() {
-		// parse line to movement
-		line = strings.Split(scanner.Text(), ",")
-		// save last available for the next movement
-		if movement != nil {
-			lastAvailable = movement.Available
+	err = s.readFile(reader, movementImport)
+	if err != nil {
+		return nil, err
+	}
+	summary := movementList.Summary
+	summary.RejectedRows = len(movementList.Rejected)
+	sort.Slice(summary.Balances, func(i, j int) bool {
+		return summary.Balances[i].Currency < summary.Balances[j].Currency
+	})
+	setHomeTotals(summary, homeCurrency)
+	// keep the report in the same order as the file
+	sort.SliceStable(movementList.Rejected, func(i, j int) bool {
+		return movementList.Rejected[i].Line < movementList.Rejected[j].Line
+	})
+	if options.Preview {
+		return &movementList, nil
+	}
+	if movementImport.aborted() {
+		// in a real case we would log the report to sentry or something...
+		summary.ImportedRows = 0 // the batches already inserted are rolled back
+		return &movementList, errors.ErrInvalidFileLines.WithTemplate(map[string]interface{}{
+			"Count": len(movementList.Rejected),
+		})
+	}
+	for _, balance := range summary.Balances {
+		firstDate, ok := movementImport.firstDates[balance.Currency]
+		if !ok {
+			continue
 		}
-		movement, err = s.parseLine(line)
+		err = s.sBalance.Recalculate(tx, customerID, balance.Currency, firstDate)
 		if err != nil {
-			// in a real case we would log the error to sentry or something...
 			return nil, err
 		}
-		movement.CustomerID = customerID
-		movement.Available = lastAvailable + (movement.Quantity * float64(movement.Type))
-		// round to 2 decimals
-		movement.Available = math.Round(movement.Available*100) / 100
-		// add movement to list
-		movementList.Movements = append(movementList.Movements, *movement)
 	}
-	err = rMovement.BulkCreate(movementList.Movements)
+	err = s.checkOverdrafts(tx, customer, homeCurrency, &movementList)
+	if goerrors.Is(err, errors.ErrOverdraft) || goerrors.Is(err, errors.ErrCreditLimit) {
+		summary.ImportedRows = 0 // the batches already inserted are rolled back
+		return &movementList, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.finishBatch(rImportBatch, movementList.ImportBatch, summary)
 	if err != nil {
-		if e := err.Error(); strings.Contains(e, "23505") {
-			err = errors.ErrDuplicatedID
-		}
 		return nil, err
 	}
 	err = rMovement.Commit()
 	if err != nil {
 		return nil, err
 	}
-	go email.SendEmail(&movementList)
+	if summary.ImportedRows > 0 {
+		go email.SendEmail(&movementList)
+	}
 	return &movementList, nil
 }
 
 /*
-parseLine takes a line of the file and returns a movement
+checkOverdrafts applies the overdraft policy of the customer to the movements of the import batch: with a credit limit
+the balance of all the currencies in the home currency can't go below it, otherwise each currency can't go below zero
+when the policy rejects overdrafts, or the first overdrawn movement of the currency is flagged in its balance.
+Only the movements of the import are checked, so the overdrafts accepted before aren't reported again
 */
-func (s *movementService) parseLine(line []string) (*entity.Movement, error) {
-	if len(line) != 3 {
-		return nil, errors.ErrInvalidFileLine
+func (s *movementService) checkOverdrafts(tx interface{}, customer *entity.Customer, homeCurrency string, movementList *dto.MovementList) error {
+	importBatchID := movementList.ImportBatch.ImportBatchID
+	if customer.OverdraftPolicy == constant.OverdraftCreditLimit {
+		overdraft, err := s.sBalance.FindCreditLimitOverdraft(tx, customer, importBatchID)
+		if err != nil || overdraft == nil {
+			return err
+		}
+		return errors.ErrCreditLimit.WithTemplate(map[string]interface{}{
+			"ExternalID": overdraft.ExternalID,
+			"Source":     overdraft.Source,
+			"Date":       overdraft.Date.Format(constant.DateLayouts[constant.DateFormatISO]),
+			"Currency":   homeCurrency,
+			"Available":  overdraft.Available.String(),
+		})
 	}
-	var movement entity.Movement
-	movementID, err := strconv.Atoi(line[0])
+	overdrawnMovements, err := s.sBalance.FindOverdrafts(tx, importBatchID)
+	if err != nil {
+		return err
+	}
+	for _, overdrawn := range overdrawnMovements {
+		if customer.OverdraftPolicy == constant.OverdraftReject {
+			return errors.ErrOverdraft.WithTemplate(map[string]interface{}{
+				"ExternalID": overdrawn.ExternalID,
+				"Source":     overdrawn.Source,
+				"Date":       overdrawn.Date.Format(constant.DateLayouts[constant.DateFormatISO]),
+				"Currency":   overdrawn.Currency,
+				"Available":  overdrawn.Available.String(),
+			})
+		}
+		if balance := movementList.Summary.Balances.Find(overdrawn.Currency); balance != nil {
+			balance.Overdraft = &entity.Overdraft{
+				MovementID: overdrawn.MovementID,
+				ExternalID: overdrawn.ExternalID,
+				Source:     overdrawn.Source,
+				Date:       overdrawn.Date,
+				Available:  overdrawn.Available,
+			}
+		}
+	}
+	return nil
+}
+
+/*
+readFile reads the statement record by record, valid movements are added to the import
+and the invalid lines to the Rejected report of the list
+*/
+func (s *movementService) readFile(reader statement.Reader, movementImport *movementImport) error {
+	// declare variables outside for loop to avoid memory leaks
+	var movement *entity.Movement
+	var lineError *entity.ImportLineError
+	for {
+		record, err := reader.Read()
+		if goerrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		movementImport.list.Summary.TotalRows++
+		// parse record to movement
+		movement, lineError = s.parseLine(record, movementImport.columns, movementImport.options)
+		if lineError != nil {
+			movementImport.reject(lineError, record.Line)
+			continue
+		}
+		err = movementImport.add(movement, record.Line)
+		if err != nil {
+			return err
+		}
+	}
+	return movementImport.flush()
+}
+
+/*
+movementImport keeps the state of an import while the file is read: the batch of movements waiting to be inserted,
+so only one batch is in memory at a time, and the running balance of each currency in the balances of the summary.
+The references seen in the file are kept for the whole import, so a repeated one is rejected as duplicated
+even when the first one is in a previous batch, that isn't inserted in a preview.
+The exchange rates to the home currency are kept by currency and date, so each one is looked up only once,
+and the first date inserted of each currency is kept to recalculate the balances from there.
+The categorizer has the category rules of the customer
+*/
+type movementImport struct {
+	tx            interface{}
+	rMovement     interfaces.IMovementRepository
+	sExchangeRate interfaces.IExchangeRateService
+	sLedger       interfaces.ILedgerService
+	categorizer   *categorizer.Categorizer
+	homeCurrency  string
+	options       dto.ImportOptions
+	columns       statement.Columns
+	list          *dto.MovementList
+	batch         []entity.Movement
+	lineNumbers   []int
+	seenRefs      map[string]bool
+	rates         map[string]money.Rate
+	lastMovements map[string]entity.Movement
+	firstDates    map[string]time.Time
+}
+
+/*
+add converts the movement to the home currency and adds it to the batch, it inserts the batch when it's full
+*/
+func (i *movementImport) add(movement *entity.Movement, line int) error {
+	if i.seenRefs == nil {
+		i.seenRefs = make(map[string]bool, batchSize)
+	}
+	if i.seenRefs[movement.ExternalRef] {
+		i.reject(newLineError(i.columns.ID, "IMPORT_LINE.DUPLICATED_ID", nil), line)
+		return nil
+	}
+	rate, err := i.rate(movement.Currency, movement.Date)
+	if goerrors.Is(err, errors.ErrMissingExchangeRate) {
+		i.reject(newLineError(i.columns.Currency, "IMPORT_LINE.MISSING_EXCHANGE_RATE", map[string]interface{}{
+			"From": movement.Currency,
+			"To":   i.homeCurrency,
+			"Date": movement.Date.Format(constant.DateLayouts[constant.DateFormatISO]),
+		}), line)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	movement.ExchangeRate = rate
+	movement.HomeQuantity = movement.Quantity.Convert(rate)
+	i.seenRefs[movement.ExternalRef] = true
+	i.batch = append(i.batch, *movement)
+	i.lineNumbers = append(i.lineNumbers, line)
+	if len(i.batch) >= batchSize {
+		return i.flush()
+	}
+	return nil
+}
+
+/*
+rate returns the exchange rate from the currency to the home currency effective on the date
+*/
+func (i *movementImport) rate(currency string, date time.Time) (money.Rate, error) {
+	key := currency + date.Format(constant.DateLayouts[constant.DateFormatISO])
+	if rate, ok := i.rates[key]; ok {
+		return rate, nil
+	}
+	rate, err := i.sExchangeRate.Rate(currency, i.homeCurrency, date)
+	if err != nil {
+		return 0, err
+	}
+	if i.rates == nil {
+		i.rates = make(map[string]money.Rate)
+	}
+	i.rates[key] = rate
+	return rate, nil
+}
+
+/*
+reject adds the line to the Rejected report of the list
+*/
+func (i *movementImport) reject(lineError *entity.ImportLineError, line int) {
+	lineError.Line = line
+	i.list.Rejected = append(i.list.Rejected, *lineError)
+}
+
+/*
+aborted tells if the import can't be saved, that happens in strict mode when a line was rejected
+*/
+func (i *movementImport) aborted() bool {
+	return i.options.Mode != constant.ImportModePartial && len(i.list.Rejected) > 0
+}
+
+/*
+flush rejects the movements of the batch that are already in database, calculates the balance of the
+rest and inserts them with their journal entries. Nothing is inserted in preview mode, where the first batch is kept
+in the list to be shown, nor after the import was aborted, where the batch is only checked to complete the report.
+The inserted balances are recalculated in order of date, so the preview calculates them in order of date and line
+and the availables of the movements shown include the ones of the next batches with earlier dates
+*/
+func (i *movementImport) flush() error {
+	if len(i.batch) == 0 {
+		return nil
+	}
+	if i.options.Preview {
+		i.sortBatch()
+	}
+	movements, err := i.rejectExistingMovements()
+	if err != nil {
+		return err
+	}
+	summary := i.list.Summary
+	for index := range movements {
+		movement := &movements[index]
+		movement.CustomerID = i.list.Customer.CustomerID
+		movement.Source = i.options.Source
+		if i.list.ImportBatch != nil {
+			movement.ImportBatchID = &i.list.ImportBatch.ImportBatchID
+		}
+		// the category of the file is kept
+		if movement.Category == "" {
+			if categoryRule := i.categorizer.Match(movement); categoryRule != nil {
+				categoryRuleID := categoryRule.CategoryRuleID
+				movement.Category = categoryRule.Category
+				movement.CategoryRuleID = &categoryRuleID
+			}
+		}
+		balance, err := i.balance(movement.Currency)
+		if err != nil {
+			return err
+		}
+		movement.Available = balance.FinalAvailable + movement.Quantity*money.Amount(movement.Type)
+		// save last available for the next movement in the currency
+		balance.FinalAvailable = movement.Available
+		if movement.Type == constant.IncomeType {
+			balance.TotalIncome += movement.Quantity
+			balance.HomeIncome += movement.HomeQuantity
+			balance.IncomeRows++
+			summary.IncomeRows++
+		} else {
+			balance.TotalOutcome += movement.Quantity
+			balance.HomeOutcome += movement.HomeQuantity
+			balance.OutcomeRows++
+			summary.OutcomeRows++
+			if movement.Category != "" {
+				summary.CategoryOutcomes[movement.Category] += movement.HomeQuantity
+			}
+		}
+		// the available is converted with the rate of the last date of the currency
+		if last, ok := i.lastMovements[movement.Currency]; !ok || !movement.Date.Before(last.Date) {
+			if i.lastMovements == nil {
+				i.lastMovements = make(map[string]entity.Movement)
+			}
+			i.lastMovements[movement.Currency] = *movement
+		}
+		balance.HomeAvailable = balance.FinalAvailable.Convert(i.lastMovements[movement.Currency].ExchangeRate)
+		summary.MonthlyRows[movement.Date.Format(constant.MonthLayout)]++
+	}
+	switch {
+	case i.options.Preview:
+		if i.list.Movements == nil {
+			i.list.Movements = movements
+			break
+		}
+		for index := range movements {
+			i.shiftPreview(&movements[index])
+		}
+	case !i.aborted() && len(movements) > 0:
+		err = i.rMovement.BulkCreate(movements)
+		if err != nil {
+			if e := err.Error(); strings.Contains(e, "23505") {
+				err = errors.ErrDuplicatedID
+			}
+			return err
+		}
+		err = i.sLedger.PostMovements(i.tx, movements)
+		if err != nil {
+			return err
+		}
+		summary.ImportedRows += len(movements)
+		if i.firstDates == nil {
+			i.firstDates = make(map[string]time.Time)
+		}
+		for _, movement := range movements {
+			if first, ok := i.firstDates[movement.Currency]; !ok || movement.Date.Before(first) {
+				i.firstDates[movement.Currency] = movement.Date
+			}
+		}
+	}
+	i.batch = make([]entity.Movement, 0, batchSize)
+	i.lineNumbers = make([]int, 0, batchSize)
+	return nil
+}
+
+/*
+sortBatch sorts the movements of the batch and their line numbers by date and line
+*/
+func (i *movementImport) sortBatch() {
+	order := make([]int, len(i.batch))
+	for index := range order {
+		order[index] = index
+	}
+	sort.Slice(order, func(a, b int) bool {
+		first, second := i.batch[order[a]], i.batch[order[b]]
+		if !first.Date.Equal(second.Date) {
+			return first.Date.Before(second.Date)
+		}
+		return i.lineNumbers[order[a]] < i.lineNumbers[order[b]]
+	})
+	batch := make([]entity.Movement, len(i.batch), batchSize)
+	lineNumbers := make([]int, len(i.lineNumbers), batchSize)
+	for index, position := range order {
+		batch[index] = i.batch[position]
+		lineNumbers[index] = i.lineNumbers[position]
+	}
+	i.batch, i.lineNumbers = batch, lineNumbers
+}
+
+/*
+shiftPreview adds the movement of a later batch to the available of the previewed movements of its currency
+with a later date, the ones of the same date are before it as their lines are
+*/
+func (i *movementImport) shiftPreview(movement *entity.Movement) {
+	for index := range i.list.Movements {
+		previewed := &i.list.Movements[index]
+		if previewed.Currency == movement.Currency && previewed.Date.After(movement.Date) {
+			previewed.Available += movement.Quantity * money.Amount(movement.Type)
+		}
+	}
+}
+
+/*
+balance returns the balance of the currency in the summary. The first time a currency is found its balance starts
+from the available of the last movement of the customer in that currency
+*/
+func (i *movementImport) balance(currency string) (*entity.CurrencyBalance, error) {
+	summary := i.list.Summary
+	if balance := summary.Balances.Find(currency); balance != nil {
+		return balance, nil
+	}
+	var lastAvailable money.Amount
+	lastMovement, err := i.rMovement.GetLastMovementByCustomerID(i.list.Customer.CustomerID, currency)
+	if !goerrors.Is(err, errors.ErrNotFound) {
+		if err != nil {
+			return nil, err
+		}
+		lastAvailable = lastMovement.Available
+	}
+	summary.Balances = append(summary.Balances, entity.CurrencyBalance{
+		Currency:         currency,
+		InitialAvailable: lastAvailable,
+		FinalAvailable:   lastAvailable,
+	})
+	return &summary.Balances[len(summary.Balances)-1], nil
+}
+
+/*
+rejectExistingMovements looks for the external references of the batch that the customer already has for the source
+of the file, it adds those lines to the Rejected report of the list and returns the rest of the movements
+*/
+func (i *movementImport) rejectExistingMovements() ([]entity.Movement, error) {
+	externalRefs := make([]string, len(i.batch))
+	for index, movement := range i.batch {
+		externalRefs[index] = movement.ExternalRef
+	}
+	existingRefs, err := i.rMovement.FindExistingExternalRefs(i.list.Customer.CustomerID, i.options.Source, externalRefs)
 	if err != nil {
 		return nil, err
 	}
-	currentYear := strconv.Itoa(time.Now().Year())
-	date, err := time.Parse("01/02/2006", line[1]+"/"+currentYear) // As in the date doesn't have a year, I add the current
+	if len(existingRefs) == 0 {
+		return i.batch, nil
+	}
+	existing := make(map[string]bool, len(existingRefs))
+	for _, externalRef := range existingRefs {
+		existing[externalRef] = true
+	}
+	newMovements := make([]entity.Movement, 0, len(i.batch))
+	for index, movement := range i.batch {
+		if existing[movement.ExternalRef] {
+			i.reject(newLineError(i.columns.ID, "IMPORT_LINE.ALREADY_IMPORTED", nil), i.lineNumbers[index])
+			continue
+		}
+		newMovements = append(newMovements, movement)
+	}
+	return newMovements, nil
+}
+
+/*
+finishBatch saves the totals of the import in its batch
+*/
+func (s *movementService) finishBatch(rImportBatch interfaces.IImportBatchRepository, importBatch *entity.ImportBatch, summary *dto.ImportSummary) error {
+	finishedAt := timeNow()
+	importBatch.TotalRows = summary.TotalRows
+	importBatch.ImportedRows = summary.ImportedRows
+	importBatch.RejectedRows = summary.RejectedRows
+	importBatch.Balances = summary.Balances
+	importBatch.FinishedAt = &finishedAt
+	return rImportBatch.Update(importBatch)
+}
+
+/*
+summaryFromBatch returns the summary of an import that was already done
+*/
+func summaryFromBatch(importBatch *entity.ImportBatch, homeCurrency string) *dto.ImportSummary {
+	summary := &dto.ImportSummary{
+		TotalRows:    importBatch.TotalRows,
+		ImportedRows: importBatch.ImportedRows,
+		RejectedRows: importBatch.RejectedRows,
+		Balances:     importBatch.Balances,
+	}
+	for _, balance := range importBatch.Balances {
+		summary.IncomeRows += balance.IncomeRows
+		summary.OutcomeRows += balance.OutcomeRows
+	}
+	setHomeTotals(summary, homeCurrency)
+	return summary
+}
+
+/*
+setHomeTotals adds up the home amounts of the balances of all the currencies in the summary
+*/
+func setHomeTotals(summary *dto.ImportSummary, homeCurrency string) {
+	summary.HomeCurrency = homeCurrency
+	summary.HomeIncome, summary.HomeOutcome, summary.HomeAvailable = 0, 0, 0
+	for _, balance := range summary.Balances {
+		summary.HomeIncome += balance.HomeIncome
+		summary.HomeOutcome += balance.HomeOutcome
+		summary.HomeAvailable += balance.HomeAvailable
+	}
+}
+
+/*
+fingerprint returns the hex SHA-256 of the file content and leaves the file at the start to be read again
+*/
+func fingerprint(file io.ReadSeeker) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+/*
+findImportBatch returns the import batch of the customer, not reverted, with the fingerprint of the file
+*/
+func (s *movementService) findImportBatch(customerID int, file io.ReadSeeker) (*entity.ImportBatch, error) {
+	fileHash, err := fingerprint(file)
+	if err != nil {
+		return nil, err
+	}
+	return s.rImportBatch.FindByFileHash(customerID, fileHash)
+}
+
+/*
+nopSeekCloser adds a Close that does nothing to an uploaded file, the caller closes it
+*/
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+// Close does nothing
+func (nopSeekCloser) Close() error {
+	return nil
+}
+
+/*
+getFileName returns the name of the customer file in the file source, with the extension of the format
+*/
+func getFileName(customerID int, format string) string {
+	return "customer_" + strconv.Itoa(customerID) + statement.Extension(format)
+}
+
+/*
+parseLine takes a record of the file and returns a movement,
+or the column and reason why the line is invalid. The amount can't have more decimals than its currency
+*/
+func (s *movementService) parseLine(record *statement.Record, columns statement.Columns, options dto.ImportOptions) (*entity.Movement, *entity.ImportLineError) {
+	if record.Err != nil {
+		return nil, newLineError(record.Err.Column, record.Err.MessageID, record.Err.TemplateData)
+	}
+	var movement entity.Movement
+	externalID, err := strconv.Atoi(record.ID)
+	if err != nil {
+		return nil, newLineError(columns.ID, "IMPORT_LINE.INVALID_ID", nil)
+	}
+	date, err := parseDate(record.Date, options.DateFormat, options.StatementDate)
 	if err != nil {
 		// in real case log the error to sentry or something...
-		return nil, errors.ErrInvalidFileLine
+		return nil, newLineError(columns.Date, "IMPORT_LINE.INVALID_DATE", map[string]interface{}{
+			"Format": options.DateFormat,
+		})
+	}
+	qty, err := money.Parse(record.Amount)
+	if goerrors.Is(err, money.ErrPrecision) {
+		return nil, newLineError(columns.Amount, "IMPORT_LINE.AMOUNT_PRECISION", nil)
 	}
-	qty, err := strconv.ParseFloat(line[2], 64)
 	if err != nil {
-		return nil, err
+		return nil, newLineError(columns.Amount, "IMPORT_LINE.INVALID_AMOUNT", nil)
+	}
+	if qty == 0 {
+		return nil, newLineError(columns.Amount, "IMPORT_LINE.ZERO_AMOUNT", nil)
+	}
+	currency := record.Currency
+	if currency == "" {
+		currency = options.SourceCurrency
+	}
+	decimals, ok := constant.CurrencyDecimals[currency]
+	if !ok {
+		return nil, newLineError(columns.Currency, "IMPORT_LINE.INVALID_CURRENCY", map[string]interface{}{
+			"Currency": currency,
+		})
+	}
+	if !qty.HasDecimals(decimals) {
+		return nil, newLineError(columns.Amount, "IMPORT_LINE.CURRENCY_PRECISION", map[string]interface{}{
+			"Decimals": decimals,
+			"Currency": currency,
+		})
+	}
+	texts := []struct {
+		column string
+		value  string
+		max    int
+	}{
+		{columns.Description, record.Description, maxDescriptionLength},
+		{columns.Merchant, record.Merchant, maxMerchantLength},
+		{columns.Category, record.Category, maxCategoryLength},
+		{columns.ID, record.Reference, maxReferenceLength},
+	}
+	for _, text := range texts {
+		if utf8.RuneCountInString(text.value) > text.max {
+			return nil, newLineError(text.column, "IMPORT_LINE.TEXT_TOO_LONG", map[string]interface{}{
+				"Max": text.max,
+			})
+		}
+	}
+	movement.Currency = currency
+	movement.Quantity = qty.Abs()
+	movement.Type = qty.Sign()
+	movement.ExternalID = externalID
+	movement.ExternalRef = record.Reference
+	if movement.ExternalRef == "" {
+		movement.ExternalRef = strconv.Itoa(externalID)
 	}
-	movement.Quantity = math.Abs(qty)
-	movement.Type = int(qty / math.Abs(qty))
-	movement.MovementID = movementID
 	movement.Date = date
+	movement.Description = record.Description
+	movement.Merchant = record.Merchant
+	movement.Category = record.Category
 	return &movement, nil
 }
+
+/*
+parseDate parses the date with the layout of the given format, full ISO dates are always accepted.
+When the format doesn't have the year, it takes the one that leaves the date in the year
+before the statement date, so a December movement processed in January is from the last year
+*/
+func parseDate(value, format string, statementDate time.Time) (time.Time, error) {
+	if date, err := time.Parse(constant.DateLayouts[constant.DateFormatISO], value); err == nil {
+		return date, nil
+	}
+	layout, ok := constant.DateLayouts[format]
+	if !ok {
+		return time.Time{}, errors.ErrFieldValidation("date_format", "oneof", format)
+	}
+	date, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if strings.Contains(layout, "2006") {
+		return date, nil
+	}
+	year := statementDate.Year()
+	statementDay := time.Date(year, statementDate.Month(), statementDate.Day(), 0, 0, 0, 0, time.UTC)
+	if time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).After(statementDay) {
+		year--
+	}
+	inferred := time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if inferred.Day() != date.Day() {
+		// february 29 of a year that is not a leap year
+		return time.Time{}, errors.ErrFieldValidation("date", "leap_year", strconv.Itoa(year))
+	}
+	return inferred, nil
+}
+
+/*
+newLineError returns the report of an invalid line with the translated reason, the line number is set by the caller
+*/
+func newLineError(column, messageID string, templateData map[string]interface{}) *entity.ImportLineError {
+	return &entity.ImportLineError{
+		Column: column,
+		Reason: i18n.T(i18n.Message{MessageID: messageID, TemplateData: templateData}),
+	}
+}
@@ -1,11 +1,17 @@
 package movement
 
 import (
+	"crypto/sha256"
 	goerrors "errors"
-	"os"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
 	"stori-service/src/environments/common/resources/entity"
-	"stori-service/src/libs/env"
+	"stori-service/src/libs/categorizer"
+	"stori-service/src/libs/dto"
 	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/libs/statement"
 	"stori-service/src/utils/constant"
 	customMocks "stori-service/src/utils/test/mock"
 	"strconv"
@@ -18,139 +24,1674 @@ import (
 )
 
 func TestMovementService(t *testing.T) {
+	fixedNow := time.Date(2022, time.June, 30, 10, 0, 0, 0, time.UTC)
+	timeNowBackup := timeNow
+	timeNow = func() time.Time { return fixedNow }
+	t.Cleanup(func() {
+		timeNow = timeNowBackup
+	})
+	defaultOptions := dto.ImportOptions{
+		Mode:           constant.ImportModeStrict,
+		SourceCurrency: constant.DefaultCurrency,
+		DateFormat:     constant.DateFormatMonthDay,
+		StatementDate:  fixedNow,
+	}
 	t.Run("parseLine", func(t *testing.T) {
 		expectedID := 1
-		currentYear := strconv.Itoa(time.Now().Year())
-		expectedDate, _ := time.Parse("1/02/2006", "5/25/"+currentYear)
-		expectedQuantity := 1.6
+		expectedDate := time.Date(2022, time.May, 25, 0, 0, 0, 0, time.UTC)
+		expectedQuantity := money.Amount(160)
 		expectedType := constant.OutcomeType
+		columns := statement.Columns{ID: "id", Date: "date", Amount: "transaction", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"}
 		t.Run("Should success on", func(t *testing.T) {
 			t.Run("Parsing a valid line", func(t *testing.T) {
-				sMovement := &movementService{nil, nil}
-				line := []string{
-					"1",
-					"5/25",
-					"-1.6",
+				sMovement := &movementService{}
+				record := &statement.Record{
+					Line:   2,
+					ID:     "1",
+					Date:   "05/25",
+					Amount: "-1.6",
 				}
-				movement, err := sMovement.parseLine(line)
+				movement, err := sMovement.parseLine(record, columns, defaultOptions)
 
 				assert.Nil(t, err)
 				assert.NotNil(t, movement)
-				assert.Equal(t, expectedID, movement.MovementID)
+				assert.Equal(t, expectedID, movement.ExternalID)
+				assert.Equal(t, "1", movement.ExternalRef)
 				assert.Equal(t, expectedDate, movement.Date)
 				assert.Equal(t, expectedQuantity, movement.Quantity)
 				assert.Equal(t, expectedType, movement.Type)
+				assert.Equal(t, constant.DefaultCurrency, movement.Currency)
+			})
+			t.Run("Parsing a line with the currency of the file", func(t *testing.T) {
+				sMovement := &movementService{}
+				record := &statement.Record{
+					Line:     2,
+					ID:       "1",
+					Date:     "05/25",
+					Amount:   "-1.6",
+					Currency: "USD",
+				}
+				movement, err := sMovement.parseLine(record, columns, defaultOptions)
+
+				assert.Nil(t, err)
+				assert.Equal(t, expectedQuantity, movement.Quantity)
+				assert.Equal(t, "USD", movement.Currency)
+			})
+			t.Run("Parsing a line with description, merchant and category", func(t *testing.T) {
+				sMovement := &movementService{}
+				record := &statement.Record{
+					Line:        2,
+					ID:          "1",
+					Date:        "05/25",
+					Amount:      "-1.6",
+					Description: "Café con leche",
+					Merchant:    "Corner Cafe",
+					Category:    "Food",
+				}
+				movement, err := sMovement.parseLine(record, columns, defaultOptions)
+
+				assert.Nil(t, err)
+				assert.Equal(t, "Café con leche", movement.Description)
+				assert.Equal(t, "Corner Cafe", movement.Merchant)
+				assert.Equal(t, "Food", movement.Category)
+			})
+			t.Run("Parsing a line with a text reference", func(t *testing.T) {
+				sMovement := &movementService{}
+				record := &statement.Record{
+					Line:      2,
+					ID:        "81985529216486895",
+					Reference: "AB-1",
+					Date:      "05/25",
+					Amount:    "-1.6",
+				}
+				movement, err := sMovement.parseLine(record, columns, defaultOptions)
+
+				assert.Nil(t, err)
+				assert.Equal(t, 81985529216486895, movement.ExternalID)
+				assert.Equal(t, "AB-1", movement.ExternalRef)
+			})
+			t.Run("Parsing a line in a currency without cents", func(t *testing.T) {
+				sMovement := &movementService{}
+				record := &statement.Record{
+					Line:     2,
+					ID:       "1",
+					Date:     "05/25",
+					Amount:   "-1600.00",
+					Currency: "JPY",
+				}
+				movement, err := sMovement.parseLine(record, columns, defaultOptions)
+
+				assert.Nil(t, err)
+				assert.Equal(t, money.Amount(160000), movement.Quantity)
+				assert.Equal(t, "JPY", movement.Currency)
 			})
 		})
 		t.Run("Should fail on", func(t *testing.T) {
 			testCases := []struct {
-				name string
-				line []string
+				name           string
+				record         *statement.Record
+				expectedColumn string
+				expectedReason string
 			}{
 				{
-					name: "Parsing a line with less than 3 elements",
-					line: []string{
-						"1",
-						"5/25",
+					name: "Parsing a line that couldn't be read",
+					record: &statement.Record{
+						Err: &statement.RecordError{
+							MessageID:    "IMPORT_LINE.WRONG_COLUMNS",
+							TemplateData: map[string]interface{}{"Count": 3},
+						},
 					},
+					expectedReason: "The line must have 3 columns",
 				},
 				{
-					name: "Parsing a line with more than 3 elements",
-					line: []string{
-						"1",
-						"5/25",
-						"-1.6",
-						"1.6",
+					name: "Parsing a line with an invalid date",
+					record: &statement.Record{
+						ID:     "1",
+						Date:   "255/25/2012",
+						Amount: "-1.6",
 					},
+					expectedColumn: "date",
+					expectedReason: "The date is not valid, expected MM/DD or YYYY-MM-DD",
 				},
 				{
-					name: "Parsing a line with an invalid date",
-					line: []string{
-						"1",
-						"255/25/2012",
-						"-1.6",
+					name: "Parsing a line with an invalid quantity",
+					record: &statement.Record{
+						ID:     "1",
+						Date:   "05/25",
+						Amount: "a",
 					},
+					expectedColumn: "transaction",
+					expectedReason: "The transaction is not a number",
 				},
 				{
-					name: "Parsing a line with an invalid quantity",
-					line: []string{
-						"1",
-						"5/25",
-						"a",
+					name: "Parsing a line with fractions of cent",
+					record: &statement.Record{
+						ID:     "1",
+						Date:   "05/25",
+						Amount: "-1.605",
+					},
+					expectedColumn: "transaction",
+					expectedReason: "The transaction can't have more than 2 decimals",
+				},
+				{
+					name: "Parsing a line with an unknown currency",
+					record: &statement.Record{
+						ID:       "1",
+						Date:     "05/25",
+						Amount:   "-1.6",
+						Currency: "XYZ",
+					},
+					expectedColumn: "currency",
+					expectedReason: "The currency XYZ is not supported",
+				},
+				{
+					name: "Parsing a line with cents in a currency without them",
+					record: &statement.Record{
+						ID:       "1",
+						Date:     "05/25",
+						Amount:   "-1.6",
+						Currency: "JPY",
 					},
+					expectedColumn: "transaction",
+					expectedReason: "The transaction can't have more than 0 decimals in JPY",
+				},
+				{
+					name: "Parsing a line with a zero quantity",
+					record: &statement.Record{
+						ID:     "1",
+						Date:   "05/25",
+						Amount: "0",
+					},
+					expectedColumn: "transaction",
+					expectedReason: "The transaction can't be zero",
 				},
 				{
 					name: "Parsing a line with an invalid id",
-					line: []string{
-						"a",
-						"5/25",
-						"-1.6",
+					record: &statement.Record{
+						ID:     "a",
+						Date:   "05/25",
+						Amount: "-1.6",
+					},
+					expectedColumn: "id",
+					expectedReason: "The ID is not an integer",
+				},
+				{
+					name: "Parsing a line with a long description",
+					record: &statement.Record{
+						ID:          "1",
+						Date:        "05/25",
+						Amount:      "-1.6",
+						Description: strings.Repeat("é", 256),
+					},
+					expectedColumn: "description",
+					expectedReason: "The text can't have more than 255 characters",
+				},
+				{
+					name: "Parsing a line with a long merchant",
+					record: &statement.Record{
+						ID:       "1",
+						Date:     "05/25",
+						Amount:   "-1.6",
+						Merchant: strings.Repeat("M", 101),
+					},
+					expectedColumn: "merchant",
+					expectedReason: "The text can't have more than 100 characters",
+				},
+				{
+					name: "Parsing a line with a long category",
+					record: &statement.Record{
+						ID:       "1",
+						Date:     "05/25",
+						Amount:   "-1.6",
+						Category: strings.Repeat("C", 51),
 					},
+					expectedColumn: "category",
+					expectedReason: "The text can't have more than 50 characters",
+				},
+				{
+					name: "Parsing a line with a long reference",
+					record: &statement.Record{
+						ID:        "1",
+						Reference: strings.Repeat("R", 256),
+						Date:      "05/25",
+						Amount:    "-1.6",
+					},
+					expectedColumn: "id",
+					expectedReason: "The text can't have more than 255 characters",
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					sMovement := &movementService{}
+
+					movement, lineError := sMovement.parseLine(tC.record, columns, defaultOptions)
+
+					assert.NotNil(t, lineError)
+					assert.Equal(t, tC.expectedColumn, lineError.Column)
+					assert.Equal(t, tC.expectedReason, lineError.Reason)
+					assert.Nil(t, movement)
+				})
+			}
+		})
+	})
+	t.Run("parseDate", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				name          string
+				value         string
+				format        string
+				statementDate time.Time
+				expected      time.Time
+			}{
+				{
+					name:          "Month and day before the statement date",
+					value:         "05/25",
+					format:        constant.DateFormatMonthDay,
+					statementDate: fixedNow,
+					expected:      time.Date(2022, time.May, 25, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					name:          "Month and day on the statement date",
+					value:         "06/30",
+					format:        constant.DateFormatMonthDay,
+					statementDate: fixedNow,
+					expected:      time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					name:          "December movement in a January statement",
+					value:         "12/28",
+					format:        constant.DateFormatMonthDay,
+					statementDate: time.Date(2023, time.January, 5, 0, 0, 0, 0, time.UTC),
+					expected:      time.Date(2022, time.December, 28, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					name:          "Day and month",
+					value:         "25/05",
+					format:        constant.DateFormatDayMonth,
+					statementDate: fixedNow,
+					expected:      time.Date(2022, time.May, 25, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					name:          "February 29 of a leap year",
+					value:         "02/29",
+					format:        constant.DateFormatMonthDay,
+					statementDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+					expected:      time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					name:          "Day, month and year",
+					value:         "25/05/2019",
+					format:        constant.DateFormatDayMonthYear,
+					statementDate: fixedNow,
+					expected:      time.Date(2019, time.May, 25, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					name:          "Month, day and year",
+					value:         "05/25/2019",
+					format:        constant.DateFormatMonthDayYear,
+					statementDate: fixedNow,
+					expected:      time.Date(2019, time.May, 25, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					name:          "ISO date with another format",
+					value:         "2021-12-28",
+					format:        constant.DateFormatMonthDay,
+					statementDate: fixedNow,
+					expected:      time.Date(2021, time.December, 28, 0, 0, 0, 0, time.UTC),
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					date, err := parseDate(tC.value, tC.format, tC.statementDate)
+
+					assert.NoError(t, err)
+					assert.Equal(t, tC.expected, date)
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			testCases := []struct {
+				name   string
+				value  string
+				format string
+			}{
+				{
+					name:   "Day and month with month and day format",
+					value:  "25/05",
+					format: constant.DateFormatMonthDay,
+				},
+				{
+					name:   "Month without the leading zero",
+					value:  "5/25",
+					format: constant.DateFormatMonthDay,
+				},
+				{
+					name:   "Missing year",
+					value:  "25/05",
+					format: constant.DateFormatDayMonthYear,
+				},
+				{
+					name:   "February 29 of a year that is not a leap year",
+					value:  "02/29",
+					format: constant.DateFormatMonthDay,
+				},
+				{
+					name:   "Unknown format",
+					value:  "05/25",
+					format: "YY/MM/DD",
 				},
 			}
 			for _, tC := range testCases {
 				t.Run(tC.name, func(t *testing.T) {
-					sMovement := &movementService{nil, nil}
+					date, err := parseDate(tC.value, tC.format, fixedNow)
+
+					assert.Error(t, err)
+					assert.True(t, date.IsZero())
+				})
+			}
+		})
+	})
+	t.Run("getFileName", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Getting the file name", func(t *testing.T) {
+				name := getFileName(1, "")
+				assert.Equal(t, "customer_1.csv", name)
+			})
+			t.Run("Getting the file name of a format", func(t *testing.T) {
+				name := getFileName(1, statement.OFXFormat)
+				assert.Equal(t, "customer_1.ofx", name)
+			})
+		})
+	})
+	t.Run("fingerprint", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Hashing the file and going back to the start", func(t *testing.T) {
+				file := strings.NewReader("id,date,transaction")
+
+				hash, err := fingerprint(file)
+
+				assert.NoError(t, err)
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("id,date,transaction"))), hash)
+				content, _ := ioutil.ReadAll(file)
+				assert.Equal(t, "id,date,transaction", string(content))
+			})
+		})
+	})
+	t.Run("ProcessFile", func(t *testing.T) {
+		validLine1 := "1,05/25,+3.5"
+		validLine2 := "2,03/20,-1.6"
+		validInput := strings.Join([]string{
+			"id,date,transaction",
+			validLine1,
+			validLine2,
+		}, "\n")
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Processing a valid file", func(t *testing.T) {
+				importBatchID := 7
+				expectedMovements := []entity.Movement{
+					{
+						ExternalID:    1,
+						ExternalRef:   "1",
+						Source:        "default",
+						Currency:      "MXN",
+						Date:          time.Date(2022, 5, 25, 0, 0, 0, 0, time.UTC),
+						Quantity:      350,
+						HomeQuantity:  350,
+						ExchangeRate:  money.OneRate,
+						Type:          constant.IncomeType,
+						CustomerID:    1,
+						Available:     350,
+						ImportBatchID: &importBatchID,
+					},
+					{
+						ExternalID:    2,
+						ExternalRef:   "2",
+						Source:        "default",
+						Currency:      "MXN",
+						Date:          time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC),
+						Quantity:      160,
+						HomeQuantity:  160,
+						ExchangeRate:  money.OneRate,
+						Type:          constant.OutcomeType,
+						CustomerID:    1,
+						Available:     190,
+						ImportBatchID: &importBatchID,
+					},
+				}
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockFileSource := new(customMocks.FileSource)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				mockLedgerService := new(customMocks.ClientLedgerService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), mockBalanceService, mockLedgerService, newCategoryRuleMock(), mockFileSource)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// fake file
+				mockFileSource.On("Open", "customer_1.csv").Return(customMocks.NewFile(validInput), nil)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", expectedMovements).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+				// the lines are not in order of date, the balance is recalculated from the oldest one
+				mockBalanceService.On("Recalculate", nil, 1, "MXN", time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)).Return(nil)
+				mockBalanceService.On("FindOverdrafts", nil, 7).Return([]entity.Movement{}, nil)
+				mockLedgerService.On("PostMovements", nil, expectedMovements).Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessFile(1, dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockBalanceService.AssertExpectations(t)
+				mockLedgerService.AssertExpectations(t)
+				mockCustomerRepo.AssertNumberOfCalls(t, "Clone", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Clone", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Begin", 1)
+				mockCustomerRepo.AssertNumberOfCalls(t, "Begin", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 1)
+				mockCustomerRepo.AssertNumberOfCalls(t, "FindAndLockByCustomerID", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.Nil(t, err)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Create", 1)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 1)
+				assert.Equal(t, 7, movementList.ImportBatch.ImportBatchID)
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte(validInput))), movementList.ImportBatch.FileHash)
+				assert.Equal(t, 2, movementList.ImportBatch.ImportedRows)
+				assert.Equal(t, movementList.Summary.Balances, movementList.ImportBatch.Balances)
+				assert.NotNil(t, movementList.ImportBatch.FinishedAt)
+				assert.False(t, movementList.AlreadyImported)
+				assert.Empty(t, movementList.Movements) // only the summary is returned
+				assert.Equal(t, &dto.ImportSummary{
+					TotalRows:        2,
+					ImportedRows:     2,
+					IncomeRows:       1,
+					OutcomeRows:      1,
+					MonthlyRows:      map[string]int{"2022-05": 1, "2022-03": 1},
+					CategoryOutcomes: map[string]money.Amount{},
+					Balances: entity.CurrencyBalances{
+						{Currency: "MXN", IncomeRows: 1, OutcomeRows: 1, TotalIncome: 350, TotalOutcome: 160, InitialAvailable: 0, FinalAvailable: 190, HomeIncome: 350, HomeOutcome: 160, HomeAvailable: 190},
+					},
+					HomeCurrency:  "MXN",
+					HomeIncome:    350,
+					HomeOutcome:   160,
+					HomeAvailable: 190,
+				}, movementList.Summary)
+			})
+			t.Run("Processing an uploaded file", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 1000}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(created))
+				assert.Equal(t, money.Amount(1350), created[0].Available)
+				assert.Equal(t, money.Amount(1190), created[1].Available)
+				assert.Equal(t, money.Amount(1190), movementList.Summary.Balances[0].FinalAvailable)
+			})
+			t.Run("Flagging an overdraft allowed to the customer", func(t *testing.T) {
+				overdrawn := entity.Movement{MovementID: 12, ExternalID: 2, Source: "default", Currency: "MXN", Available: -30, Date: time.Date(2022, time.March, 20, 0, 0, 0, 0, time.UTC)}
+				customer := &entity.Customer{CustomerID: 1, Name: "User 1", Email: "test1@hotmail.com", OverdraftPolicy: constant.OverdraftAllowFlag}
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), mockBalanceService, newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+				mockBalanceService.On("Recalculate", nil, 1, "MXN", mock.AnythingOfType("time.Time")).Return(nil)
+				mockBalanceService.On("FindOverdrafts", nil, 7).Return([]entity.Movement{overdrawn}, nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockBalanceService.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 1)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, &entity.Overdraft{
+					MovementID: 12,
+					ExternalID: 2,
+					Source:     "default",
+					Date:       overdrawn.Date,
+					Available:  -30,
+				}, movementList.Summary.Balances[0].Overdraft)
+				assert.Equal(t, 2, movementList.Summary.ImportedRows)
+			})
+			t.Run("Processing a file with description, merchant and category", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction,description,merchant,category",
+					"1,05/25,+100,Salary,ACME,Income",
+					"2,05/25,-1.6,Coffee,Corner Cafe,Food",
+					"3,05/26,-20.5,Weekly shopping,Super Market,Food",
+					"4,05/26,-3,Bus,,Transport",
+					"5,05/27,-1,Fee,,",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 5, len(created))
+				assert.Equal(t, "Weekly shopping", created[2].Description)
+				assert.Equal(t, "Super Market", created[2].Merchant)
+				assert.Equal(t, "Food", created[2].Category)
+				assert.Equal(t, map[string]money.Amount{"Food": 2210, "Transport": 300}, movementList.Summary.CategoryOutcomes)
+			})
+			t.Run("Processing a file with category rules", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction,description,merchant,category",
+					"1,05/25,+100,Salary,ACME,",
+					"2,05/25,-1.6,Coffee,Corner Cafe,Food",
+					"3,05/26,-20.5,WALMART SUPERCENTER,,",
+					"4,05/27,-1,Fee,,",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				customerID := 1
+				mockCategoryRuleService := newCategoryRuleMock(
+					entity.CategoryRule{CategoryRuleID: 7, CustomerID: &customerID, Category: "Groceries", DescriptionPattern: "walmart", Type: constant.OutcomeType},
+					entity.CategoryRule{CategoryRuleID: 8, Category: "Snacks", DescriptionPattern: "coffee"},
+					entity.CategoryRule{CategoryRuleID: 9, Category: "Payroll", Type: constant.IncomeType},
+				)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), mockCategoryRuleService, nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCategoryRuleService.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 4, len(created))
+				assert.Equal(t, "Payroll", created[0].Category)
+				assert.Equal(t, 9, *created[0].CategoryRuleID)
+				assert.Equal(t, "Food", created[1].Category)
+				assert.Nil(t, created[1].CategoryRuleID)
+				assert.Equal(t, "Groceries", created[2].Category)
+				assert.Equal(t, 7, *created[2].CategoryRuleID)
+				assert.Empty(t, created[3].Category)
+				assert.Nil(t, created[3].CategoryRuleID)
+				assert.Equal(t, map[string]money.Amount{"Food": 160, "Groceries": 2050}, movementList.Summary.CategoryOutcomes)
+			})
+			t.Run("Processing a file with several currencies", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction,currency",
+					"1,05/25,+3.5,USD",
+					"2,05/25,-1.6,",
+					"3,05/26,-1.5,USD",
+					"4,05/26,+10,MXN",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 1000}, nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "USD").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), defaultOptions)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 2)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Len(t, created, 4)
+				assert.Equal(t, []string{"USD", "MXN", "USD", "MXN"}, []string{created[0].Currency, created[1].Currency, created[2].Currency, created[3].Currency})
+				assert.Equal(t, []money.Amount{350, 840, 200, 1840}, []money.Amount{created[0].Available, created[1].Available, created[2].Available, created[3].Available})
+				// the original amount and currency are kept, converted to pesos at 20 per dollar
+				assert.Equal(t, []money.Amount{7000, 160, 3000, 1000}, []money.Amount{created[0].HomeQuantity, created[1].HomeQuantity, created[2].HomeQuantity, created[3].HomeQuantity})
+				assert.Equal(t, []money.Rate{2000000000, money.OneRate, 2000000000, money.OneRate}, []money.Rate{created[0].ExchangeRate, created[1].ExchangeRate, created[2].ExchangeRate, created[3].ExchangeRate})
+				assert.Equal(t, entity.CurrencyBalances{
+					{Currency: "MXN", IncomeRows: 1, OutcomeRows: 1, TotalIncome: 1000, TotalOutcome: 160, InitialAvailable: 1000, FinalAvailable: 1840, HomeIncome: 1000, HomeOutcome: 160, HomeAvailable: 1840},
+					{Currency: "USD", IncomeRows: 1, OutcomeRows: 1, TotalIncome: 350, TotalOutcome: 150, InitialAvailable: 0, FinalAvailable: 200, HomeIncome: 7000, HomeOutcome: 3000, HomeAvailable: 4000},
+				}, movementList.Summary.Balances)
+				assert.Equal(t, movementList.Summary.Balances, movementList.ImportBatch.Balances)
+				assert.Equal(t, "MXN", movementList.Summary.HomeCurrency)
+				assert.Equal(t, money.Amount(8000), movementList.Summary.HomeIncome)
+				assert.Equal(t, money.Amount(3160), movementList.Summary.HomeOutcome)
+				assert.Equal(t, money.Amount(5840), movementList.Summary.HomeAvailable)
+			})
+			t.Run("Processing a file with lines without exchange rate in partial mode", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction,currency",
+					"1,05/26,+10,EUR",
+					"2,05/25,+5,EUR",
+					"3,05/25,-3,EUR",
+					"4,05/20,+1,EUR",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockExchangeRateService := new(customMocks.ClientExchangeRateService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, mockExchangeRateService, newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+				may25 := time.Date(2022, 5, 25, 0, 0, 0, 0, time.UTC)
+				may26 := time.Date(2022, 5, 26, 0, 0, 0, 0, time.UTC)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&entity.Customer{CustomerID: 1, HomeCurrency: "USD"}, nil)
+				mockExchangeRateService.On("Rate", "EUR", "USD", may26).Return(money.Rate(110000000), nil)
+				mockExchangeRateService.On("Rate", "EUR", "USD", may25).Return(money.Rate(105000000), nil)
+				mockExchangeRateService.On("Rate", "EUR", "USD", mock.AnythingOfType("time.Time")).Return(money.Rate(0), errors.ErrMissingExchangeRate)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "EUR").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModePartial})
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+				mockExchangeRateService.AssertNumberOfCalls(t, "Rate", 3) // the rate of each date is looked up once
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 3, len(created))
+				assert.Equal(t, []money.Amount{1100, 525, 315}, []money.Amount{created[0].HomeQuantity, created[1].HomeQuantity, created[2].HomeQuantity})
+				assert.Equal(t, entity.ImportLineErrors{
+					{Line: 5, Column: "currency", Reason: "There isn't an exchange rate from EUR to USD on 2022-05-20"},
+				}, movementList.Rejected)
+				// the available is converted with the rate of the last date, not the one of the last line
+				assert.Equal(t, entity.CurrencyBalances{
+					{Currency: "EUR", IncomeRows: 2, OutcomeRows: 1, TotalIncome: 1500, TotalOutcome: 300, FinalAvailable: 1200, HomeIncome: 1625, HomeOutcome: 315, HomeAvailable: 1320},
+				}, movementList.Summary.Balances)
+				assert.Equal(t, "USD", movementList.Summary.HomeCurrency)
+				assert.Equal(t, money.Amount(1320), movementList.Summary.HomeAvailable)
+			})
+			t.Run("Processing a file with amounts that drift with floats", func(t *testing.T) {
+				// 0.1 can't be represented in binary, a float balance drifts after a few lines
+				lines := []string{"id,date,transaction"}
+				for id := 1; id <= 30; id++ {
+					lines = append(lines, strconv.Itoa(id)+",05/25,+0.1")
+				}
+				lines = append(lines, "31,05/25,-2.9", "32,05/25,+0.07", "33,05/25,-0.17")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(strings.Join(lines, "\n")), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Len(t, created, 33)
+				assert.Equal(t, money.Amount(300), created[29].Available)
+				assert.Equal(t, money.Amount(10), created[30].Available)
+				assert.Equal(t, money.Amount(0), created[32].Available)
+				assert.Equal(t, money.Amount(307), movementList.Summary.Balances[0].TotalIncome)
+				assert.Equal(t, money.Amount(307), movementList.Summary.Balances[0].TotalOutcome)
+				assert.Equal(t, money.Amount(0), movementList.Summary.Balances[0].FinalAvailable)
+				assert.Equal(t, movementList.Summary.Balances, movementList.ImportBatch.Balances)
+			})
+			t.Run("Processing a file with invalid lines in partial mode", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,juan/20,-1.6",
+					"1,03/21,-1.0",
+					"3,03/20,-1.6",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModePartial})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(created))
+				assert.Equal(t, money.Amount(350), created[0].Available)
+				assert.Equal(t, money.Amount(190), created[1].Available)
+				assert.Equal(t, entity.ImportLineErrors{
+					{Line: 3, Column: "date", Reason: "The date is not valid, expected MM/DD or YYYY-MM-DD"},
+					{Line: 4, Column: "id", Reason: "The ID is repeated in the file"},
+				}, movementList.Rejected)
+			})
+			t.Run("Processing a file with already imported ids in partial mode", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,juan/20,-1.6",
+					"3,03/20,-1.6",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 1000}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"1", "3"}).Return([]string{"1"}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModePartial})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 1)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(created))
+				assert.Equal(t, 3, created[0].ExternalID)
+				assert.Equal(t, money.Amount(840), created[0].Available)
+				assert.Equal(t, entity.ImportLineErrors{
+					{Line: 2, Column: "id", Reason: "The ID was already imported"},
+					{Line: 3, Column: "date", Reason: "The date is not valid, expected MM/DD or YYYY-MM-DD"},
+				}, movementList.Rejected)
+				assert.Equal(t, 3, movementList.Summary.TotalRows)
+				assert.Equal(t, 1, movementList.Summary.ImportedRows)
+				assert.Equal(t, 2, movementList.Summary.RejectedRows)
+			})
+			t.Run("Processing a file with another profile", func(t *testing.T) {
+				input := strings.Join([]string{
+					"fecha;monto;id",
+					"25/05/2022;1.003,50;1",
+					"20/03/2022;-1,6;2",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "latam", []string{"1", "2"}).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModeStrict, Profile: "latam"})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(created))
+				assert.Equal(t, "latam", created[0].Source) // the source is the profile by default
+				assert.Equal(t, time.Date(2022, time.May, 25, 0, 0, 0, 0, time.UTC), created[0].Date)
+				assert.Equal(t, money.Amount(100350), created[0].Quantity)
+				assert.Equal(t, money.Amount(100190), created[1].Available)
+				assert.Equal(t, 2, movementList.Summary.ImportedRows)
+			})
+			t.Run("Processing an OFX file", func(t *testing.T) {
+				input := strings.Join([]string{
+					"OFXHEADER:100",
+					"<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>",
+					"<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20220525<TRNAMT>3.5<FITID>1</STMTTRN>",
+					"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20220320120000[-3:ART]<TRNAMT>-1.6<FITID>2</STMTTRN>",
+					"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"1", "2"}).Return([]string{}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(created))
+				assert.Equal(t, time.Date(2022, time.March, 20, 0, 0, 0, 0, time.UTC), created[1].Date)
+				assert.Equal(t, money.Amount(190), created[1].Available)
+				assert.Equal(t, 2, movementList.Summary.ImportedRows)
+			})
+			t.Run("Processing references with the same ID", func(t *testing.T) {
+				// the ID of a text reference is its hash, a bank can use that number as the reference of another transaction
+				hash := fnv.New64a()
+				hash.Write([]byte("AB-1"))
+				collidingID := strconv.FormatUint(hash.Sum64()&0x7fffffffffffffff, 10)
+				input := strings.Join([]string{
+					"OFXHEADER:100",
+					"<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>",
+					"<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20220525<TRNAMT>3.5<FITID>AB-1</STMTTRN>",
+					"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20220526<TRNAMT>-1.6<FITID>" + collidingID + "</STMTTRN>",
+					"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20220527<TRNAMT>-0.5<FITID>AB-2</STMTTRN>",
+					"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"AB-1", collidingID, "AB-2"}).Return([]string{"AB-2"}, nil)
+				var created []entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					created = append(created, args.Get(0).([]entity.Movement)...)
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModePartial})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(created))
+				assert.Equal(t, created[0].ExternalID, created[1].ExternalID)
+				assert.Equal(t, "AB-1", created[0].ExternalRef)
+				assert.Equal(t, collidingID, created[1].ExternalRef)
+				assert.Equal(t, entity.ImportLineErrors{
+					{Line: 5, Column: "FITID", Reason: "The ID was already imported"},
+				}, movementList.Rejected)
+				assert.Equal(t, 2, movementList.Summary.ImportedRows)
+			})
+			t.Run("Processing a file that was already imported", func(t *testing.T) {
+				importBatch := &entity.ImportBatch{
+					ImportBatchID: 3,
+					CustomerID:    1,
+					FileHash:      fmt.Sprintf("%x", sha256.Sum256([]byte(validInput))),
+					TotalRows:     2,
+					ImportedRows:  2,
+					Balances: entity.CurrencyBalances{
+						{Currency: "MXN", IncomeRows: 1, OutcomeRows: 1, TotalIncome: 350, TotalOutcome: 160, FinalAvailable: 190, HomeIncome: 350, HomeOutcome: 160, HomeAvailable: 190},
+					},
+				}
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockImportBatchRepo.On("Clone").Return(mockImportBatchRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockImportBatchRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockImportBatchRepo.On("FindByFileHash", 1, importBatch.FileHash).Return(importBatch, nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockImportBatchRepo.AssertExpectations(t)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Create", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.True(t, movementList.AlreadyImported)
+				assert.Equal(t, importBatch, movementList.ImportBatch)
+				assert.Equal(t, &dto.ImportSummary{
+					TotalRows:     2,
+					ImportedRows:  2,
+					IncomeRows:    1,
+					OutcomeRows:   1,
+					Balances:      importBatch.Balances,
+					HomeCurrency:  "MXN",
+					HomeIncome:    350,
+					HomeOutcome:   160,
+					HomeAvailable: 190,
+				}, movementList.Summary)
+			})
+			t.Run("Processing a file in several batches", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,05/26,-1.5",
+					"3,05/27,+10",
+					"4,05/28,-2",
+					"5,05/29,+1",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+				defaultBatchSize := batchSize
+				batchSize = 2
+				defer func() { batchSize = defaultBatchSize }()
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 1000}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"1", "2"}).Return([]string{}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"3", "4"}).Return([]string{}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"5"}).Return([]string{}, nil)
+				var batches [][]entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					batches = append(batches, args.Get(0).([]entity.Movement))
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 3)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 3, len(batches))
+				assert.Equal(t, money.Amount(1200), batches[0][1].Available)
+				assert.Equal(t, money.Amount(2200), batches[1][0].Available) // the balance continues from the previous batch
+				assert.Equal(t, money.Amount(2100), batches[2][0].Available)
+				assert.Equal(t, 5, movementList.Summary.ImportedRows)
+				assert.Equal(t, money.Amount(2100), movementList.Summary.Balances[0].FinalAvailable)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 1) // only for the first batch
+			})
+			t.Run("Processing a file with an ID repeated in another batch", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,05/26,-1.5",
+					"1,05/27,+10",
+					"3,05/28,-2",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+				defaultBatchSize := batchSize
+				batchSize = 2
+				defer func() { batchSize = defaultBatchSize }()
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 1000}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"1", "2"}).Return([]string{}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"3"}).Return([]string{}, nil)
+				var batches [][]entity.Movement
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Run(func(args mock.Arguments) {
+					batches = append(batches, args.Get(0).([]entity.Movement))
+				}).Return(nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModePartial})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 2)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(batches))
+				assert.Equal(t, 3, batches[1][0].ExternalID)
+				assert.Equal(t, entity.ImportLineErrors{
+					{Line: 4, Column: "id", Reason: "The ID is repeated in the file"},
+				}, movementList.Rejected)
+				assert.Equal(t, 3, movementList.Summary.ImportedRows)
+			})
+			t.Run("Previewing a file", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,juan/20,-1.6",
+					"3,03/20,-1.6",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockFileSource := new(customMocks.FileSource)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), mockFileSource)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// fake file
+				mockFileSource.On("Open", "customer_1.csv").Return(customMocks.NewFile(input), nil)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 1000}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"3", "1"}).Return([]string{}, nil)
+
+				// action
+				movementList, err := sMovement.ProcessFile(1, dto.ImportOptions{Mode: constant.ImportModeStrict, Preview: true})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(movementList.Movements))
+				// the lines are not in order of date, the availables follow the dates as the imported ones
+				assert.Equal(t, 3, movementList.Movements[0].ExternalID)
+				assert.Equal(t, money.Amount(840), movementList.Movements[0].Available)
+				assert.Equal(t, 1, movementList.Movements[1].ExternalID)
+				assert.Equal(t, money.Amount(1190), movementList.Movements[1].Available)
+				assert.Equal(t, 1, len(movementList.Rejected))
+				assert.Equal(t, &dto.ImportSummary{
+					TotalRows:        3,
+					ImportedRows:     0, // strict mode doesn't import files with rejected lines
+					RejectedRows:     1,
+					IncomeRows:       1,
+					OutcomeRows:      1,
+					MonthlyRows:      map[string]int{"2022-05": 1, "2022-03": 1},
+					CategoryOutcomes: map[string]money.Amount{},
+					Balances: entity.CurrencyBalances{
+						{Currency: "MXN", IncomeRows: 1, OutcomeRows: 1, TotalIncome: 350, TotalOutcome: 160, InitialAvailable: 1000, FinalAvailable: 1190, HomeIncome: 350, HomeOutcome: 160, HomeAvailable: 1190},
+					},
+					HomeCurrency:  "MXN",
+					HomeIncome:    350,
+					HomeOutcome:   160,
+					HomeAvailable: 1190,
+				}, movementList.Summary)
+			})
+			t.Run("Previewing a file in several batches", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,05/20,-1",
+					"3,05/22,+10",
+					"4,05/28,-2",
+					"5,05/20,+0.5",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+				defaultBatchSize := batchSize
+				batchSize = 2
+				defer func() { batchSize = defaultBatchSize }()
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 1000}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"2", "1"}).Return([]string{}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"3", "4"}).Return([]string{}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"5"}).Return([]string{}, nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModeStrict, Preview: true})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(movementList.Movements)) // only the first batch is shown
+				// the line 5 has the date of the line 2 and is after it, the line 3 is before the line 1
+				assert.Equal(t, 2, movementList.Movements[0].ExternalID)
+				assert.Equal(t, money.Amount(900), movementList.Movements[0].Available)
+				assert.Equal(t, 1, movementList.Movements[1].ExternalID)
+				assert.Equal(t, money.Amount(2300), movementList.Movements[1].Available)
+				assert.Equal(t, money.Amount(2100), movementList.Summary.Balances[0].FinalAvailable)
+				assert.Equal(t, money.Amount(2100), movementList.Summary.HomeAvailable)
+			})
+			t.Run("Processing a file without valid lines in partial mode", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"a,05/25,+3.5",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("Commit").Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModePartial})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 0) // there is no currency to get its balance
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+
+				// assertion
+				assert.Nil(t, err)
+				assert.Empty(t, movementList.Movements)
+				assert.Equal(t, 1, len(movementList.Rejected))
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Invalid lines in strict mode", func(t *testing.T) {
+				invalidInput := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,juan/20,-1.6",
+					"a,03/20,-1.6",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockFileSource := new(customMocks.FileSource)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), mockFileSource)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// fake file
+				mockFileSource.On("Open", "customer_1.csv").Return(customMocks.NewFile(invalidInput), nil)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+
+				// action
+				movementList, err := sMovement.ProcessFile(1, dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockCustomerRepo.AssertNumberOfCalls(t, "Clone", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Clone", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Begin", 1)
+				mockCustomerRepo.AssertNumberOfCalls(t, "Begin", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 1)
+				mockCustomerRepo.AssertNumberOfCalls(t, "FindAndLockByCustomerID", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrInvalidFileLines)
+				assert.Empty(t, movementList.Movements)
+				assert.Equal(t, 3, movementList.Summary.TotalRows)
+				assert.Equal(t, 0, movementList.Summary.ImportedRows)
+				assert.Equal(t, 2, len(movementList.Rejected))
+				assert.Equal(t, 3, movementList.Rejected[0].Line)
+				assert.Equal(t, "date", movementList.Rejected[0].Column)
+				assert.Equal(t, 4, movementList.Rejected[1].Line)
+				assert.Equal(t, "id", movementList.Rejected[1].Column)
+			})
+			t.Run("Invalid lines in strict mode after a batch was inserted", func(t *testing.T) {
+				input := strings.Join([]string{
+					"id,date,transaction",
+					"1,05/25,+3.5",
+					"2,05/26,-1.5",
+					"3,juan/27,+10",
+					"4,05/28,-2",
+				}, "\n")
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+				defaultBatchSize := batchSize
+				batchSize = 2
+				defer func() { batchSize = defaultBatchSize }()
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(input), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "FindExistingExternalRefs", 2)
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 1) // the first batch is rolled back
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrInvalidFileLines)
+				assert.Equal(t, 0, movementList.Summary.ImportedRows)
+				assert.Equal(t, 1, movementList.Summary.RejectedRows)
+			})
+			t.Run("Repository fails on FindByFileHash", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockImportBatchRepo.On("Clone").Return(mockImportBatchRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockImportBatchRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockImportBatchRepo.On("FindByFileHash", 1, mock.AnythingOfType("string")).Return(nil, goerrors.New("repository error"))
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+				mockImportBatchRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.Error(t, err)
+				assert.Nil(t, movementList)
+			})
+			t.Run("Exchange rate service fails", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockExchangeRateService := new(customMocks.ClientExchangeRateService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, mockExchangeRateService, newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockExchangeRateService.On("Rate", "MXN", "MXN", mock.AnythingOfType("time.Time")).Return(money.Rate(0), goerrors.New("repository error"))
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModePartial})
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+
+				// assertion
+				assert.EqualError(t, err, "repository error")
+				assert.Nil(t, movementList)
+			})
+			t.Run("Balance service fails", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), mockBalanceService, newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
+				mockBalanceService.On("Recalculate", nil, 1, "MXN", mock.AnythingOfType("time.Time")).Return(goerrors.New("repository error"))
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
 
-					movement, err := sMovement.parseLine(tC.line)
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 0)
 
-					assert.Error(t, err)
-					assert.Nil(t, movement)
-				})
-			}
-		})
-	})
-	t.Run("getPath", func(t *testing.T) {
-		t.Run("Should success on", func(t *testing.T) {
-			t.Run("Getting the path", func(t *testing.T) {
-				path := getPath(1)
-				assert.Equal(t, env.FileRoute+"/customer_1.csv", path)
+				// assertion
+				assert.EqualError(t, err, "repository error")
+				assert.Nil(t, movementList)
 			})
-		})
-	})
-	t.Run("ProcessFile", func(t *testing.T) {
-		var path string
-		validLine1 := "1,5/25,+3.5"
-		validLine2 := "2,3/20,-1.6"
-		validInput := strings.Join([]string{
-			"linea 1 sin info",
-			validLine1,
-			validLine2,
-		}, "\n")
-		getPathBackup := getPath
-		t.Run("Should success on", func(t *testing.T) {
-			t.Run("Processing a valid file", func(t *testing.T) {
-				currentYear := time.Now().Year()
-				expectedMovements := []entity.Movement{
-					{
-						MovementID: 1,
-						Date:       time.Date(currentYear, 5, 25, 0, 0, 0, 0, time.UTC),
-						Quantity:   3.5,
-						Type:       constant.IncomeType,
-						CustomerID: 1,
-						Available:  3.5,
-					},
-					{
-						MovementID: 2,
-						Date:       time.Date(currentYear, 3, 20, 0, 0, 0, 0, time.UTC),
-						Quantity:   1.6,
-						Type:       constant.OutcomeType,
-						CustomerID: 1,
-						Available:  1.9,
-					},
-				}
-				getPath = func(id int) string {
-					return "testfile.csv"
-				}
-				path = getPath(1)
+			t.Run("Overdraft not allowed to the customer", func(t *testing.T) {
+				customer := &entity.Customer{CustomerID: 1, Name: "User 1", Email: "test1@hotmail.com", OverdraftPolicy: constant.OverdraftReject}
+				overdrawn := entity.Movement{MovementID: 12, ExternalID: 2, Source: "default", Currency: "MXN", Available: -30, Date: time.Date(2022, time.March, 20, 0, 0, 0, 0, time.UTC)}
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), mockBalanceService, newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
+				mockBalanceService.On("Recalculate", nil, 1, "MXN", mock.AnythingOfType("time.Time")).Return(nil)
+				mockBalanceService.On("FindOverdrafts", nil, 7).Return([]entity.Movement{overdrawn}, nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockBalanceService.AssertNotCalled(t, "FindCreditLimitOverdraft", mock.Anything, mock.Anything, mock.Anything)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 0)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrOverdraft)
+				assert.Equal(t, "overdraft", *errors.GetAction(err))
+				assert.Equal(t, map[string]interface{}{
+					"ExternalID": 2,
+					"Source":     "default",
+					"Date":       "2022-03-20",
+					"Currency":   "MXN",
+					"Available":  "-0.30",
+				}, err.(errors.MyError).GetData())
+				assert.Equal(t, 0, movementList.Summary.ImportedRows)
+			})
+			t.Run("Going over the credit limit with the balance of all currencies", func(t *testing.T) {
+				customer := &entity.Customer{CustomerID: 1, Name: "User 1", Email: "test1@hotmail.com", OverdraftPolicy: constant.OverdraftCreditLimit, CreditLimit: 1000}
+				overdraft := &entity.Overdraft{MovementID: 12, ExternalID: 2, Source: "default", Available: -1200, Date: time.Date(2022, time.March, 20, 0, 0, 0, 0, time.UTC)}
 				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
 				mockMovementRepo := new(customMocks.ClientMovementRepository)
-				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), mockBalanceService, newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(customer, nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
+				mockBalanceService.On("Recalculate", nil, 1, "MXN", mock.AnythingOfType("time.Time")).Return(nil)
+				mockBalanceService.On("FindCreditLimitOverdraft", nil, customer, 7).Return(overdraft, nil)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockBalanceService.AssertNotCalled(t, "FindOverdrafts", mock.Anything, mock.Anything)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 0)
 
-				// write a fake file
-				file, _ := os.Create(path)
-				defer file.Close()
-				file.WriteString(validInput)
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrCreditLimit)
+				assert.Equal(t, "overdraft", *errors.GetAction(err))
+				assert.Equal(t, map[string]interface{}{
+					"ExternalID": 2,
+					"Source":     "default",
+					"Date":       "2022-03-20",
+					"Currency":   "MXN",
+					"Available":  "-12.00",
+				}, err.(errors.MyError).GetData())
+				assert.Equal(t, 0, movementList.Summary.ImportedRows)
+			})
+			t.Run("Balance service fails on finding the overdraft", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockBalanceService := new(customMocks.ClientBalanceService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), mockBalanceService, newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
 
 				// mock preparation
 				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
@@ -159,56 +1700,92 @@ func TestMovementService(t *testing.T) {
 				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
 				mockMovementRepo.On("Rollback").Return(nil)
 				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
-				mockMovementRepo.On("GetLastMovementByCustomerID", 1).Return(&entity.Movement{Available: 0}, nil)
-				mockMovementRepo.On("BulkCreate", expectedMovements).Return(nil)
-				mockMovementRepo.On("Commit").Return(nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
+				mockBalanceService.On("Recalculate", nil, 1, "MXN", mock.AnythingOfType("time.Time")).Return(nil)
+				mockBalanceService.On("FindOverdrafts", nil, 7).Return(nil, goerrors.New("repository error"))
 
 				// action
-				movementList, err := sMovement.ProcessFile(1)
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
 
 				// mock assertion
-				mockCustomerRepo.AssertExpectations(t)
-				mockMovementRepo.AssertExpectations(t)
-				mockCustomerRepo.AssertNumberOfCalls(t, "Clone", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "Clone", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "Begin", 1)
-				mockCustomerRepo.AssertNumberOfCalls(t, "Begin", 1)
 				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 1)
-				mockCustomerRepo.AssertNumberOfCalls(t, "FindAndLockByCustomerID", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 0)
 
 				// assertion
-				assert.Nil(t, err)
-				assert.Equal(t, 2, len(movementList.Movements))
+				assert.EqualError(t, err, "repository error")
+				assert.Nil(t, movementList)
+			})
+			t.Run("Ledger service fails", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockLedgerService := new(customMocks.ClientLedgerService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), mockLedgerService, newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
 
-				t.Cleanup(func() {
-					os.RemoveAll(path)
-					getPath = getPathBackup
-				})
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(&entity.Movement{Available: 0}, nil)
+				mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
+				mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
+				mockLedgerService.On("PostMovements", nil, mock.AnythingOfType("[]entity.Movement")).Return(goerrors.New("repository error"))
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockImportBatchRepo.AssertNumberOfCalls(t, "Update", 0)
+
+				// assertion
+				assert.EqualError(t, err, "repository error")
+				assert.Nil(t, movementList)
 			})
-		})
-		t.Run("Should fail on", func(t *testing.T) {
-			t.Run("Invalid line", func(t *testing.T) {
-				invalidInput := strings.Join([]string{
-					"linea 1 sin info",
-					"1,5/25,+3.5",
-					"2,juan/20,-1.6",
-					"a,3/20,-1.6",
-				}, "\n")
-				getPath = func(id int) string {
-					return "testfile.csv"
-				}
-				path = getPath(1)
+			t.Run("Category rule service fails", func(t *testing.T) {
 				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
 				mockMovementRepo := new(customMocks.ClientMovementRepository)
-				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				mockCategoryRuleService := new(customMocks.ClientCategoryRuleService)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), mockCategoryRuleService, nil)
+				prepareImportBatchMock(mockImportBatchRepo)
+				rulesErr := goerrors.New("rules error")
+
+				// mock preparation
+				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+				mockMovementRepo.On("Begin", nil).Return(nil)
+				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+				mockMovementRepo.On("Rollback").Return(nil)
+				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+				mockCategoryRuleService.On("NewCategorizer", nil, 1).Return(nil, rulesErr)
+
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader("id,date,transaction\n1,05/25,+100"), dto.ImportOptions{Mode: constant.ImportModeStrict})
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
 
-				// write a fake file
-				file, _ := os.Create(path)
-				defer file.Close()
-				file.WriteString(invalidInput)
+				// assertion
+				assert.EqualError(t, err, rulesErr.Error())
+				assert.Nil(t, movementList)
+			})
+			t.Run("File without the profile columns", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
 
 				// mock preparation
 				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
@@ -217,42 +1794,46 @@ func TestMovementService(t *testing.T) {
 				mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
 				mockMovementRepo.On("Rollback").Return(nil)
 				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
-				mockMovementRepo.On("GetLastMovementByCustomerID", 1).Return(&entity.Movement{Available: 0}, nil)
 
 				// action
-				movementList, err := sMovement.ProcessFile(1)
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader("id,date,amount\n1,05/25,+3.5"), dto.ImportOptions{Mode: constant.ImportModeStrict})
 
 				// mock assertion
 				mockCustomerRepo.AssertExpectations(t)
 				mockMovementRepo.AssertExpectations(t)
-				mockCustomerRepo.AssertNumberOfCalls(t, "Clone", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "Clone", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "Begin", 1)
-				mockCustomerRepo.AssertNumberOfCalls(t, "Begin", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 0)
 				mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
-				mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 1)
-				mockCustomerRepo.AssertNumberOfCalls(t, "FindAndLockByCustomerID", 1)
-				mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
 
 				// assertion
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, errors.ErrMissingColumns)
 				assert.Nil(t, movementList)
+			})
+			t.Run("Unknown profile", func(t *testing.T) {
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), nil)
+				prepareImportBatchMock(mockImportBatchRepo)
 
-				t.Cleanup(func() {
-					os.RemoveAll(path)
-					getPath = getPathBackup
-				})
+				// action
+				movementList, err := sMovement.ProcessUpload(1, strings.NewReader(validInput), dto.ImportOptions{Profile: "unknown"})
+
+				// mock assertion
+				mockMovementRepo.AssertNumberOfCalls(t, "Clone", 0)
+
+				// assertion
+				assert.Error(t, err)
+				assert.Nil(t, movementList)
 			})
 			t.Run("Fails opening file", func(t *testing.T) {
-				getPath = func(id int) string {
-					return "testfile.csv"
-				}
-				path = getPath(1)
 				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
 				mockMovementRepo := new(customMocks.ClientMovementRepository)
-				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo)
+				mockFileSource := new(customMocks.FileSource)
+				mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), mockFileSource)
+				prepareImportBatchMock(mockImportBatchRepo)
 
+				mockFileSource.On("Open", "customer_1.csv").Return(nil, errors.ErrFileNotFound)
 				mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
 				mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
 				mockMovementRepo.On("Begin", nil).Return(nil)
@@ -261,7 +1842,7 @@ func TestMovementService(t *testing.T) {
 				mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
 
 				// action
-				movementList, err := sMovement.ProcessFile(1)
+				movementList, err := sMovement.ProcessFile(1, dto.ImportOptions{Mode: constant.ImportModeStrict})
 
 				// mock assertion
 				mockCustomerRepo.AssertExpectations(t)
@@ -276,10 +1857,6 @@ func TestMovementService(t *testing.T) {
 				// assertion
 				assert.Error(t, err)
 				assert.Nil(t, movementList)
-
-				t.Cleanup(func() {
-					getPath = getPathBackup
-				})
 			})
 			testCases := []struct {
 				name        string
@@ -306,6 +1883,7 @@ func TestMovementService(t *testing.T) {
 						mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
 						mockCustomerRepo.AssertNumberOfCalls(t, "FindAndLockByCustomerID", 1)
 						mockMovementRepo.AssertNumberOfCalls(t, "GetLastMovementByCustomerID", 0)
+						mockMovementRepo.AssertNumberOfCalls(t, "FindExistingExternalRefs", 0)
 						mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
 						mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
 					},
@@ -319,7 +1897,8 @@ func TestMovementService(t *testing.T) {
 						mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
 						mockMovementRepo.On("Rollback").Return(nil)
 						mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
-						mockMovementRepo.On("GetLastMovementByCustomerID", 1).Return(nil, goerrors.New("repository error"))
+						mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"1", "2"}).Return([]string{}, nil)
+						mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, goerrors.New("repository error"))
 					},
 					assertMock: func(mockMovementRepo *customMocks.ClientMovementRepository, mockCustomerRepo *customMocks.ClientCustomerRepository) {
 						mockCustomerRepo.AssertExpectations(t)
@@ -335,6 +1914,26 @@ func TestMovementService(t *testing.T) {
 						mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
 					},
 				},
+				{
+					name: "Repository fails on FindExistingExternalRefs",
+					prepareMock: func(mockMovementRepo *customMocks.ClientMovementRepository, mockCustomerRepo *customMocks.ClientCustomerRepository) {
+						mockCustomerRepo.On("Clone").Return(mockCustomerRepo, nil)
+						mockMovementRepo.On("Clone").Return(mockMovementRepo, nil)
+						mockMovementRepo.On("Begin", nil).Return(nil)
+						mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
+						mockMovementRepo.On("Rollback").Return(nil)
+						mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
+						mockMovementRepo.On("FindExistingExternalRefs", 1, "default", []string{"1", "2"}).Return(nil, goerrors.New("repository error"))
+					},
+					assertMock: func(mockMovementRepo *customMocks.ClientMovementRepository, mockCustomerRepo *customMocks.ClientCustomerRepository) {
+						mockCustomerRepo.AssertExpectations(t)
+						mockMovementRepo.AssertExpectations(t)
+						mockMovementRepo.AssertNumberOfCalls(t, "Rollback", 1)
+						mockMovementRepo.AssertNumberOfCalls(t, "FindExistingExternalRefs", 1)
+						mockMovementRepo.AssertNumberOfCalls(t, "BulkCreate", 0)
+						mockMovementRepo.AssertNumberOfCalls(t, "Commit", 0)
+					},
+				},
 				{
 					name: "Repository fails on BulkCreate",
 					prepareMock: func(mockMovementRepo *customMocks.ClientMovementRepository, mockCustomerRepo *customMocks.ClientCustomerRepository) {
@@ -344,7 +1943,8 @@ func TestMovementService(t *testing.T) {
 						mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
 						mockMovementRepo.On("Rollback").Return(nil)
 						mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
-						mockMovementRepo.On("GetLastMovementByCustomerID", 1).Return(nil, errors.ErrNotFound)
+						mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+						mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
 						mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(goerrors.New("repository error"))
 					},
 					assertMock: func(mockMovementRepo *customMocks.ClientMovementRepository, mockCustomerRepo *customMocks.ClientCustomerRepository) {
@@ -370,7 +1970,8 @@ func TestMovementService(t *testing.T) {
 						mockCustomerRepo.On("Begin", mock.Anything).Return(nil)
 						mockMovementRepo.On("Rollback").Return(nil)
 						mockCustomerRepo.On("FindAndLockByCustomerID", 1).Return(&customers[0], nil)
-						mockMovementRepo.On("GetLastMovementByCustomerID", 1).Return(nil, errors.ErrNotFound)
+						mockMovementRepo.On("GetLastMovementByCustomerID", 1, "MXN").Return(nil, errors.ErrNotFound)
+						mockMovementRepo.On("FindExistingExternalRefs", 1, "default", mock.AnythingOfType("[]string")).Return([]string{}, nil)
 						mockMovementRepo.On("BulkCreate", mock.AnythingOfType("[]entity.Movement")).Return(nil)
 						mockMovementRepo.On("Commit").Return(goerrors.New("commit error"))
 					},
@@ -391,24 +1992,21 @@ func TestMovementService(t *testing.T) {
 			}
 			for _, tC := range testCases {
 				t.Run(tC.name, func(t *testing.T) {
-					getPath = func(id int) string {
-						return "testfile.csv"
-					}
-					path = getPath(1)
 					mockCustomerRepo := new(customMocks.ClientCustomerRepository)
 					mockMovementRepo := new(customMocks.ClientMovementRepository)
-					sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo)
+					mockFileSource := new(customMocks.FileSource)
+					mockImportBatchRepo := new(customMocks.ClientImportBatchRepository)
+					sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, mockImportBatchRepo, newExchangeRateMock(), newBalanceMock(), newLedgerMock(), newCategoryRuleMock(), mockFileSource)
+					prepareImportBatchMock(mockImportBatchRepo)
 
-					// write a fake file
-					file, _ := os.Create(path)
-					defer file.Close()
-					file.WriteString(validInput)
+					// fake file
+					mockFileSource.On("Open", "customer_1.csv").Return(customMocks.NewFile(validInput), nil)
 
 					// mock preparation
 					tC.prepareMock(mockMovementRepo, mockCustomerRepo)
 
 					// action
-					movementList, err := sMovement.ProcessFile(1)
+					movementList, err := sMovement.ProcessFile(1, dto.ImportOptions{Mode: constant.ImportModeStrict})
 
 					// mock assertion
 					mockCustomerRepo.AssertExpectations(t)
@@ -418,12 +2016,196 @@ func TestMovementService(t *testing.T) {
 					// assertion
 					assert.Nil(t, movementList)
 					assert.Error(t, err)
-					t.Cleanup(func() {
-						os.RemoveAll(path)
-						getPath = getPathBackup
-					})
 				})
 			}
 		})
 	})
+	t.Run("FindByCustomerID", func(t *testing.T) {
+		filter := dto.MovementFilter{Type: constant.IncomeType, Sort: constant.MovementSortDateDesc}
+		customerMovements := []entity.Movement{{MovementID: 2, CustomerID: 1}, {MovementID: 1, CustomerID: 1}}
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the movements of the customer", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, nil, nil, nil, nil, nil, nil)
+				pagination := dto.NewPagination(1, 20, 0)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockMovementRepo.On("FindByCustomerID", 1, filter, pagination).Return(customerMovements, nil)
+
+				// action
+				got, err := sMovement.FindByCustomerID(1, filter, pagination)
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, customerMovements, got)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Customer doesn't exist", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, nil, nil, nil, nil, nil, nil)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(nil, errors.ErrNotFound)
+
+				// action
+				got, err := sMovement.FindByCustomerID(1, filter, dto.NewPagination(1, 20, 0))
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "FindByCustomerID", 0)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrNotFound)
+				assert.Nil(t, got)
+			})
+			t.Run("Repository fails finding the movements", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, nil, nil, nil, nil, nil, nil)
+				repositoryErr := goerrors.New("repository error")
+				pagination := dto.NewPagination(1, 20, 0)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockMovementRepo.On("FindByCustomerID", 1, filter, pagination).Return(nil, repositoryErr)
+
+				// action
+				got, err := sMovement.FindByCustomerID(1, filter, pagination)
+
+				// mock assertion
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.EqualError(t, err, repositoryErr.Error())
+				assert.Nil(t, got)
+			})
+		})
+	})
+	t.Run("FindPageByCustomerID", func(t *testing.T) {
+		filter := dto.MovementFilter{Sort: constant.MovementSortDateDesc}
+		customerMovements := []entity.Movement{{MovementID: 2, CustomerID: 1}, {MovementID: 1, CustomerID: 1}}
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding a page of the movements of the customer", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, nil, nil, nil, nil, nil, nil)
+				pagination := dto.NewCursorPagination(20, nil)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(&entity.Customer{CustomerID: 1}, nil)
+				mockMovementRepo.On("FindPageByCustomerID", 1, filter, pagination).Return(customerMovements, nil)
+
+				// action
+				got, err := sMovement.FindPageByCustomerID(1, filter, pagination)
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertExpectations(t)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.Equal(t, customerMovements, got)
+			})
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Sorting by amount", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, nil, nil, nil, nil, nil, nil)
+
+				// action
+				got, err := sMovement.FindPageByCustomerID(1, dto.MovementFilter{Sort: constant.MovementSortAmount}, dto.NewCursorPagination(20, nil))
+
+				// mock assertion
+				mockCustomerRepo.AssertNumberOfCalls(t, "FindByCustomerID", 0)
+				mockMovementRepo.AssertNumberOfCalls(t, "FindPageByCustomerID", 0)
+
+				// assertion
+				assert.EqualError(t, err, errors.ErrFieldValidation("sort", "oneof", "date -date").Error())
+				assert.Nil(t, got)
+			})
+			t.Run("Customer doesn't exist", func(t *testing.T) {
+				mockMovementRepo := new(customMocks.ClientMovementRepository)
+				mockCustomerRepo := new(customMocks.ClientCustomerRepository)
+				sMovement := NewMovementService(mockMovementRepo, mockCustomerRepo, nil, nil, nil, nil, nil, nil)
+
+				// mock preparation
+				mockCustomerRepo.On("FindByCustomerID", 1).Return(nil, errors.ErrNotFound)
+
+				// action
+				got, err := sMovement.FindPageByCustomerID(1, filter, dto.NewCursorPagination(20, nil))
+
+				// mock assertion
+				mockCustomerRepo.AssertExpectations(t)
+				mockMovementRepo.AssertNumberOfCalls(t, "FindPageByCustomerID", 0)
+
+				// assertion
+				assert.ErrorIs(t, err, errors.ErrNotFound)
+				assert.Nil(t, got)
+			})
+		})
+	})
+}
+
+/*
+prepareImportBatchMock prepares the import batch repository for a file that wasn't imported before,
+the created batch gets the ID 7
+*/
+func prepareImportBatchMock(mockImportBatchRepo *customMocks.ClientImportBatchRepository) {
+	mockImportBatchRepo.On("Clone").Return(mockImportBatchRepo, nil)
+	mockImportBatchRepo.On("Begin", mock.Anything).Return(nil)
+	mockImportBatchRepo.On("FindByFileHash", 1, mock.AnythingOfType("string")).Return(nil, errors.ErrNotFound)
+	mockImportBatchRepo.On("Create", mock.AnythingOfType("*entity.ImportBatch")).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.ImportBatch).ImportBatchID = 7
+	}).Return(nil)
+	mockImportBatchRepo.On("Update", mock.AnythingOfType("*entity.ImportBatch")).Return(nil)
+}
+
+/*
+newBalanceMock returns a balance service that recalculates any balance without errors nor overdrafts
+*/
+func newBalanceMock() *customMocks.ClientBalanceService {
+	mockBalanceService := new(customMocks.ClientBalanceService)
+	mockBalanceService.On("Recalculate", mock.Anything, mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	mockBalanceService.On("FindOverdrafts", mock.Anything, mock.AnythingOfType("int")).Return([]entity.Movement{}, nil)
+	mockBalanceService.On("FindCreditLimitOverdraft", mock.Anything, mock.AnythingOfType("*entity.Customer"), mock.AnythingOfType("int")).Return(nil, nil)
+	return mockBalanceService
+}
+
+/*
+newCategoryRuleMock returns a category rule service with a categorizer of the given rules
+*/
+func newCategoryRuleMock(categoryRules ...entity.CategoryRule) *customMocks.ClientCategoryRuleService {
+	rulesCategorizer, _ := categorizer.New(categoryRules)
+	mockCategoryRuleService := new(customMocks.ClientCategoryRuleService)
+	mockCategoryRuleService.On("NewCategorizer", mock.Anything, mock.AnythingOfType("int")).Return(rulesCategorizer, nil)
+	return mockCategoryRuleService
+}
+
+/*
+newLedgerMock returns a ledger service that posts any movement without errors
+*/
+func newLedgerMock() *customMocks.ClientLedgerService {
+	mockLedgerService := new(customMocks.ClientLedgerService)
+	mockLedgerService.On("PostMovements", mock.Anything, mock.AnythingOfType("[]entity.Movement")).Return(nil)
+	return mockLedgerService
+}
+
+/*
+newExchangeRateMock returns an exchange rate service where every currency converts to the home currency
+with a rate of one, USD at 20
+*/
+func newExchangeRateMock() *customMocks.ClientExchangeRateService {
+	mockExchangeRateService := new(customMocks.ClientExchangeRateService)
+	mockExchangeRateService.On("Rate", "USD", "MXN", mock.AnythingOfType("time.Time")).Return(money.Rate(2000000000), nil)
+	mockExchangeRateService.On("Rate", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(money.OneRate, nil)
+	return mockExchangeRateService
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"net/http"
+	"net/url"
 	"stori-service/src/environments/common/resources/controller"
 	"stori-service/src/libs/dto"
 	"stori-service/src/utils/constant"
@@ -22,6 +23,13 @@ func (c *ClientController) MakePaginateResponse(response http.ResponseWriter, da
 	c.BaseController.MakePaginateResponse(constant.ClientCollection, response, data, statusCode, pagination)
 }
 
+/*
+MakeCursorPaginateResponse partial application for base method
+*/
+func (c *ClientController) MakeCursorPaginateResponse(response http.ResponseWriter, requestURL *url.URL, data interface{}, statusCode int, pagination *dto.CursorPagination) {
+	c.BaseController.MakeCursorPaginateResponse(constant.ClientCollection, response, requestURL, data, statusCode, pagination)
+}
+
 /*
 MakeSuccessResponse partial application for base method
 */
@@ -53,6 +53,35 @@ func TestBaseController_MakePaginateResponse(t *testing.T) {
 	})
 }
 
+func TestBaseController_MakeCursorPaginateResponse(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Correct header data", func(t *testing.T) {
+			// Fixture
+			page := &dto.CursorPagination{PageSize: 10}
+
+			// Run Foo inside request
+			response := mock.MHTTPHandle("GET", "/",
+				func(response http.ResponseWriter, request *http.Request) {
+					assert.NotPanics(t, func() {
+						defaultController.MakeCursorPaginateResponse(
+							response,
+							request.URL,
+							defaultData,
+							http.StatusOK,
+							page)
+					})
+				}, "", nil, nil)
+			defer response.Body.Close()
+			body, _ := utils.GetBodyResponse(response, &DData{})
+
+			// assert data
+			assert.Equal(t, "10", response.Header.Get("X-pagination-page-size"))
+			assert.Equal(t, defaultData.Client, body.Data.(*DData).Client)
+			assert.Zero(t, body.Data.(*DData).Admin)
+		})
+	})
+}
+
 func TestBaseController_MakeSuccessResponse(t *testing.T) {
 	t.Run("Should Succeed", func(t *testing.T) {
 		t.Run("Correct OK response", func(t *testing.T) {
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"net/http"
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"time"
+)
+
+/*
+	IBalanceService methods with bussiness logic
+*/
+type IBalanceService interface {
+	Recalculate(tx interface{}, customerID int, currency string, from time.Time) error
+	FindOverdrafts(tx interface{}, importBatchID int) ([]entity.Movement, error)
+	FindCreditLimitOverdraft(tx interface{}, customer *entity.Customer, importBatchID int) (*entity.Overdraft, error)
+	FindAt(customerID int, filter dto.BalanceFilter) (*dto.BalanceAt, error)
+}
+
+/*
+	IBalanceController methods to handle requests and responses
+*/
+type IBalanceController interface {
+	FindAt(response http.ResponseWriter, request *http.Request)
+}
@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"net/http"
+	"stori-service/src/environments/common/resources/entity"
+	commonInterfaces "stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/categorizer"
+)
+
+/*
+ICategoryRuleRepository to interact with entity and database
+*/
+type ICategoryRuleRepository interface {
+	commonInterfaces.ITransactionalRepository
+	Create(categoryRule *entity.CategoryRule) error
+	Update(categoryRule *entity.CategoryRule) error
+	Delete(categoryRuleID int) error
+	FindByID(categoryRuleID int) (*entity.CategoryRule, error)
+	Find(customerID *int) ([]entity.CategoryRule, error)
+}
+
+/*
+	ICategoryRuleService methods with bussiness logic
+*/
+type ICategoryRuleService interface {
+	Create(categoryRule entity.CategoryRule) (*entity.CategoryRule, error)
+	Update(categoryRuleID int, categoryRule entity.CategoryRule) (*entity.CategoryRule, error)
+	Delete(categoryRuleID int) error
+	FindByID(categoryRuleID int) (*entity.CategoryRule, error)
+	Find(customerID *int) ([]entity.CategoryRule, error)
+	NewCategorizer(tx interface{}, customerID int) (*categorizer.Categorizer, error)
+	Recategorize(customerID int) (int, error)
+}
+
+/*
+	ICategoryRuleController methods to handle requests and responses
+*/
+type ICategoryRuleController interface {
+	List(response http.ResponseWriter, request *http.Request)
+	FindByID(response http.ResponseWriter, request *http.Request)
+	Create(response http.ResponseWriter, request *http.Request)
+	Update(response http.ResponseWriter, request *http.Request)
+	Delete(response http.ResponseWriter, request *http.Request)
+}
@@ -12,4 +12,5 @@ type ICustomerRepository interface {
 	interfaces.ITransactionalRepository
 	FindAndLockByCustomerID(id int) (*entity.Customer, error)
 	FindByCustomerID(id int) (*entity.Customer, error)
+	FindIDs() ([]int, error)
 }
@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"io"
+	"stori-service/src/environments/common/resources/entity"
+	commonInterfaces "stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/money"
+	"time"
+)
+
+/*
+IExchangeRateRepository to interact with entity and database
+*/
+type IExchangeRateRepository interface {
+	commonInterfaces.ITransactionalRepository
+	FindEffective(baseCurrency string, quoteCurrency string, date time.Time) (*entity.ExchangeRate, error)
+	Upsert(exchangeRates []entity.ExchangeRate) error
+}
+
+/*
+	IExchangeRateService methods with bussiness logic
+*/
+type IExchangeRateService interface {
+	Rate(from string, to string, date time.Time) (money.Rate, error)
+	Convert(amount money.Amount, from string, to string, date time.Time) (money.Amount, error)
+	ImportRates(file io.Reader) (int, error)
+}
@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"net/http"
+	"stori-service/src/environments/common/resources/entity"
+	commonInterfaces "stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/dto"
+)
+
+/*
+IImportBatchRepository to interact with entity and database
+*/
+type IImportBatchRepository interface {
+	commonInterfaces.ITransactionalRepository
+	Create(importBatch *entity.ImportBatch) error
+	Update(importBatch *entity.ImportBatch) error
+	FindByFileHash(customerID int, fileHash string) (*entity.ImportBatch, error)
+	FindAndLockByID(importBatchID int) (*entity.ImportBatch, error)
+}
+
+/*
+	IImportBatchService methods with bussiness logic
+*/
+type IImportBatchService interface {
+	Revert(importBatchID int, importRevert dto.ImportRevert) (*entity.ImportBatch, error)
+}
+
+/*
+	IImportBatchController methods to handle requests and responses
+*/
+type IImportBatchController interface {
+	Revert(response http.ResponseWriter, request *http.Request)
+}
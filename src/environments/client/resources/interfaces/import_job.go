@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"net/http"
+	"stori-service/src/environments/common/resources/entity"
+	commonInterfaces "stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/dto"
+)
+
+/*
+ImportFunc runs an import and returns the movements that were created
+*/
+type ImportFunc func() (*dto.MovementList, error)
+
+/*
+IImportJobRepository to interact with entity and database
+*/
+type IImportJobRepository interface {
+	commonInterfaces.ITransactionalRepository
+	Create(importJob *entity.ImportJob) error
+	Update(importJob *entity.ImportJob) error
+	FindByID(importJobID int) (*entity.ImportJob, error)
+	FindUnfinished() ([]entity.ImportJob, error)
+}
+
+/*
+	IImportJobService methods with bussiness logic
+*/
+type IImportJobService interface {
+	Enqueue(customerID int, run ImportFunc) (*entity.ImportJob, error)
+	FindByID(importJobID int) (*entity.ImportJob, error)
+	FailUnfinished() error
+}
+
+/*
+	IImportJobController methods to handle requests and responses
+*/
+type IImportJobController interface {
+	FindByID(response http.ResponseWriter, request *http.Request)
+}
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"net/http"
+	"stori-service/src/environments/common/resources/entity"
+	commonInterfaces "stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/dto"
+	"time"
+)
+
+/*
+ILedgerRepository to interact with entity and database
+*/
+type ILedgerRepository interface {
+	commonInterfaces.ITransactionalRepository
+	UpsertAccount(account *entity.Account) error
+	CreateEntries(journalEntries []entity.JournalEntry) error
+	FindEntriesByImportBatchID(importBatchID int) ([]entity.JournalEntry, error)
+	FindAccountBalances() ([]dto.AccountBalance, error)
+}
+
+/*
+	ILedgerService methods with bussiness logic
+*/
+type ILedgerService interface {
+	PostMovements(tx interface{}, movements []entity.Movement) error
+	ReverseImportBatch(tx interface{}, importBatchID int, date time.Time) error
+	TrialBalance() (*dto.TrialBalance, error)
+}
+
+/*
+	ILedgerController methods to handle requests and responses
+*/
+type ILedgerController interface {
+	TrialBalance(response http.ResponseWriter, request *http.Request)
+}
@@ -1,10 +1,13 @@
 package interfaces
 
 import (
+	"io"
 	"net/http"
 	"stori-service/src/environments/common/resources/entity"
 	commonInterfaces "stori-service/src/environments/common/resources/interfaces"
 	"stori-service/src/libs/dto"
+	"stori-service/src/libs/money"
+	"time"
 )
 
 /*
@@ -13,14 +16,32 @@ IMovementRepository to interact with entity and database
 type IMovementRepository interface {
 	commonInterfaces.ITransactionalRepository
 	BulkCreate(movements []entity.Movement) error
-	GetLastMovementByCustomerID(customerID int) (*entity.Movement, error)
+	GetLastMovementByCustomerID(customerID int, currency string) (*entity.Movement, error)
+	GetLastMovementsAtDate(customerID int, date time.Time) ([]entity.Movement, error)
+	GetTotalsByCustomerID(customerID int, from, to *time.Time) ([]dto.CurrencyTotals, error)
+	FindExistingExternalRefs(customerID int, source string, externalRefs []string) ([]string, error)
+	DeleteByImportBatchID(importBatchID int) error
+	GetLastMovementBeforeDate(customerID int, currency string, date time.Time) (*entity.Movement, error)
+	UpdateAvailableFromDate(customerID int, currency string, date time.Time, opening money.Amount) error
+	FindFirstDatesByImportBatchID(importBatchID int) (map[string]time.Time, error)
+	FindFirstOverdrawn(importBatchID int) ([]entity.Movement, error)
+	FindFirstOverCreditLimit(customerID int, importBatchID int, creditLimit money.Amount) (*entity.Overdraft, error)
+	FindByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.Pagination) ([]entity.Movement, error)
+	FindPageByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.CursorPagination) ([]entity.Movement, error)
+	FindCategorizable(customerID int, afterID int, limit int) ([]entity.Movement, error)
+	UpdateCategory(movementID int, category string, categoryRuleID *int) error
 }
 
 /*
 	IMovementService methods with bussiness logic
 */
 type IMovementService interface {
-	ProcessFile(customerID int) (*dto.MovementList, error)
+	ProcessFile(customerID int, options dto.ImportOptions) (*dto.MovementList, error)
+	ProcessUpload(customerID int, file io.ReadSeeker, options dto.ImportOptions) (*dto.MovementList, error)
+	FindImportedFile(customerID int, options dto.ImportOptions) (*entity.ImportBatch, error)
+	FindImportedUpload(customerID int, file io.ReadSeeker) (*entity.ImportBatch, error)
+	FindByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.Pagination) ([]entity.Movement, error)
+	FindPageByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.CursorPagination) ([]entity.Movement, error)
 }
 
 /*
@@ -28,4 +49,6 @@ type IMovementService interface {
 */
 type IMovementController interface {
 	ProcessFile(response http.ResponseWriter, request *http.Request)
+	UploadFile(response http.ResponseWriter, request *http.Request)
+	List(response http.ResponseWriter, request *http.Request)
 }
@@ -1,9 +1,18 @@
 package router
 
 import (
+	"stori-service/src/environments/client/modules/balance"
+	"stori-service/src/environments/client/modules/categoryrule"
 	"stori-service/src/environments/client/modules/customer"
+	"stori-service/src/environments/client/modules/exchangerate"
+	"stori-service/src/environments/client/modules/importbatch"
+	"stori-service/src/environments/client/modules/importjob"
+	"stori-service/src/environments/client/modules/ledger"
 	movement "stori-service/src/environments/client/modules/movement"
+	"stori-service/src/environments/client/resources/interfaces"
 	"stori-service/src/libs/database"
+	"stori-service/src/libs/env"
+	"stori-service/src/libs/filesource"
 
 	"github.com/gorilla/mux"
 )
@@ -12,17 +21,103 @@ import (
 SetupClientRoutes creates all instances for client enviroment and calls each router
 */
 func SetupClientRoutes(subRouter *mux.Router) {
-	movementRoutes(subRouter.PathPrefix("/client-movements").Subrouter())
+	connection := database.GetStoriGormConnection()
+	rImportJob := importjob.NewImportJobGormRepo(connection)
+	sImportJob := importjob.NewImportJobService(rImportJob, env.ImportWorkers, env.ImportQueueSize)
+	if err := sImportJob.FailUnfinished(); err != nil {
+		panic(err)
+	}
+	customerRouter := subRouter.PathPrefix("/customers").Subrouter()
+	movementRoutes(subRouter.PathPrefix("/client-movements").Subrouter(), customerRouter, sImportJob)
+	importJobRoutes(subRouter.PathPrefix("/import-jobs").Subrouter(), sImportJob)
+	importBatchRoutes(subRouter.PathPrefix("/import-batches").Subrouter())
+	ledgerRoutes(subRouter.PathPrefix("/ledger").Subrouter())
+	balanceRoutes(customerRouter)
+	categoryRuleRoutes(subRouter.PathPrefix("/category-rules").Subrouter())
 }
 
 /*
-movementRoutes creates the router for movement module
+movementRoutes creates the router for movement module, its listing goes under the customers
 */
-func movementRoutes(subRouter *mux.Router) {
+func movementRoutes(subRouter *mux.Router, customerRouter *mux.Router, sImportJob interfaces.IImportJobService) {
 	connection := database.GetStoriGormConnection()
 	rMovement := movement.NewMovementGormRepo(connection)
 	rCustomer := customer.NewCustomerGormRepo(connection)
-	sMovement := movement.NewMovementService(rMovement, rCustomer)
-	cMovement := movement.NewMovementController(sMovement)
+	rImportBatch := importbatch.NewImportBatchGormRepo(connection)
+	rExchangeRate := exchangerate.NewExchangeRateGormRepo(connection)
+	sExchangeRate := exchangerate.NewExchangeRateService(rExchangeRate)
+	sBalance := balance.NewBalanceService(rMovement, rCustomer)
+	rLedger := ledger.NewLedgerGormRepo(connection)
+	sLedger := ledger.NewLedgerService(rLedger)
+	fileSource, err := filesource.NewFileSource()
+	if err != nil {
+		panic(err)
+	}
+	rCategoryRule := categoryrule.NewCategoryRuleGormRepo(connection)
+	sCategoryRule := categoryrule.NewCategoryRuleService(rCategoryRule, rCustomer, rMovement)
+	sMovement := movement.NewMovementService(rMovement, rCustomer, rImportBatch, sExchangeRate, sBalance, sLedger, sCategoryRule, fileSource)
+	cMovement := movement.NewMovementController(sMovement, sImportJob)
 	movement.NewMovementRouter(subRouter, cMovement)
+	movement.NewCustomerMovementRouter(customerRouter, cMovement)
+}
+
+/*
+importJobRoutes creates the router for import job module, the service is shared
+with the modules that queue the jobs
+*/
+func importJobRoutes(subRouter *mux.Router, sImportJob interfaces.IImportJobService) {
+	cImportJob := importjob.NewImportJobController(sImportJob)
+	importjob.NewImportJobRouter(subRouter, cImportJob)
+}
+
+/*
+importBatchRoutes creates the router for import batch module
+*/
+func importBatchRoutes(subRouter *mux.Router) {
+	connection := database.GetStoriGormConnection()
+	rImportBatch := importbatch.NewImportBatchGormRepo(connection)
+	rMovement := movement.NewMovementGormRepo(connection)
+	rCustomer := customer.NewCustomerGormRepo(connection)
+	sBalance := balance.NewBalanceService(rMovement, rCustomer)
+	rLedger := ledger.NewLedgerGormRepo(connection)
+	sLedger := ledger.NewLedgerService(rLedger)
+	sImportBatch := importbatch.NewImportBatchService(rImportBatch, rMovement, rCustomer, sBalance, sLedger)
+	cImportBatch := importbatch.NewImportBatchController(sImportBatch)
+	importbatch.NewImportBatchRouter(subRouter, cImportBatch)
+}
+
+/*
+ledgerRoutes creates the router for ledger module
+*/
+func ledgerRoutes(subRouter *mux.Router) {
+	connection := database.GetStoriGormConnection()
+	rLedger := ledger.NewLedgerGormRepo(connection)
+	sLedger := ledger.NewLedgerService(rLedger)
+	cLedger := ledger.NewLedgerController(sLedger)
+	ledger.NewLedgerRouter(subRouter, cLedger)
+}
+
+/*
+balanceRoutes creates the router for balance module, it goes under the customers
+*/
+func balanceRoutes(customerRouter *mux.Router) {
+	connection := database.GetStoriGormConnection()
+	rMovement := movement.NewMovementGormRepo(connection)
+	rCustomer := customer.NewCustomerGormRepo(connection)
+	sBalance := balance.NewBalanceService(rMovement, rCustomer)
+	cBalance := balance.NewBalanceController(sBalance)
+	balance.NewCustomerBalanceRouter(customerRouter, cBalance)
+}
+
+/*
+categoryRuleRoutes creates the router for category rule module
+*/
+func categoryRuleRoutes(subRouter *mux.Router) {
+	connection := database.GetStoriGormConnection()
+	rCategoryRule := categoryrule.NewCategoryRuleGormRepo(connection)
+	rCustomer := customer.NewCustomerGormRepo(connection)
+	rMovement := movement.NewMovementGormRepo(connection)
+	sCategoryRule := categoryrule.NewCategoryRuleService(rCategoryRule, rCustomer, rMovement)
+	cCategoryRule := categoryrule.NewCategoryRuleController(sCategoryRule)
+	categoryrule.NewCategoryRuleRouter(subRouter, cCategoryRule)
 }
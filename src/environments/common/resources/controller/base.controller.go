@@ -2,6 +2,7 @@ package controller
 
 import (
 	"net/http"
+	"net/url"
 	"stori-service/src/libs/dto"
 	"stori-service/src/libs/logger"
 	"stori-service/src/utils"
@@ -20,6 +21,14 @@ func (b *BaseController) MakePaginateResponse(collection string, response http.R
 	utils.MakePaginateResponse(response, parsedData, statusCode, pagination)
 }
 
+/*
+MakeCursorPaginateResponse Set Data array, cursor pagination headers and Errors to empty array
+*/
+func (b *BaseController) MakeCursorPaginateResponse(collection string, response http.ResponseWriter, requestURL *url.URL, data interface{}, statusCode int, pagination *dto.CursorPagination) {
+	parsedData := sheriffParse(collection, data)
+	utils.MakeCursorPaginateResponse(response, requestURL, parsedData, statusCode, pagination)
+}
+
 /*
 MakeSuccessResponse Set Message, Data object and Errors to empty array
 */
@@ -58,6 +58,38 @@ func TestBaseController_MakePaginateResponse(t *testing.T) {
 	})
 }
 
+func TestBaseController_MakeCursorPaginateResponse(t *testing.T) {
+	t.Run("Should Succeed", func(t *testing.T) {
+		t.Run("Correct header data", func(t *testing.T) {
+			// Fixture
+			page := &dto.CursorPagination{
+				PageSize: 10,
+				Next:     &dto.Cursor{MovementID: 8},
+			}
+
+			// Run Foo inside request
+			response := customMock.MHTTPHandle("GET", "/",
+				func(response http.ResponseWriter, request *http.Request) {
+					assert.NotPanics(t, func() {
+						defaultController.MakeCursorPaginateResponse(
+							defaultCollection,
+							response,
+							request.URL,
+							dto.NewBodyResponse(defaultMessage, defaultErrors, defaultData),
+							http.StatusOK,
+							page,
+						)
+					})
+				}, "", nil, nil)
+
+			// Assert Data
+			assert.Equal(t, "10", response.Header.Get("X-pagination-page-size"))
+			assert.Equal(t, page.Next.Encode(), response.Header.Get("X-pagination-next-cursor"))
+			assert.Contains(t, response.Header.Get("Link"), `rel="next"`)
+		})
+	})
+}
+
 func TestBaseController_MakeSuccessResponse(t *testing.T) {
 	t.Run("Should Succeed", func(t *testing.T) {
 		t.Run("Correct OK response", func(t *testing.T) {
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"fmt"
+	"stori-service/src/libs/validator"
+	"stori-service/src/utils/constant"
+	"time"
+)
+
+/*
+Account model for account table, an account of the ledger in one currency. Each customer has
+an account for each currency of its movements and the money of the movements comes from or goes to
+the clearing account of the currency, the one reconciled against the bank
+*/
+type Account struct {
+	AccountID  int       `json:"account_id" gorm:"primaryKey" groups:"client"`
+	Code       string    `json:"code" groups:"client" validate:"required,max=50"`
+	Type       string    `json:"type" groups:"client" validate:"required,oneof=customer clearing"`
+	CustomerID *int      `json:"customer_id" groups:"client"`
+	Currency   string    `json:"currency" groups:"client" validate:"required,len=3"`
+	CreatedAt  time.Time `json:"created_at" groups:""`
+	UpdatedAt  time.Time `json:"updated_at" groups:""`
+}
+
+/*
+NewCustomerAccount returns the account of the customer in the currency
+*/
+func NewCustomerAccount(customerID int, currency string) Account {
+	return Account{
+		Code:       fmt.Sprintf("CUSTOMER-%d-%s", customerID, currency),
+		Type:       constant.CustomerAccount,
+		CustomerID: &customerID,
+		Currency:   currency,
+	}
+}
+
+/*
+NewClearingAccount returns the clearing account of the currency
+*/
+func NewClearingAccount(currency string) Account {
+	return Account{
+		Code:     fmt.Sprintf("CLEARING-%s", currency),
+		Type:     constant.ClearingAccount,
+		Currency: currency,
+	}
+}
+
+/*
+Validate returns an error if entity doesn't pass any of its own validations
+*/
+func (account *Account) Validate() error {
+	if err := validator.ValidateStruct(account); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"stori-service/src/utils/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Customer account", func(t *testing.T) {
+			// action
+			account := NewCustomerAccount(1, "MXN")
+			err := account.Validate()
+			// assertion
+			assert.NoError(t, err)
+			assert.Equal(t, "CUSTOMER-1-MXN", account.Code)
+			assert.Equal(t, constant.CustomerAccount, account.Type)
+			assert.Equal(t, 1, *account.CustomerID)
+		})
+		t.Run("Clearing account", func(t *testing.T) {
+			// action
+			account := NewClearingAccount("USD")
+			err := account.Validate()
+			// assertion
+			assert.NoError(t, err)
+			assert.Equal(t, "CLEARING-USD", account.Code)
+			assert.Equal(t, constant.ClearingAccount, account.Type)
+			assert.Nil(t, account.CustomerID)
+		})
+	})
+
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			input *Account
+		}{
+			{
+				name:  "Without Code",
+				input: &Account{Type: constant.ClearingAccount, Currency: "MXN"},
+			},
+			{
+				name:  "Invalid Type",
+				input: &Account{Code: "CASH-MXN", Type: "cash", Currency: "MXN"},
+			},
+			{
+				name:  "Invalid Currency",
+				input: &Account{Code: "CLEARING-MX", Type: constant.ClearingAccount, Currency: "MX"},
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				// action
+				err := testCase.input.Validate()
+				// assertion
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"regexp"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/libs/validator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/*
+CategoryRule model for category_rule table, it sets the Category of the movements it matches.
+Rules without CustomerID are global. A movement matches when its description matches the DescriptionPattern
+regular expression, its HomeQuantity is between MinAmount and MaxAmount and it's of the rule Type,
+empty conditions match any movement. Lower Priority rules are tried first
+*/
+type CategoryRule struct {
+	CategoryRuleID     int            `json:"category_rule_id" gorm:"primaryKey" groups:"client"`
+	CustomerID         *int           `json:"customer_id" groups:"client" validate:"omitempty,gte=1"`
+	Category           string         `json:"category" groups:"client" validate:"required,max=50"`
+	DescriptionPattern string         `json:"description_pattern" groups:"client" validate:"max=255"`
+	MinAmount          *money.Amount  `json:"min_amount" groups:"client" validate:"omitempty,gte=0"`
+	MaxAmount          *money.Amount  `json:"max_amount" groups:"client" validate:"omitempty,gte=0"`
+	Type               int            `json:"type" groups:"client" validate:"eq=0|eq=1|eq=-1"`
+	Priority           int            `json:"priority" groups:"client" validate:"gte=0"`
+	CreatedAt          time.Time      `json:"created_at" groups:"client"`
+	UpdatedAt          time.Time      `json:"updated_at" groups:""`
+	DeletedAt          gorm.DeletedAt `json:"deleted_at" groups:""`
+}
+
+/*
+Validate returns an error if entity doesn't pass any of its own validations, the pattern isn't
+a valid regular expression or the amount range is empty
+*/
+func (categoryRule *CategoryRule) Validate() error {
+	if err := validator.ValidateStruct(categoryRule); err != nil {
+		return err
+	}
+	if _, err := regexp.Compile(categoryRule.DescriptionPattern); err != nil {
+		return errors.ErrFieldValidation("DescriptionPattern", "regexp", "")
+	}
+	if categoryRule.MinAmount != nil && categoryRule.MaxAmount != nil && *categoryRule.MinAmount > *categoryRule.MaxAmount {
+		return errors.ErrFieldValidation("MaxAmount", "gtefield", "MinAmount")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"stori-service/src/libs/money"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryRule(t *testing.T) {
+	// fixture
+	validCustomerID := 1
+	validCategory := "Groceries"
+	validPattern := "walmart|soriana"
+	minAmount := money.Amount(10000)
+	maxAmount := money.Amount(50000)
+	negativeAmount := money.Amount(-100)
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			input *CategoryRule
+		}{
+			{
+				name: "Global rule",
+				input: &CategoryRule{
+					Category:           validCategory,
+					DescriptionPattern: validPattern,
+				},
+			},
+			{
+				name: "Customer rule with every condition",
+				input: &CategoryRule{
+					CustomerID:         &validCustomerID,
+					Category:           validCategory,
+					DescriptionPattern: validPattern,
+					MinAmount:          &minAmount,
+					MaxAmount:          &maxAmount,
+					Type:               -1,
+					Priority:           2,
+				},
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				// action
+				err := testCase.input.Validate()
+				// assertion
+				assert.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("Should fail on", func(t *testing.T) {
+		invalidCustomerID := 0
+		testCases := []struct {
+			name  string
+			input *CategoryRule
+		}{
+			{
+				name:  "Without Category",
+				input: &CategoryRule{DescriptionPattern: validPattern},
+			},
+			{
+				name:  "Long Category",
+				input: &CategoryRule{Category: strings.Repeat("E", 51)},
+			},
+			{
+				name:  "Invalid CustomerID",
+				input: &CategoryRule{CustomerID: &invalidCustomerID, Category: validCategory},
+			},
+			{
+				name:  "Invalid DescriptionPattern",
+				input: &CategoryRule{Category: validCategory, DescriptionPattern: "walmart("},
+			},
+			{
+				name:  "Negative MinAmount",
+				input: &CategoryRule{Category: validCategory, MinAmount: &negativeAmount},
+			},
+			{
+				name:  "MinAmount greater than MaxAmount",
+				input: &CategoryRule{Category: validCategory, MinAmount: &maxAmount, MaxAmount: &minAmount},
+			},
+			{
+				name:  "Invalid Type",
+				input: &CategoryRule{Category: validCategory, Type: 2},
+			},
+			{
+				name:  "Negative Priority",
+				input: &CategoryRule{Category: validCategory, Priority: -1},
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				// action
+				err := testCase.input.Validate()
+				// assertion
+				assert.Error(t, err)
+			})
+		}
+	})
+}
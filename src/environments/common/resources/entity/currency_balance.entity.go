@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"stori-service/src/libs/money"
+	"time"
+)
+
+/*
+CurrencyBalance has the totals of the movements of an import in one currency,
+with the available balance of the customer in that currency before and after it.
+The home amounts are in the home currency of the customer, the totals with the rate of each movement
+and the final available with the rate of the last date of the import. Overdraft is the first movement
+that left the balance below zero when the overdraft policy of the customer allows it
+*/
+type CurrencyBalance struct {
+	Currency         string       `json:"currency" groups:"client"`
+	IncomeRows       int          `json:"income_rows" groups:"client"`
+	OutcomeRows      int          `json:"outcome_rows" groups:"client"`
+	TotalIncome      money.Amount `json:"total_income" groups:"client"`
+	TotalOutcome     money.Amount `json:"total_outcome" groups:"client"`
+	InitialAvailable money.Amount `json:"initial_available" groups:"client"`
+	FinalAvailable   money.Amount `json:"final_available" groups:"client"`
+	HomeIncome       money.Amount `json:"home_income" groups:"client"`
+	HomeOutcome      money.Amount `json:"home_outcome" groups:"client"`
+	HomeAvailable    money.Amount `json:"home_available" groups:"client"`
+	Overdraft        *Overdraft   `json:"overdraft" groups:"client"`
+}
+
+/*
+Overdraft is a movement that left the balance of its currency below the overdraft allowed to the customer
+*/
+type Overdraft struct {
+	MovementID int          `json:"movement_id" groups:"client"`
+	ExternalID int          `json:"external_id" groups:"client"`
+	Source     string       `json:"source" groups:"client"`
+	Date       time.Time    `json:"date" groups:"client"`
+	Available  money.Amount `json:"available" groups:"client"`
+}
+
+/*
+CurrencyBalances are the balances of an import, one for each currency of its movements, it's saved as jsonb
+*/
+type CurrencyBalances []CurrencyBalance
+
+/*
+Find returns the balance of the currency, nil when the import doesn't have movements in it
+*/
+func (balances CurrencyBalances) Find(currency string) *CurrencyBalance {
+	for index := range balances {
+		if balances[index].Currency == currency {
+			return &balances[index]
+		}
+	}
+	return nil
+}
+
+/*
+Value serializes the balances to be saved in database
+*/
+func (balances CurrencyBalances) Value() (driver.Value, error) {
+	if balances == nil {
+		return nil, nil
+	}
+	return json.Marshal(balances)
+}
+
+/*
+Scan deserializes the balances from database
+*/
+func (balances *CurrencyBalances) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*balances = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, balances)
+	case string:
+		return json.Unmarshal([]byte(data), balances)
+	default:
+		return fmt.Errorf("unsupported type %T for CurrencyBalances", value)
+	}
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencyBalances(t *testing.T) {
+	balances := CurrencyBalances{
+		{Currency: "MXN", IncomeRows: 1, TotalIncome: 1050, InitialAvailable: 100, FinalAvailable: 1150, HomeIncome: 1050, HomeAvailable: 1150},
+		{Currency: "USD", OutcomeRows: 1, TotalOutcome: 25, InitialAvailable: 100, FinalAvailable: 75, HomeOutcome: 500, HomeAvailable: 1500},
+		{
+			Currency: "CAD", OutcomeRows: 1, TotalOutcome: 150, InitialAvailable: 100, FinalAvailable: -50, HomeOutcome: 3000, HomeAvailable: -1000,
+			Overdraft: &Overdraft{MovementID: 9, ExternalID: 3, Source: "default", Date: time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC), Available: -50},
+		},
+	}
+	balancesJSON := `[` +
+		`{"currency":"MXN","income_rows":1,"outcome_rows":0,"total_income":10.50,"total_outcome":0.00,"initial_available":1.00,"final_available":11.50,` +
+		`"home_income":10.50,"home_outcome":0.00,"home_available":11.50,"overdraft":null},` +
+		`{"currency":"USD","income_rows":0,"outcome_rows":1,"total_income":0.00,"total_outcome":0.25,"initial_available":1.00,"final_available":0.75,` +
+		`"home_income":0.00,"home_outcome":5.00,"home_available":15.00,"overdraft":null},` +
+		`{"currency":"CAD","income_rows":0,"outcome_rows":1,"total_income":0.00,"total_outcome":1.50,"initial_available":1.00,"final_available":-0.50,` +
+		`"home_income":0.00,"home_outcome":30.00,"home_available":-10.00,` +
+		`"overdraft":{"movement_id":9,"external_id":3,"source":"default","date":"2022-06-30T00:00:00Z","available":-0.50}}]`
+	t.Run("Find", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Finding the balance of a currency", func(t *testing.T) {
+				balance := balances.Find("USD")
+
+				assert.Equal(t, &balances[1], balance)
+			})
+			t.Run("Not finding a currency without movements", func(t *testing.T) {
+				assert.Nil(t, balances.Find("EUR"))
+			})
+		})
+	})
+	t.Run("Value", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Serializing the balances", func(t *testing.T) {
+				value, err := balances.Value()
+
+				assert.NoError(t, err)
+				assert.JSONEq(t, balancesJSON, string(value.([]byte)))
+			})
+			t.Run("Serializing empty balances", func(t *testing.T) {
+				var empty CurrencyBalances
+
+				value, err := empty.Value()
+
+				assert.NoError(t, err)
+				assert.Nil(t, value)
+			})
+		})
+	})
+	t.Run("Scan", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				name     string
+				input    interface{}
+				expected CurrencyBalances
+			}{
+				{
+					name:     "Scanning bytes",
+					input:    []byte(balancesJSON),
+					expected: balances,
+				},
+				{
+					name:     "Scanning a string",
+					input:    balancesJSON,
+					expected: balances,
+				},
+				{
+					name:     "Scanning null",
+					input:    nil,
+					expected: nil,
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					var got CurrencyBalances
+
+					err := got.Scan(tC.input)
+
+					assert.NoError(t, err)
+					assert.Equal(t, tC.expected, got)
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("Scanning an unsupported type", func(t *testing.T) {
+				var got CurrencyBalances
+
+				err := got.Scan(10)
+
+				assert.Error(t, err)
+			})
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"stori-service/src/libs/money"
 	"stori-service/src/libs/validator"
 	"time"
 
@@ -8,15 +9,20 @@ import (
 )
 
 /*
-	Customer model for Customer table
+	Customer model for Customer table, HomeCurrency is the ISO 4217 currency its movements are converted to.
+	OverdraftPolicy says what an import does when a movement leaves a balance below zero: reject the import,
+	allow it down to the CreditLimit, in the home currency, or allow it and flag the movement
 */
 type Customer struct {
-	CustomerID int            `json:"customer_id" gorm:"primaryKey" groups:"client"`
-	Name       string         `json:"name" validate:"required,min=3,max=100" groups:"client"`
-	Email      string         `json:"email" validate:"required,email,max=100" groups:"client"`
-	CreatedAt  time.Time      `json:"created_at" groups:""`
-	UpdatedAt  time.Time      `json:"updated_at" groups:""`
-	DeletedAt  gorm.DeletedAt `json:"deleted_at" groups:""`
+	CustomerID      int            `json:"customer_id" gorm:"primaryKey" groups:"client"`
+	Name            string         `json:"name" validate:"required,min=3,max=100" groups:"client"`
+	Email           string         `json:"email" validate:"required,email,max=100" groups:"client"`
+	HomeCurrency    string         `json:"home_currency" gorm:"default:MXN" validate:"required,len=3" groups:"client"`
+	OverdraftPolicy string         `json:"overdraft_policy" gorm:"default:allow_flag" validate:"required,oneof=reject credit_limit allow_flag" groups:"client"`
+	CreditLimit     money.Amount   `json:"credit_limit" validate:"gte=0" groups:"client"`
+	CreatedAt       time.Time      `json:"created_at" groups:""`
+	UpdatedAt       time.Time      `json:"updated_at" groups:""`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at" groups:""`
 }
 
 /*
@@ -11,13 +11,17 @@ func TestCustomer(t *testing.T) {
 	validId := 5
 	validName := "Pepe pepito"
 	validEmail := "pepepe@hotmail.com"
+	validHomeCurrency := "MXN"
+	validOverdraftPolicy := "allow_flag"
 	shortString := "El"
 	longString := strings.Repeat("E", 301)
 	t.Run("Should success on", func(t *testing.T) {
 		w := Customer{
-			CustomerID: 1,
-			Name:       validName,
-			Email:      validEmail,
+			CustomerID:      1,
+			Name:            validName,
+			Email:           validEmail,
+			HomeCurrency:    validHomeCurrency,
+			OverdraftPolicy: validOverdraftPolicy,
 		}
 		err := w.Validate()
 		assert.NoError(t, err)
@@ -26,27 +30,64 @@ func TestCustomer(t *testing.T) {
 	t.Run("Should fail on", func(t *testing.T) {
 		testCases := map[string]*Customer{
 			"Without name": {
-				CustomerID: validId,
-				Email:      validEmail,
+				CustomerID:   validId,
+				Email:        validEmail,
+				HomeCurrency: validHomeCurrency,
 			},
 			"Short name": {
-				CustomerID: validId,
-				Name:       shortString,
-				Email:      validEmail,
+				CustomerID:   validId,
+				Name:         shortString,
+				Email:        validEmail,
+				HomeCurrency: validHomeCurrency,
 			},
 			"Long name": {
-				CustomerID: validId,
-				Name:       longString,
-				Email:      validEmail,
+				CustomerID:   validId,
+				Name:         longString,
+				Email:        validEmail,
+				HomeCurrency: validHomeCurrency,
 			},
 			"Without email": {
-				CustomerID: validId,
-				Name:       validName,
+				CustomerID:   validId,
+				Name:         validName,
+				HomeCurrency: validHomeCurrency,
 			},
 			"Invalid email": {
+				CustomerID:   validId,
+				Name:         validName,
+				Email:        "invalid email",
+				HomeCurrency: validHomeCurrency,
+			},
+			"Without home currency": {
 				CustomerID: validId,
 				Name:       validName,
-				Email:      "invalid email",
+				Email:      validEmail,
+			},
+			"Invalid home currency": {
+				CustomerID:   validId,
+				Name:         validName,
+				Email:        validEmail,
+				HomeCurrency: "MX",
+			},
+			"Without overdraft policy": {
+				CustomerID:   validId,
+				Name:         validName,
+				Email:        validEmail,
+				HomeCurrency: validHomeCurrency,
+			},
+			"Invalid overdraft policy": {
+				CustomerID:      validId,
+				Name:            validName,
+				Email:           validEmail,
+				HomeCurrency:    validHomeCurrency,
+				OverdraftPolicy: "overdraft",
+			},
+			"Negative credit limit": {
+				CustomerID:      validId,
+				Name:            validName,
+				Email:           validEmail,
+				HomeCurrency:    validHomeCurrency,
+				OverdraftPolicy: "credit_limit",
+				CreditLimit:     -100,
 			},
 		}
 
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"stori-service/src/libs/money"
+	"stori-service/src/libs/validator"
+	"time"
+)
+
+/*
+ExchangeRate model for exchange_rate table, Rate is the amount of the QuoteCurrency that buys
+a unit of the BaseCurrency from its Date until the date of the next rate of the pair
+*/
+type ExchangeRate struct {
+	ExchangeRateID int        `json:"exchange_rate_id" gorm:"primaryKey" groups:"client"`
+	BaseCurrency   string     `json:"base_currency" groups:"client" validate:"required,len=3"`
+	QuoteCurrency  string     `json:"quote_currency" groups:"client" validate:"required,len=3,nefield=BaseCurrency"`
+	Date           time.Time  `json:"date" groups:"client" validate:"required"`
+	Rate           money.Rate `json:"rate" groups:"client" validate:"gt=0"`
+	CreatedAt      time.Time  `json:"created_at" groups:""`
+	UpdatedAt      time.Time  `json:"updated_at" groups:""`
+}
+
+/*
+Validate returns an error if entity doesn't pass any of its own validations
+*/
+func (exchangeRate *ExchangeRate) Validate() error {
+	if err := validator.ValidateStruct(exchangeRate); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"stori-service/src/libs/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeRate(t *testing.T) {
+	// fixture
+	validBase := "USD"
+	validQuote := "MXN"
+	validDate := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	validRate := money.Rate(1712500000)
+	t.Run("Should success on", func(t *testing.T) {
+		// fixture
+		exchangeRate := &ExchangeRate{
+			BaseCurrency:  validBase,
+			QuoteCurrency: validQuote,
+			Date:          validDate,
+			Rate:          validRate,
+		}
+		// action
+		err := exchangeRate.Validate()
+		// assertion
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			input *ExchangeRate
+		}{
+			{
+				name: "Without BaseCurrency",
+				input: &ExchangeRate{
+					QuoteCurrency: validQuote,
+					Date:          validDate,
+					Rate:          validRate,
+				},
+			},
+			{
+				name: "Invalid QuoteCurrency",
+				input: &ExchangeRate{
+					BaseCurrency:  validBase,
+					QuoteCurrency: "MX",
+					Date:          validDate,
+					Rate:          validRate,
+				},
+			},
+			{
+				name: "Same currencies",
+				input: &ExchangeRate{
+					BaseCurrency:  validBase,
+					QuoteCurrency: validBase,
+					Date:          validDate,
+					Rate:          validRate,
+				},
+			},
+			{
+				name: "Without Date",
+				input: &ExchangeRate{
+					BaseCurrency:  validBase,
+					QuoteCurrency: validQuote,
+					Rate:          validRate,
+				},
+			},
+			{
+				name: "Without Rate",
+				input: &ExchangeRate{
+					BaseCurrency:  validBase,
+					QuoteCurrency: validQuote,
+					Date:          validDate,
+				},
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				// action
+				err := testCase.input.Validate()
+				// assertion
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"stori-service/src/libs/validator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/*
+ImportBatch model for import_batch table, it records each file imported for a customer with its
+SHA-256 fingerprint, so importing the same content again returns this batch instead of importing it twice.
+Balances has the totals and the available balance before and after the import for each currency of its movements.
+A reverted batch keeps who reverted it and why, its movements are deleted
+*/
+type ImportBatch struct {
+	ImportBatchID int              `json:"import_batch_id" gorm:"primaryKey" groups:"client"`
+	CustomerID    int              `json:"customer_id" groups:"client" validate:"required,gte=1"`
+	FileHash      string           `json:"file_hash" groups:"client" validate:"required,len=64,hexadecimal"`
+	TotalRows     int              `json:"total_rows" groups:"client" validate:"gte=0"`
+	ImportedRows  int              `json:"imported_rows" groups:"client" validate:"gte=0"`
+	RejectedRows  int              `json:"rejected_rows" groups:"client" validate:"gte=0"`
+	Balances      CurrencyBalances `json:"balances" gorm:"type:jsonb" groups:"client"`
+	StartedAt     time.Time        `json:"started_at" groups:"client" validate:"required"`
+	FinishedAt    *time.Time       `json:"finished_at" groups:"client"`
+	RevertedAt    *time.Time       `json:"reverted_at" groups:"client"`
+	RevertedBy    *string          `json:"reverted_by" groups:"client" validate:"omitempty,max=255"`
+	RevertReason  *string          `json:"revert_reason" groups:"client" validate:"omitempty,max=1000"`
+	CreatedAt     time.Time        `json:"created_at" groups:"client"`
+	UpdatedAt     time.Time        `json:"updated_at" groups:""`
+	DeletedAt     gorm.DeletedAt   `json:"deleted_at" groups:""`
+}
+
+/*
+Validate returns an error if entity doesn't pass any of its own validations
+*/
+func (importBatch *ImportBatch) Validate() error {
+	if err := validator.ValidateStruct(importBatch); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBatch(t *testing.T) {
+	// fixture
+	validCustomerID := 1
+	validFileHash := strings.Repeat("ab", 32)
+	validStartedAt := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+	t.Run("Should success on", func(t *testing.T) {
+		// fixture
+		importBatch := &ImportBatch{
+			CustomerID:   validCustomerID,
+			FileHash:     validFileHash,
+			TotalRows:    10,
+			ImportedRows: 10,
+			StartedAt:    validStartedAt,
+		}
+		// action
+		err := importBatch.Validate()
+		// assertion
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			input *ImportBatch
+		}{
+			{
+				name: "Without CustomerID",
+				input: &ImportBatch{
+					FileHash:  validFileHash,
+					StartedAt: validStartedAt,
+				},
+			},
+			{
+				name: "Without FileHash",
+				input: &ImportBatch{
+					CustomerID: validCustomerID,
+					StartedAt:  validStartedAt,
+				},
+			},
+			{
+				name: "Invalid FileHash",
+				input: &ImportBatch{
+					CustomerID: validCustomerID,
+					FileHash:   strings.Repeat("z", 64),
+					StartedAt:  validStartedAt,
+				},
+			},
+			{
+				name: "Without StartedAt",
+				input: &ImportBatch{
+					CustomerID: validCustomerID,
+					FileHash:   validFileHash,
+				},
+			},
+			{
+				name: "Invalid TotalRows",
+				input: &ImportBatch{
+					CustomerID: validCustomerID,
+					FileHash:   validFileHash,
+					StartedAt:  validStartedAt,
+					TotalRows:  -1,
+				},
+			},
+		}
+
+		for _, tC := range testCases {
+			t.Run(tC.name, func(t *testing.T) {
+				// action
+				err := tC.input.Validate()
+				// assertion
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"stori-service/src/libs/validator"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/*
+ImportJob model for import_job table, it tracks the state of an asynchronous import,
+Action is the needs-action slug of the error when the import fails with one
+*/
+type ImportJob struct {
+	ImportJobID   int              `json:"import_job_id" gorm:"primaryKey" groups:"client"`
+	CustomerID    int              `json:"customer_id" groups:"client" validate:"required,gte=1"`
+	Status        string           `json:"status" groups:"client" validate:"required,oneof=pending running succeeded failed"`
+	TotalRows     int              `json:"total_rows" groups:"client" validate:"gte=0"`
+	ImportedRows  int              `json:"imported_rows" groups:"client" validate:"gte=0"`
+	RejectedRows  int              `json:"rejected_rows" groups:"client" validate:"gte=0"`
+	RejectedLines ImportLineErrors `json:"rejected_lines" gorm:"type:jsonb" groups:"client"`
+	ImportBatchID *int             `json:"import_batch_id" groups:"client"`
+	Error         *string          `json:"error" groups:"client"`
+	Action        *string          `json:"action" groups:"client"`
+	StartedAt     *time.Time       `json:"started_at" groups:"client"`
+	FinishedAt    *time.Time       `json:"finished_at" groups:"client"`
+	CreatedAt     time.Time        `json:"created_at" groups:"client"`
+	UpdatedAt     time.Time        `json:"updated_at" groups:""`
+	DeletedAt     gorm.DeletedAt   `json:"deleted_at" groups:""`
+}
+
+/*
+Validate returns an error if entity doesn't pass any of its own validations
+*/
+func (importJob *ImportJob) Validate() error {
+	if err := validator.ValidateStruct(importJob); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"stori-service/src/utils/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportJob(t *testing.T) {
+	// fixture
+	validCustomerID := 1
+	validStatus := constant.ImportJobPending
+	t.Run("Should success on", func(t *testing.T) {
+		// fixture
+		importJob := &ImportJob{
+			CustomerID:   validCustomerID,
+			Status:       validStatus,
+			TotalRows:    10,
+			ImportedRows: 10,
+		}
+		// action
+		err := importJob.Validate()
+		// assertion
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			input *ImportJob
+		}{
+			{
+				name: "Without CustomerID",
+				input: &ImportJob{
+					Status: validStatus,
+				},
+			},
+			{
+				name: "Without Status",
+				input: &ImportJob{
+					CustomerID: validCustomerID,
+				},
+			},
+			{
+				name: "Invalid Status",
+				input: &ImportJob{
+					CustomerID: validCustomerID,
+					Status:     "done",
+				},
+			},
+			{
+				name: "Invalid TotalRows",
+				input: &ImportJob{
+					CustomerID: validCustomerID,
+					Status:     validStatus,
+					TotalRows:  -1,
+				},
+			},
+			{
+				name: "Invalid RejectedRows",
+				input: &ImportJob{
+					CustomerID:   validCustomerID,
+					Status:       validStatus,
+					RejectedRows: -1,
+				},
+			},
+		}
+
+		for _, tC := range testCases {
+			t.Run(tC.name, func(t *testing.T) {
+				// action
+				err := tC.input.Validate()
+				// assertion
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+ImportLineError is a line of the file that couldn't be imported, with the column and the reason
+*/
+type ImportLineError struct {
+	Line   int    `json:"line" groups:"client"`
+	Column string `json:"column" groups:"client"`
+	Reason string `json:"reason" groups:"client"`
+}
+
+/*
+ImportLineErrors is the report of rejected lines, it's saved as jsonb
+*/
+type ImportLineErrors []ImportLineError
+
+/*
+Value serializes the report to be saved in database
+*/
+func (lines ImportLineErrors) Value() (driver.Value, error) {
+	if lines == nil {
+		return nil, nil
+	}
+	return json.Marshal(lines)
+}
+
+/*
+Scan deserializes the report from database
+*/
+func (lines *ImportLineErrors) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*lines = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, lines)
+	case string:
+		return json.Unmarshal([]byte(data), lines)
+	default:
+		return fmt.Errorf("unsupported type %T for ImportLineErrors", value)
+	}
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportLineErrors(t *testing.T) {
+	lines := ImportLineErrors{
+		{Line: 2, Column: "date", Reason: "Invalid date"},
+		{Line: 5, Column: "transaction", Reason: "Invalid amount"},
+	}
+	t.Run("Value", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			t.Run("Serializing a report", func(t *testing.T) {
+				value, err := lines.Value()
+
+				assert.NoError(t, err)
+				assert.JSONEq(t, `[{"line":2,"column":"date","reason":"Invalid date"},{"line":5,"column":"transaction","reason":"Invalid amount"}]`, string(value.([]byte)))
+			})
+			t.Run("Serializing an empty report", func(t *testing.T) {
+				var empty ImportLineErrors
+
+				value, err := empty.Value()
+
+				assert.NoError(t, err)
+				assert.Nil(t, value)
+			})
+		})
+	})
+	t.Run("Scan", func(t *testing.T) {
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				name     string
+				input    interface{}
+				expected ImportLineErrors
+			}{
+				{
+					name:     "Scanning bytes",
+					input:    []byte(`[{"line":2,"column":"date","reason":"Invalid date"},{"line":5,"column":"transaction","reason":"Invalid amount"}]`),
+					expected: lines,
+				},
+				{
+					name:     "Scanning a string",
+					input:    `[{"line":2,"column":"date","reason":"Invalid date"},{"line":5,"column":"transaction","reason":"Invalid amount"}]`,
+					expected: lines,
+				},
+				{
+					name:     "Scanning null",
+					input:    nil,
+					expected: nil,
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					var got ImportLineErrors
+
+					err := got.Scan(tC.input)
+
+					assert.NoError(t, err)
+					assert.Equal(t, tC.expected, got)
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			testCases := []struct {
+				name  string
+				input interface{}
+			}{
+				{
+					name:  "Scanning an unsupported type",
+					input: 10,
+				},
+				{
+					name:  "Scanning invalid json",
+					input: []byte(`{"line"`),
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					var got ImportLineErrors
+
+					err := got.Scan(tC.input)
+
+					assert.Error(t, err)
+				})
+			}
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/libs/validator"
+	"time"
+)
+
+/*
+JournalEntry model for journal_entry table, a set of postings of the same currency that balances to zero.
+Each customer movement has an entry that credits the customer account and debits the clearing account
+on incomes and the other way around on outcomes, so the credit balance of the customer account
+is the available of the customer. Entries are never changed, a reverted movement gets a new entry
+with the opposite postings that points to the original one in ReversalOfID
+*/
+type JournalEntry struct {
+	JournalEntryID int       `json:"journal_entry_id" gorm:"primaryKey" groups:"client"`
+	MovementID     *int      `json:"movement_id" groups:"client"`
+	ImportBatchID  *int      `json:"import_batch_id" groups:"client"`
+	ReversalOfID   *int      `json:"reversal_of_id" groups:"client"`
+	Date           time.Time `json:"date" groups:"client" validate:"required"`
+	Description    string    `json:"description" groups:"client" validate:"max=255"`
+	Postings       []Posting `json:"postings" groups:"client" validate:"min=2,dive"`
+	CreatedAt      time.Time `json:"created_at" groups:""`
+}
+
+/*
+Validate returns an error if entity doesn't pass any of its own validations or its postings don't balance to zero
+*/
+func (journalEntry *JournalEntry) Validate() error {
+	if err := validator.ValidateStruct(journalEntry); err != nil {
+		return err
+	}
+	var total money.Amount
+	for _, posting := range journalEntry.Postings {
+		total += posting.Amount
+	}
+	if total != 0 {
+		return errors.ErrUnbalancedEntry
+	}
+	return nil
+}
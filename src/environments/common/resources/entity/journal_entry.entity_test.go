@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"stori-service/src/libs/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalEntry(t *testing.T) {
+	// fixture
+	validDate := time.Date(2022, time.June, 30, 0, 0, 0, 0, time.UTC)
+	t.Run("Should success on", func(t *testing.T) {
+		// fixture
+		journalEntry := &JournalEntry{
+			Date: validDate,
+			Postings: []Posting{
+				{AccountID: 1, Amount: 1000},
+				{AccountID: 2, Amount: -1000},
+			},
+		}
+		// action
+		err := journalEntry.Validate()
+		// assertion
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			input *JournalEntry
+		}{
+			{
+				name: "Without Date",
+				input: &JournalEntry{
+					Postings: []Posting{{AccountID: 1, Amount: 1000}, {AccountID: 2, Amount: -1000}},
+				},
+			},
+			{
+				name: "Only one posting",
+				input: &JournalEntry{
+					Date:     validDate,
+					Postings: []Posting{{AccountID: 1, Amount: 0}},
+				},
+			},
+			{
+				name: "Posting without account",
+				input: &JournalEntry{
+					Date:     validDate,
+					Postings: []Posting{{Amount: 1000}, {AccountID: 2, Amount: -1000}},
+				},
+			},
+			{
+				name: "Posting without amount",
+				input: &JournalEntry{
+					Date:     validDate,
+					Postings: []Posting{{AccountID: 1}, {AccountID: 2}},
+				},
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				// action
+				err := testCase.input.Validate()
+				// assertion
+				assert.Error(t, err)
+			})
+		}
+		t.Run("Unbalanced postings", func(t *testing.T) {
+			// fixture
+			journalEntry := &JournalEntry{
+				Date:     validDate,
+				Postings: []Posting{{AccountID: 1, Amount: 1000}, {AccountID: 2, Amount: -999}},
+			}
+			// action
+			err := journalEntry.Validate()
+			// assertion
+			assert.ErrorIs(t, err, errors.ErrUnbalancedEntry)
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"stori-service/src/libs/money"
 	"stori-service/src/libs/validator"
 	"time"
 
@@ -8,18 +9,35 @@ import (
 )
 
 /*
-Movement model for movement table
+Movement model for movement table, ExternalID is the ID of the transaction in the bank file and ExternalRef
+the reference of the bank it comes from, unique for the customer and the source of the file. Quantity and Available are exact amounts in cents
+of the ISO 4217 Currency, Available is the balance of the customer in that currency, it's below zero
+when the overdraft policy of the customer allows it.
+HomeQuantity is the Quantity in the home currency of the customer with the ExchangeRate effective on the Date.
+Description, Merchant and Category are optional texts of the transaction, empty when the file doesn't have them,
+CategoryRuleID is the rule that set the Category when the file didn't have it
 */
 type Movement struct {
-	MovementID int            `json:"movement_id" gorm:"primaryKey" groups:""`
-	CustomerID int            `json:"customer_id" groups:"" validate:"required,gte=1"`
-	Quantity   float64        `json:"quantity" groups:"client" validate:"required,gt=0"`
-	Available  float64        `json:"available" groups:"client" validate:"required,gte=0"`
-	Type       int            `json:"type" groups:"client" validate:"required,eq=1|eq=-1"`
-	Date       time.Time      `json:"date" groups:"client" validate:"required"`
-	CreatedAt  time.Time      `json:"created_at" groups:""`
-	UpdatedAt  time.Time      `json:"updated_at" groups:""`
-	DeletedAt  gorm.DeletedAt `json:"deleted_at" groups:""`
+	MovementID     int            `json:"movement_id" gorm:"primaryKey" groups:"client"`
+	ExternalID     int            `json:"external_id" groups:"client"`
+	ExternalRef    string         `json:"external_ref" groups:"client" validate:"max=255"`
+	Source         string         `json:"source" groups:"client" validate:"required,max=50"`
+	CustomerID     int            `json:"customer_id" groups:"" validate:"required,gte=1"`
+	Quantity       money.Amount   `json:"quantity" groups:"client" validate:"required,gt=0"`
+	Available      money.Amount   `json:"available" groups:"client"`
+	Currency       string         `json:"currency" groups:"client" validate:"required,len=3"`
+	HomeQuantity   money.Amount   `json:"home_quantity" groups:"client" validate:"gte=0"`
+	ExchangeRate   money.Rate     `json:"exchange_rate" groups:"client" validate:"gte=0"`
+	Type           int            `json:"type" groups:"client" validate:"required,eq=1|eq=-1"`
+	Date           time.Time      `json:"date" groups:"client" validate:"required"`
+	Description    string         `json:"description" groups:"client" validate:"max=255"`
+	Merchant       string         `json:"merchant" groups:"client" validate:"max=100"`
+	Category       string         `json:"category" groups:"client" validate:"max=50"`
+	CategoryRuleID *int           `json:"category_rule_id" groups:"client"`
+	ImportBatchID  *int           `json:"import_batch_id" groups:"client"`
+	CreatedAt      time.Time      `json:"created_at" groups:""`
+	UpdatedAt      time.Time      `json:"updated_at" groups:""`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at" groups:""`
 }
 
 /*
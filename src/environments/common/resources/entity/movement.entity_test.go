@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"stori-service/src/libs/money"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,16 +11,20 @@ import (
 
 func TestMovement(t *testing.T) {
 	// fixture
-	validMovementID := 1
+	validExternalID := 1
+	validSource := "default"
+	validCurrency := "MXN"
 	validCustomerID := 1
-	validQty := 10.0
+	validQty := money.Amount(1000)
 	validDate := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
-	validAvailable := 20.00
+	validAvailable := money.Amount(2000)
 	validType := 1
 	t.Run("Should success on", func(t *testing.T) {
 		// fixture
 		movement := &Movement{
-			MovementID: validMovementID,
+			ExternalID: validExternalID,
+			Source:     validSource,
+			Currency:   validCurrency,
 			CustomerID: validCustomerID,
 			Quantity:   validQty,
 			Available:  validAvailable,
@@ -30,6 +36,43 @@ func TestMovement(t *testing.T) {
 		// assertion
 		assert.NoError(t, err)
 	})
+	t.Run("Should success on texts", func(t *testing.T) {
+		// fixture
+		movement := &Movement{
+			ExternalID:  validExternalID,
+			Source:      validSource,
+			Currency:    validCurrency,
+			CustomerID:  validCustomerID,
+			Quantity:    validQty,
+			Available:   validAvailable,
+			Type:        validType,
+			Date:        validDate,
+			Description: "Weekly shopping",
+			Merchant:    "Super Market",
+			Category:    "Groceries",
+		}
+		// action
+		err := movement.Validate()
+		// assertion
+		assert.NoError(t, err)
+	})
+	t.Run("Should success on negative available", func(t *testing.T) {
+		// fixture
+		movement := &Movement{
+			ExternalID: validExternalID,
+			Source:     validSource,
+			Currency:   validCurrency,
+			CustomerID: validCustomerID,
+			Quantity:   validQty,
+			Available:  -validAvailable,
+			Type:       validType,
+			Date:       validDate,
+		}
+		// action
+		err := movement.Validate()
+		// assertion
+		assert.NoError(t, err)
+	})
 
 	t.Run("Should fail on", func(t *testing.T) {
 		testCases := []struct {
@@ -39,7 +82,9 @@ func TestMovement(t *testing.T) {
 			{
 				name: "Without CustomerID",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
 					Quantity:   validQty,
 					Available:  validAvailable,
 					Type:       validType,
@@ -49,7 +94,9 @@ func TestMovement(t *testing.T) {
 			{
 				name: "Invalid CustomerID",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
 					CustomerID: 0,
 					Quantity:   validQty,
 					Available:  validAvailable,
@@ -58,44 +105,62 @@ func TestMovement(t *testing.T) {
 				},
 			},
 			{
-				name: "Without Quantity",
+				name: "Without Source",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
 					CustomerID: validCustomerID,
+					Quantity:   validQty,
 					Available:  validAvailable,
 					Type:       validType,
 					Date:       validDate,
 				},
 			},
 			{
-				name: "Invalid Quantity",
+				name: "Without Currency",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
 					CustomerID: validCustomerID,
-					Quantity:   0,
+					Quantity:   validQty,
 					Available:  validAvailable,
 					Type:       validType,
 					Date:       validDate,
 				},
 			},
 			{
-				name: "Without Available",
+				name: "Invalid Currency",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   "MX",
 					CustomerID: validCustomerID,
 					Quantity:   validQty,
+					Available:  validAvailable,
 					Type:       validType,
 					Date:       validDate,
 				},
 			},
-
 			{
-				name: "Invalid Available",
+				name: "Without Quantity",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
 					CustomerID: validCustomerID,
-					Quantity:   validQty,
-					Available:  -1,
+					Available:  validAvailable,
+					Type:       validType,
+					Date:       validDate,
+				},
+			},
+			{
+				name: "Invalid Quantity",
+				input: &Movement{
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
+					CustomerID: validCustomerID,
+					Quantity:   0,
+					Available:  validAvailable,
 					Type:       validType,
 					Date:       validDate,
 				},
@@ -103,7 +168,9 @@ func TestMovement(t *testing.T) {
 			{
 				name: "Without Type",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
 					CustomerID: validCustomerID,
 					Quantity:   validQty,
 					Available:  validAvailable,
@@ -113,7 +180,9 @@ func TestMovement(t *testing.T) {
 			{
 				name: "Invalid Type",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
 					CustomerID: validCustomerID,
 					Quantity:   validQty,
 					Available:  validAvailable,
@@ -124,13 +193,57 @@ func TestMovement(t *testing.T) {
 			{
 				name: "Without date",
 				input: &Movement{
-					MovementID: validMovementID,
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
 					CustomerID: validCustomerID,
 					Quantity:   validQty,
 					Available:  validAvailable,
 					Type:       0,
 				},
 			},
+			{
+				name: "Long Description",
+				input: &Movement{
+					ExternalID:  validExternalID,
+					Source:      validSource,
+					Currency:    validCurrency,
+					CustomerID:  validCustomerID,
+					Quantity:    validQty,
+					Available:   validAvailable,
+					Type:        validType,
+					Date:        validDate,
+					Description: strings.Repeat("D", 256),
+				},
+			},
+			{
+				name: "Long Merchant",
+				input: &Movement{
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
+					CustomerID: validCustomerID,
+					Quantity:   validQty,
+					Available:  validAvailable,
+					Type:       validType,
+					Date:       validDate,
+					Merchant:   strings.Repeat("M", 101),
+				},
+			},
+			{
+				name: "Long Category",
+				input: &Movement{
+					ExternalID: validExternalID,
+					Source:     validSource,
+					Currency:   validCurrency,
+					CustomerID: validCustomerID,
+					Quantity:   validQty,
+					Available:  validAvailable,
+					Type:       validType,
+					Date:       validDate,
+					Category:   strings.Repeat("C", 51),
+				},
+			},
 		}
 
 		for _, tC := range testCases {
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"stori-service/src/libs/money"
+	"time"
+)
+
+/*
+Posting model for posting table, the amount of a journal entry that goes to an account, in the currency
+of the account. Amount is positive for debits and negative for credits
+*/
+type Posting struct {
+	PostingID      int          `json:"posting_id" gorm:"primaryKey" groups:"client"`
+	JournalEntryID int          `json:"journal_entry_id" groups:"client"`
+	AccountID      int          `json:"account_id" groups:"client" validate:"required,gte=1"`
+	Amount         money.Amount `json:"amount" groups:"client" validate:"ne=0"`
+	CreatedAt      time.Time    `json:"created_at" groups:""`
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "io"
+
+/*
+IFileSource opens statement files from wherever they are stored, files can be read
+more than once (to fingerprint them before the import) seeking back to the start
+*/
+type IFileSource interface {
+	Open(name string) (io.ReadSeekCloser, error)
+}
@@ -0,0 +1,83 @@
+package categorizer
+
+import (
+	"regexp"
+	"sort"
+	"stori-service/src/environments/common/resources/entity"
+)
+
+/*
+rule is a category rule with its compiled pattern
+*/
+type rule struct {
+	categoryRule entity.CategoryRule
+	pattern      *regexp.Regexp
+}
+
+/*
+Categorizer finds the category rule that matches a movement
+*/
+type Categorizer struct {
+	rules []rule
+}
+
+/*
+New receives the rules of a customer and the global ones and returns a Categorizer that tries the rules
+of the customer first and then the global ones, each group by priority and creation.
+The patterns are case insensitive, it fails when a pattern isn't a valid regular expression
+*/
+func New(categoryRules []entity.CategoryRule) (*Categorizer, error) {
+	rules := make([]rule, 0, len(categoryRules))
+	for _, categoryRule := range categoryRules {
+		var pattern *regexp.Regexp
+		if categoryRule.DescriptionPattern != "" {
+			compiled, err := regexp.Compile("(?i)" + categoryRule.DescriptionPattern)
+			if err != nil {
+				return nil, err
+			}
+			pattern = compiled
+		}
+		rules = append(rules, rule{categoryRule: categoryRule, pattern: pattern})
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		first, second := rules[i].categoryRule, rules[j].categoryRule
+		if (first.CustomerID == nil) != (second.CustomerID == nil) {
+			return first.CustomerID != nil
+		}
+		if first.Priority != second.Priority {
+			return first.Priority < second.Priority
+		}
+		return first.CategoryRuleID < second.CategoryRuleID
+	})
+	return &Categorizer{rules}, nil
+}
+
+/*
+Match returns the first rule that matches the description, the home quantity and the type of the movement,
+or nil when no rule matches
+*/
+func (c *Categorizer) Match(movement *entity.Movement) *entity.CategoryRule {
+	for index := range c.rules {
+		rule := &c.rules[index]
+		if rule.matches(movement) {
+			return &rule.categoryRule
+		}
+	}
+	return nil
+}
+
+/*
+matches returns true when the movement meets all the conditions of the rule
+*/
+func (r *rule) matches(movement *entity.Movement) bool {
+	if r.categoryRule.Type != 0 && r.categoryRule.Type != movement.Type {
+		return false
+	}
+	if r.categoryRule.MinAmount != nil && movement.HomeQuantity < *r.categoryRule.MinAmount {
+		return false
+	}
+	if r.categoryRule.MaxAmount != nil && movement.HomeQuantity > *r.categoryRule.MaxAmount {
+		return false
+	}
+	return r.pattern == nil || r.pattern.MatchString(movement.Description)
+}
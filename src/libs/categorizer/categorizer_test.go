@@ -0,0 +1,104 @@
+package categorizer
+
+import (
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorizer(t *testing.T) {
+	customerID := 1
+	minAmount := money.Amount(10000)
+	maxAmount := money.Amount(50000)
+	rules := []entity.CategoryRule{
+		{CategoryRuleID: 1, Category: "Groceries", DescriptionPattern: "walmart|soriana", Type: -1},
+		{CategoryRuleID: 2, Category: "Transport", DescriptionPattern: "^uber", Priority: 1},
+		{CategoryRuleID: 3, CustomerID: &customerID, Category: "Salary", Type: 1, MinAmount: &minAmount},
+		{CategoryRuleID: 4, CustomerID: &customerID, Category: "Market", DescriptionPattern: "soriana", Priority: 1},
+		{CategoryRuleID: 5, Category: "Rides", DescriptionPattern: "uber", Priority: 1},
+		{CategoryRuleID: 6, Category: "Small expenses", Type: -1, MaxAmount: &maxAmount, Priority: 2},
+	}
+	t.Run("Match", func(t *testing.T) {
+		sCategorizer, err := New(rules)
+		assert.NoError(t, err)
+		t.Run("Should success on", func(t *testing.T) {
+			testCases := []struct {
+				Name       string
+				Movement   entity.Movement
+				ExpectedID int
+			}{
+				{
+					Name:       "Matching the description without case",
+					Movement:   entity.Movement{Description: "WALMART SUPERCENTER", Type: -1, HomeQuantity: 80000},
+					ExpectedID: 1,
+				},
+				{
+					Name:       "Preferring the rules of the customer",
+					Movement:   entity.Movement{Description: "Soriana Centro", Type: -1, HomeQuantity: 80000},
+					ExpectedID: 4,
+				},
+				{
+					Name:       "Matching the amount range",
+					Movement:   entity.Movement{Description: "Payroll", Type: 1, HomeQuantity: 10000},
+					ExpectedID: 3,
+				},
+				{
+					Name:       "Preferring the lowest priority",
+					Movement:   entity.Movement{Description: "Uber trip", Type: -1, HomeQuantity: 80000},
+					ExpectedID: 2,
+				},
+				{
+					Name:       "Preferring the oldest rule with the same priority",
+					Movement:   entity.Movement{Description: "Trip with uber", Type: -1, HomeQuantity: 80000},
+					ExpectedID: 5,
+				},
+				{
+					Name:       "Matching a rule without pattern",
+					Movement:   entity.Movement{Description: "Coffee", Type: -1, HomeQuantity: 5000},
+					ExpectedID: 6,
+				},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					categoryRule := sCategorizer.Match(&testCase.Movement)
+
+					assert.NotNil(t, categoryRule)
+					assert.Equal(t, testCase.ExpectedID, categoryRule.CategoryRuleID)
+				})
+			}
+		})
+		t.Run("Should fail on", func(t *testing.T) {
+			testCases := []struct {
+				Name     string
+				Movement entity.Movement
+			}{
+				{Name: "Another type", Movement: entity.Movement{Description: "Walmart refund", Type: 1, HomeQuantity: 5000}},
+				{Name: "An amount below the range", Movement: entity.Movement{Description: "Payroll", Type: 1, HomeQuantity: 9999}},
+				{Name: "An amount above the range", Movement: entity.Movement{Description: "Rent", Type: -1, HomeQuantity: 50001}},
+			}
+			for _, testCase := range testCases {
+				t.Run(testCase.Name, func(t *testing.T) {
+					assert.Nil(t, sCategorizer.Match(&testCase.Movement))
+				})
+			}
+			t.Run("Not having rules", func(t *testing.T) {
+				sCategorizer, err := New(nil)
+
+				assert.NoError(t, err)
+				assert.Nil(t, sCategorizer.Match(&entity.Movement{Description: "Walmart", Type: -1}))
+			})
+		})
+	})
+	t.Run("New", func(t *testing.T) {
+		t.Run("Should fail on", func(t *testing.T) {
+			t.Run("An invalid pattern", func(t *testing.T) {
+				sCategorizer, err := New([]entity.CategoryRule{{CategoryRuleID: 1, Category: "Groceries", DescriptionPattern: "walmart("}})
+
+				assert.Error(t, err)
+				assert.Nil(t, sCategorizer)
+			})
+		})
+	})
+}
@@ -3,29 +3,206 @@ package scopes
 import (
 	"stori-service/src/environments/common/resources/entity"
 	"stori-service/src/libs/dto"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-//StocksByCustomerID scope function to get stocks by user id
-func StocksByCustomerID(customerid int, pagination *dto.Pagination) func(db *gorm.DB) *gorm.DB {
+//MovementByCustomerID scope function to get stock movement user id
+func MovementByCustomerID(customerid int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		return db.Model(&entity.Movement{}).
-			Where(&entity.Movement{CustomerID: customerid}).
-			Where("product.enabled = true AND product.deleted_at IS NULL").
-			Select("DISTINCT ON (movement.product_id) product.product_id, movement.available as stock, product.name, product.slug, product.description").
-			Joins("JOIN product ON product.product_id = movement.product_id").
-			Order("movement.product_id ASC, movement_id DESC").
-			Offset(pagination.Offset()).
-			Limit(pagination.PageSize)
+		return db.
+			Model(&entity.Movement{}).
+			Where(&entity.Movement{CustomerID: customerid})
 	}
 }
 
-//MovementByCustomerID scope function to get stock movement user id
-func MovementByCustomerID(customerid int) func(db *gorm.DB) *gorm.DB {
+//MovementsByExternalRefs scope function to get the movements of a customer and source with the given external references
+func MovementsByExternalRefs(customerID int, source string, externalRefs []string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.
 			Model(&entity.Movement{}).
-			Where(&entity.Movement{CustomerID: customerid})
+			Where(&entity.Movement{CustomerID: customerID, Source: source}).
+			Where("external_ref IN ?", externalRefs)
+	}
+}
+
+//MovementsNotDeleted scope function to skip the soft deleted movements in the queries that aren't filtered by the model, like Unscoped or Table ones
+func MovementsNotDeleted() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("deleted_at IS NULL")
+	}
+}
+
+//MovementsByCurrency scope function to get the movements in the currency
+func MovementsByCurrency(currency string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("currency = ?", currency)
+	}
+}
+
+//MovementsByImportBatchID scope function to get the movements created by the import batch
+func MovementsByImportBatchID(importBatchID int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Model(&entity.Movement{}).
+			Where("import_batch_id = ?", importBatchID)
+	}
+}
+
+//MovementsBeforeDate scope function to get the movements with a date before the given one
+func MovementsBeforeDate(date time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("date < ?", date)
+	}
+}
+
+//MovementsUntilDate scope function to get the movements with a date at or before the given one
+func MovementsUntilDate(date time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("date <= ?", date)
+	}
+}
+
+//MovementsOverdrawn scope function to get the movements that leave the available of their currency below zero
+func MovementsOverdrawn() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("available < 0")
+	}
+}
+
+//MovementsAfterID scope function to get the movements with an ID greater than the given one
+func MovementsAfterID(movementID int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("movement_id > ?", movementID)
+	}
+}
+
+//MovementsCategorizable scope function to get the movements whose category was set by a rule or is empty, the categories of the files are kept
+func MovementsCategorizable() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("category_rule_id IS NOT NULL OR category = ''")
+	}
+}
+
+//MovementsByDateRange scope function to get the movements from the day from until the day to, both included, a nil day doesn't limit the range
+func MovementsByDateRange(from, to *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if from != nil {
+			db = db.Where("date >= ?", *from)
+		}
+		if to != nil {
+			db = db.Where("date < ?", to.AddDate(0, 0, 1)) // the whole last day
+		}
+		return db
+	}
+}
+
+//MovementsByType scope function to get the incomes or the outcomes, zero gets both
+func MovementsByType(movementType int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if movementType == 0 {
+			return db
+		}
+		return db.Where("type = ?", movementType)
+	}
+}
+
+//MovementsByAmountRange scope function to get the movements with an amount in the home currency between min and max, both included, a nil one doesn't limit the range
+func MovementsByAmountRange(min, max *money.Amount) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if min != nil {
+			db = db.Where("home_quantity >= ?", *min)
+		}
+		if max != nil {
+			db = db.Where("home_quantity <= ?", *max)
+		}
+		return db
+	}
+}
+
+//MovementsByFilter scope function to get the movements that pass the date range, type and amount range of the filter
+func MovementsByFilter(filter dto.MovementFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(
+			MovementsByDateRange(filter.From, filter.To),
+			MovementsByType(filter.Type),
+			MovementsByAmountRange(filter.MinAmount, filter.MaxAmount),
+		)
+	}
+}
+
+//MovementsOrderBy scope function to order the movements by one of the movement sorts, the newest first when it's unknown
+func MovementsOrderBy(sort string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		order, ok := constant.MovementSorts[sort]
+		if !ok {
+			order = constant.MovementSorts[constant.MovementSortDateDesc]
+		}
+		return db.Order(order)
+	}
+}
+
+//MovementsAfter scope function to get the movements after a position in the order of date and movement_id
+func MovementsAfter(date time.Time, movementID int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(date, movement_id) > (?, ?)", date, movementID)
+	}
+}
+
+//MovementsBefore scope function to get the movements before a position in the order of date and movement_id
+func MovementsBefore(date time.Time, movementID int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(date, movement_id) < (?, ?)", date, movementID)
+	}
+}
+
+//MovementsByDate scope function to order the movements by date and movement_id, the newest first when descending
+func MovementsByDate(descending bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if descending {
+			return db.Order("date DESC, movement_id DESC")
+		}
+		return db.Order("date ASC, movement_id ASC")
+	}
+}
+
+//MovementsOrderByCurrency scope function to order the movements by currency, the orders of the next scopes break the ties
+func MovementsOrderByCurrency() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order("currency ASC")
+	}
+}
+
+//MovementsOrderByID scope function to order the movements by movement_id, the oldest inserted first
+func MovementsOrderByID() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order("movement_id ASC")
+	}
+}
+
+/*
+MovementKeyset scope function to get the page of movements of the cursor pagination in the order of date and movement_id,
+the newest first when descending. The page after the cursor is read in the order of the listing and the one before it,
+for backward cursors, in the opposite order, so the caller reverses it. It gets one more movement than the page size
+to know if there are more movements after the page
+*/
+func MovementKeyset(pagination *dto.CursorPagination, descending bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		cursor := pagination.Cursor
+		readDescending := descending
+		if cursor != nil && cursor.Backward {
+			readDescending = !descending
+		}
+		if cursor != nil {
+			if readDescending {
+				db = db.Scopes(MovementsBefore(cursor.Date, cursor.MovementID))
+			} else {
+				db = db.Scopes(MovementsAfter(cursor.Date, cursor.MovementID))
+			}
+		}
+		return db.Scopes(MovementsByDate(readDescending)).Limit(pagination.PageSize + 1)
 	}
 }
@@ -2,10 +2,14 @@ package scopes
 
 import (
 	"os"
+	"stori-service/src/environments/common/resources/entity"
 	"stori-service/src/libs/database"
 	"stori-service/src/libs/dto"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -18,6 +22,43 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+/*
+addFixtures adds a customer with an income and an outcome on each of the first days of July, the outcome of July 2nd
+is deleted. The external ID of each movement is its day and type, the amount in the home currency is its external ID
+*/
+func addFixtures(tx *gorm.DB) {
+	tx.Create(&entity.Customer{CustomerID: 90, Name: "Scopes", Email: "scopes@hotmail.com"})
+	movements := []entity.Movement{}
+	for day := 1; day <= 3; day++ {
+		for _, movementType := range []int{constant.IncomeType, constant.OutcomeType} {
+			externalID := day*10 + (1-movementType)/2 // 11 for the income of the 1st, 12 for its outcome
+			movements = append(movements, entity.Movement{
+				ExternalID:   externalID,
+				ExternalRef:  strconv.Itoa(externalID),
+				Source:       "default",
+				CustomerID:   90,
+				Quantity:     money.Amount(externalID),
+				HomeQuantity: money.Amount(externalID),
+				Currency:     "MXN",
+				Type:         movementType,
+				Date:         time.Date(2022, time.July, day, 12, 0, 0, 0, time.UTC),
+			})
+		}
+	}
+	tx.Create(&movements)
+	tx.Where("customer_id = 90 AND external_id = 22").Delete(&entity.Movement{})
+}
+
+/*
+externalIDs returns the external IDs of the movements of the fixtures customer that the query finds, in its order
+*/
+func externalIDs(t *testing.T, db *gorm.DB) []int {
+	ids := []int{}
+	err := db.Scopes(MovementByCustomerID(90)).Pluck("external_id", &ids).Error
+	assert.NoError(t, err)
+	return ids
+}
+
 func TestMovementScope(t *testing.T) {
 	db := database.GetStoriGormConnection().Session(&gorm.Session{DryRun: true})
 	t.Run("MovementByCustomerID", func(t *testing.T) {
@@ -30,20 +71,199 @@ func TestMovementScope(t *testing.T) {
 		//Data Assertion: inteporlated values
 		assert.Equal(t, 1, subQuery.Vars[0])
 	})
-	t.Run("StocksByCustomerID", func(t *testing.T) {
-		pagination := dto.NewPagination(2, 20, 0)
-		subQuery := db.Scopes(StocksByCustomerID(1, pagination)).Find(nil).Statement
+	t.Run("MovementsByFilter", func(t *testing.T) {
+		from := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2022, time.July, 31, 0, 0, 0, 0, time.UTC)
+		minAmount := money.Amount(100)
+		filter := dto.MovementFilter{From: &from, To: &to, Type: constant.OutcomeType, MinAmount: &minAmount}
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsByFilter(filter)).Find(nil).Statement
 
 		//Data Assertion: query
-		assert.Contains(t, subQuery.SQL.String(), `SELECT DISTINCT ON (movement.product_id)`)
-		assert.Contains(t, subQuery.SQL.String(), `product.product_id, movement.available as stock, product.name, product.slug, product.description`)
-		assert.Contains(t, subQuery.SQL.String(), `JOIN product ON product.product_id = movement.product_id`)
-		assert.Contains(t, subQuery.SQL.String(), `ORDER BY movement.product_id ASC, movement_id DESC`)
-		assert.Contains(t, subQuery.SQL.String(), "\"customer_id\" = $1")
-		assert.Contains(t, subQuery.SQL.String(), "enabled = true")
-		assert.Contains(t, subQuery.SQL.String(), "LIMIT "+strconv.Itoa(pagination.PageSize))
-		assert.Contains(t, subQuery.SQL.String(), "OFFSET "+strconv.Itoa(pagination.Offset()))
+		assert.Contains(t, subQuery.SQL.String(), "date >= $2 AND date < $3 AND type = $4 AND home_quantity >= $5")
+		assert.NotContains(t, subQuery.SQL.String(), "home_quantity <=")
+
 		//Data Assertion: inteporlated values
-		assert.Equal(t, 1, subQuery.Vars[0])
+		assert.Equal(t, from, subQuery.Vars[1])
+		assert.Equal(t, time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC), subQuery.Vars[2])
+		assert.Equal(t, constant.OutcomeType, subQuery.Vars[3])
+		assert.Equal(t, minAmount, subQuery.Vars[4])
+	})
+	t.Run("MovementsOrderBy", func(t *testing.T) {
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsOrderBy(constant.MovementSortAmountDesc)).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), "ORDER BY home_quantity DESC, movement_id DESC")
+	})
+	t.Run("MovementsAfter", func(t *testing.T) {
+		date := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsAfter(date, 15)).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), "(date, movement_id) > ($2, $3)")
+
+		//Data Assertion: inteporlated values
+		assert.Equal(t, date, subQuery.Vars[1])
+		assert.Equal(t, 15, subQuery.Vars[2])
+	})
+	t.Run("MovementsBefore", func(t *testing.T) {
+		date := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsBefore(date, 15)).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), "(date, movement_id) < ($2, $3)")
 	})
+	t.Run("MovementsByCurrency", func(t *testing.T) {
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsByCurrency("USD")).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), "currency = $2")
+
+		//Data Assertion: inteporlated values
+		assert.Equal(t, "USD", subQuery.Vars[1])
+	})
+	t.Run("MovementsByImportBatchID", func(t *testing.T) {
+		subQuery := db.Scopes(MovementsByImportBatchID(7), MovementsOverdrawn()).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), `SELECT * FROM "movement"`)
+		assert.Contains(t, subQuery.SQL.String(), "import_batch_id = $1")
+		assert.Contains(t, subQuery.SQL.String(), "available < 0")
+
+		//Data Assertion: inteporlated values
+		assert.Equal(t, 7, subQuery.Vars[0])
+	})
+	t.Run("MovementsCategorizable", func(t *testing.T) {
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsAfterID(15), MovementsCategorizable()).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), "movement_id > $2")
+		assert.Contains(t, subQuery.SQL.String(), "(category_rule_id IS NOT NULL OR category = '')")
+
+		//Data Assertion: inteporlated values
+		assert.Equal(t, 15, subQuery.Vars[1])
+	})
+	t.Run("MovementsOrderByCurrency", func(t *testing.T) {
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsOrderByCurrency(), MovementsByDate(true)).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), "ORDER BY currency ASC,date DESC, movement_id DESC")
+	})
+	t.Run("MovementsOrderByID", func(t *testing.T) {
+		subQuery := db.Scopes(MovementByCustomerID(1), MovementsOrderByID()).Find(nil).Statement
+
+		//Data Assertion: query
+		assert.Contains(t, subQuery.SQL.String(), "ORDER BY movement_id ASC")
+	})
+	t.Run("MovementKeyset", func(t *testing.T) {
+		cursor := &dto.Cursor{Date: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), MovementID: 15}
+		testCases := []struct {
+			Name       string
+			Cursor     *dto.Cursor
+			Descending bool
+			Where      string
+			Order      string
+		}{
+			{Name: "First page from the newest", Descending: true, Order: "ORDER BY date DESC, movement_id DESC"},
+			{Name: "Page after a cursor from the newest", Cursor: cursor, Descending: true, Where: "(date, movement_id) < ", Order: "ORDER BY date DESC, movement_id DESC"},
+			{Name: "Page before a cursor from the newest", Cursor: &dto.Cursor{Date: cursor.Date, MovementID: 15, Backward: true}, Descending: true, Where: "(date, movement_id) > ", Order: "ORDER BY date ASC, movement_id ASC"},
+			{Name: "Page after a cursor from the oldest", Cursor: cursor, Where: "(date, movement_id) > ", Order: "ORDER BY date ASC, movement_id ASC"},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				pagination := dto.NewCursorPagination(20, testCase.Cursor)
+				subQuery := db.Scopes(MovementByCustomerID(1), MovementKeyset(pagination, testCase.Descending)).Find(nil).Statement
+
+				//Data Assertion: query
+				assert.Contains(t, subQuery.SQL.String(), testCase.Where)
+				assert.Contains(t, subQuery.SQL.String(), testCase.Order)
+				assert.Contains(t, subQuery.SQL.String(), "LIMIT 21")
+			})
+		}
+	})
+}
+
+func TestMovementScopeQueries(t *testing.T) {
+	july := func(day int) *time.Time {
+		date := time.Date(2022, time.July, day, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	amount := func(value int) *money.Amount {
+		amount := money.Amount(value)
+		return &amount
+	}
+	testCases := []struct {
+		Name     string
+		Scopes   []func(db *gorm.DB) *gorm.DB
+		Unscoped bool
+		Expected []int
+	}{
+		{
+			Name:     "Not deleted",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementsNotDeleted(), MovementsOrderBy(constant.MovementSortAmount)},
+			Unscoped: true,
+			Expected: []int{11, 12, 21, 31, 32},
+		},
+		{
+			Name:     "Before a date",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementsBeforeDate(time.Date(2022, time.July, 2, 12, 0, 0, 0, time.UTC)), MovementsOrderBy(constant.MovementSortDate)},
+			Expected: []int{11, 12},
+		},
+		{
+			Name:     "Until a date",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementsUntilDate(time.Date(2022, time.July, 2, 12, 0, 0, 0, time.UTC)), MovementsOrderBy(constant.MovementSortDate)},
+			Expected: []int{11, 12, 21},
+		},
+		{
+			Name:     "Date range with the whole last day",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementsByDateRange(july(2), july(3)), MovementsOrderBy(constant.MovementSortDate)},
+			Expected: []int{21, 31, 32},
+		},
+		{
+			Name:     "Date range from a day",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementsByDateRange(july(3), nil), MovementsOrderBy(constant.MovementSortDate)},
+			Expected: []int{31, 32},
+		},
+		{
+			Name:     "Type",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementsByType(constant.OutcomeType), MovementsOrderBy(constant.MovementSortDateDesc)},
+			Expected: []int{32, 12},
+		},
+		{
+			Name:     "Amount range",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementsByAmountRange(amount(12), amount(31)), MovementsOrderBy(constant.MovementSortAmountDesc)},
+			Expected: []int{31, 21, 12},
+		},
+		{
+			Name: "Filter",
+			Scopes: []func(db *gorm.DB) *gorm.DB{
+				MovementsByFilter(dto.MovementFilter{From: july(1), To: july(2), Type: constant.IncomeType, MinAmount: amount(20)}),
+				MovementsOrderBy(""),
+			},
+			Expected: []int{21},
+		},
+		{
+			Name:     "Keyset",
+			Scopes:   []func(db *gorm.DB) *gorm.DB{MovementKeyset(dto.NewCursorPagination(2, nil), true)},
+			Expected: []int{32, 31, 21},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tx := database.GetStoriGormConnection().Begin()
+			addFixtures(tx)
+			db := tx
+			if testCase.Unscoped {
+				db = tx.Unscoped()
+			}
+
+			got := externalIDs(t, db.Scopes(testCase.Scopes...))
+
+			//Data Assertion
+			assert.Equal(t, testCase.Expected, got)
+
+			t.Cleanup(func() {
+				tx.Rollback()
+			})
+		})
+	}
 }
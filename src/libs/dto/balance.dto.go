@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"stori-service/src/libs/money"
+	"time"
+)
+
+/*
+BalanceAt is a DTO with the balances of a customer as of a date, one for each currency it had movements in,
+From and To are the days of the window of the totals, nil when it doesn't limit it
+*/
+type BalanceAt struct {
+	CustomerID int                 `json:"customer_id" groups:"client"`
+	At         time.Time           `json:"at" groups:"client"`
+	From       *time.Time          `json:"from" groups:"client"`
+	To         *time.Time          `json:"to" groups:"client"`
+	Currencies []CurrencyBalanceAt `json:"currencies" groups:"client"`
+}
+
+/*
+CurrencyBalanceAt is a DTO with the available of a customer in a currency after its last movement at or before the date,
+the totals are the incomes and outcomes in the window and are nil when the request doesn't have one
+*/
+type CurrencyBalanceAt struct {
+	Currency     string        `json:"currency" groups:"client"`
+	Available    money.Amount  `json:"available" groups:"client"`
+	TotalIncome  *money.Amount `json:"total_income" groups:"client"`
+	TotalOutcome *money.Amount `json:"total_outcome" groups:"client"`
+}
+
+/*
+CurrencyTotals is a DTO with the total incomes and outcomes of the movements of a currency
+*/
+type CurrencyTotals struct {
+	Currency     string
+	TotalIncome  money.Amount
+	TotalOutcome money.Amount
+}
+
+/*
+BalanceFilter has the date of the balances and the days of the window of the totals, a nil day doesn't limit it
+and the window is empty when both are nil
+*/
+type BalanceFilter struct {
+	At   time.Time
+	From *time.Time
+	To   *time.Time
+}
+
+/*
+HasWindow returns true when the balances need the totals of a window
+*/
+func (f BalanceFilter) HasWindow() bool {
+	return f.From != nil || f.To != nil
+}
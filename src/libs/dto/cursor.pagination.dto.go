@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"encoding/base64"
+	"fmt"
+	"stori-service/src/libs/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Cursor is the position of a movement in the keyset pagination over (date, movement_id),
+Backward cursors point to the page before the position instead of the one after it
+*/
+type Cursor struct {
+	Date       time.Time
+	MovementID int
+	Backward   bool
+}
+
+// directions of the cursors in their encoded form
+const (
+	cursorForward  = "next"
+	cursorBackward = "prev"
+)
+
+/*
+Encode returns the opaque form of the cursor that the clients send back
+*/
+func (c *Cursor) Encode() string {
+	direction := cursorForward
+	if c.Backward {
+		direction = cursorBackward
+	}
+	raw := fmt.Sprintf("%s|%d|%s", c.Date.UTC().Format(time.RFC3339Nano), c.MovementID, direction)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+/*
+DecodeCursor parses a cursor made by Encode, any other value is an ErrInvalidCursor
+*/
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || (parts[2] != cursorForward && parts[2] != cursorBackward) {
+		return nil, errors.ErrInvalidCursor
+	}
+	date, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+	movementID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+	return &Cursor{Date: date, MovementID: movementID, Backward: parts[2] == cursorBackward}, nil
+}
+
+/*
+CursorPagination struct useful for: repository methods (position and limit)
+and controllers (set response headers with the cursors of the next and previous pages)
+*/
+type CursorPagination struct {
+	PageSize int
+	Cursor   *Cursor // nil for the first page
+	Next     *Cursor // nil when there are no more movements after the page
+	Prev     *Cursor // nil when there are no movements before the page
+}
+
+/*
+NewCursorPagination is a constructor for CursorPagination struct
+*/
+func NewCursorPagination(pageSize int, cursor *Cursor) *CursorPagination {
+	return &CursorPagination{
+		PageSize: pageSize,
+		Cursor:   cursor,
+	}
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"encoding/base64"
+	"stori-service/src/libs/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			Name   string
+			Cursor *Cursor
+		}{
+			{
+				Name:   "Decoding a cursor to the next page",
+				Cursor: &Cursor{Date: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), MovementID: 15},
+			},
+			{
+				Name:   "Decoding a cursor to the previous page",
+				Cursor: &Cursor{Date: time.Date(2022, time.July, 1, 10, 30, 0, 500, time.UTC), MovementID: 7, Backward: true},
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				encoded := testCase.Cursor.Encode()
+
+				got, err := DecodeCursor(encoded)
+
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.Cursor, got)
+				assert.NotContains(t, encoded, "=") // it's safe in the query without escaping
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			Name    string
+			Encoded string
+		}{
+			{Name: "Not base64", Encoded: "not a cursor!"},
+			{Name: "Missing parts", Encoded: base64.RawURLEncoding.EncodeToString([]byte("2022-07-01T00:00:00Z|15"))},
+			{Name: "Invalid date", Encoded: base64.RawURLEncoding.EncodeToString([]byte("07/01/2022|15|next"))},
+			{Name: "Invalid ID", Encoded: base64.RawURLEncoding.EncodeToString([]byte("2022-07-01T00:00:00Z|abc|next"))},
+			{Name: "Invalid direction", Encoded: base64.RawURLEncoding.EncodeToString([]byte("2022-07-01T00:00:00Z|15|up"))},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				got, err := DecodeCursor(testCase.Encoded)
+
+				assert.ErrorIs(t, err, errors.ErrInvalidCursor)
+				assert.Nil(t, got)
+			})
+		}
+	})
+}
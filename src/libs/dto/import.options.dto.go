@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+/*
+ImportOptions are the options sent by the partner to process a file
+*/
+type ImportOptions struct {
+	Mode           string
+	Profile        string    // name of the bank profile with the layout of the file
+	Source         string    // bank or account the file comes from, the IDs of the file are unique for each source
+	SourceCurrency string    // ISO 4217 currency of the lines that don't have one in the file
+	Format         string    // statement format, when empty it's picked by the file name or content
+	Preview        bool      // the file is processed but nothing is saved
+	DateFormat     string    // one of the constant.DateLayouts formats
+	StatementDate  time.Time // end of the statement period, used to infer the year of the dates without it
+}
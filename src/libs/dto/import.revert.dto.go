@@ -0,0 +1,18 @@
+package dto
+
+import "stori-service/src/libs/validator"
+
+/*
+ImportRevert is the body sent to revert an import batch, with who reverts it and why
+*/
+type ImportRevert struct {
+	RevertedBy string `json:"reverted_by" validate:"required,max=255"`
+	Reason     string `json:"reason" validate:"required,max=1000"`
+}
+
+/*
+Validate returns an error if the body doesn't pass any of its own validations
+*/
+func (importRevert *ImportRevert) Validate() error {
+	return validator.ValidateStruct(importRevert)
+}
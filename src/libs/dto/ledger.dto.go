@@ -0,0 +1,37 @@
+package dto
+
+import "stori-service/src/libs/money"
+
+/*
+TrialBalance is a DTO with the debits and credits of each account of the ledger and their totals by currency.
+The ledger is balanced when the debits and credits of every currency are the same
+*/
+type TrialBalance struct {
+	Balanced   bool                   `json:"balanced" groups:"client"`
+	Currencies []CurrencyTrialBalance `json:"currencies" groups:"client"`
+	Accounts   []AccountBalance       `json:"accounts" groups:"client"`
+}
+
+/*
+CurrencyTrialBalance is a DTO with the total debits and credits of the accounts of a currency
+*/
+type CurrencyTrialBalance struct {
+	Currency string       `json:"currency" groups:"client"`
+	Debit    money.Amount `json:"debit" groups:"client"`
+	Credit   money.Amount `json:"credit" groups:"client"`
+	Balanced bool         `json:"balanced" groups:"client"`
+}
+
+/*
+AccountBalance is a DTO with the debits and credits posted to an account, Balance is the debits minus the credits
+*/
+type AccountBalance struct {
+	AccountID  int          `json:"account_id" groups:"client"`
+	Code       string       `json:"code" groups:"client"`
+	Type       string       `json:"type" groups:"client"`
+	CustomerID *int         `json:"customer_id" groups:"client"`
+	Currency   string       `json:"currency" groups:"client"`
+	Debit      money.Amount `json:"debit" groups:"client"`
+	Credit     money.Amount `json:"credit" groups:"client"`
+	Balance    money.Amount `json:"balance" groups:"client"`
+}
@@ -2,12 +2,40 @@ package dto
 
 import (
 	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/money"
 )
 
 /*
-MovementList is a DTO to list all Movements of a customer
+MovementList is a DTO with the result of processing a file of a customer: its import batch, summary,
+the lines that were rejected and, only in preview mode, the first movements of the file.
+AlreadyImported is set when the file was imported before and the result is the one of that import
 */
 type MovementList struct {
-	Customer  *entity.Customer  `json:"customer" groups:"client"`
-	Movements []entity.Movement `json:"movements" groups:"client"`
+	Customer        *entity.Customer        `json:"customer" groups:"client"`
+	ImportBatch     *entity.ImportBatch     `json:"import_batch" groups:"client"`
+	AlreadyImported bool                    `json:"already_imported" groups:"client"`
+	Movements       []entity.Movement       `json:"movements" groups:"client"`
+	Rejected        entity.ImportLineErrors `json:"rejected" groups:"client"`
+	Summary         *ImportSummary          `json:"summary" groups:"client"`
+}
+
+/*
+ImportSummary is a DTO with the totals of a processed file, MonthlyRows has the number of valid rows
+of each month (YYYY-MM) and Balances the amounts of each currency, sorted by currency.
+The home amounts are the ones of all the balances converted to the home currency of the customer,
+CategoryOutcomes has the outcomes of each category in the home currency, without the movements without category
+*/
+type ImportSummary struct {
+	TotalRows        int                     `json:"total_rows" groups:"client"`
+	ImportedRows     int                     `json:"imported_rows" groups:"client"`
+	RejectedRows     int                     `json:"rejected_rows" groups:"client"`
+	IncomeRows       int                     `json:"income_rows" groups:"client"`
+	OutcomeRows      int                     `json:"outcome_rows" groups:"client"`
+	MonthlyRows      map[string]int          `json:"monthly_rows" groups:"client"`
+	CategoryOutcomes map[string]money.Amount `json:"category_outcomes" groups:"client"`
+	Balances         entity.CurrencyBalances `json:"balances" groups:"client"`
+	HomeCurrency     string                  `json:"home_currency" groups:"client"`
+	HomeIncome       money.Amount            `json:"home_income" groups:"client"`
+	HomeOutcome      money.Amount            `json:"home_outcome" groups:"client"`
+	HomeAvailable    money.Amount            `json:"home_available" groups:"client"`
 }
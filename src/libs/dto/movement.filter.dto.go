@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"stori-service/src/libs/money"
+	"time"
+)
+
+/*
+MovementFilter are the filters sent to list the movements of a customer, the nil or zero ones don't filter.
+From and To are days, both included, the amounts are in the home currency of the customer
+*/
+type MovementFilter struct {
+	From      *time.Time
+	To        *time.Time
+	Type      int           // constant.IncomeType or constant.OutcomeType
+	MinAmount *money.Amount // included
+	MaxAmount *money.Amount // included
+	Sort      string        // one of the constant.MovementSorts
+}
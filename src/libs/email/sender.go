@@ -2,14 +2,21 @@ package email
 
 import (
 	"fmt"
+	"html"
+	"sort"
 	"stori-service/src/environments/common/resources/entity"
 	"stori-service/src/libs/dto"
 	"stori-service/src/libs/env"
+	"stori-service/src/libs/money"
 	"stori-service/src/utils/constant"
+	"time"
 
 	"github.com/go-gomail/gomail"
 )
 
+// topCategories is the number of spending categories shown in the email
+const topCategories = 3
+
 var (
 	emailServer   = env.EmailServer
 	emailAcount   = env.EmailAccount
@@ -17,47 +24,105 @@ var (
 	emailPassword = env.EmailPassword
 )
 
-// getTransactionByMonth returns a map of transactions by month
-func getTransactionByMonth(movements []entity.Movement) string {
-	transactionByMonth := make(map[string]int)
-	for _, movement := range movements {
-		transactionByMonth[movement.Date.Format("January")]++
+// getTransactionByMonth returns the number of transactions of each month, sorted by month,
+// the months show their year when they aren't all of the same year
+func getTransactionByMonth(monthlyRows map[string]int) string {
+	months := make([]string, 0, len(monthlyRows))
+	for month := range monthlyRows {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	dates := make([]time.Time, 0, len(months))
+	for _, month := range months {
+		date, err := time.Parse(constant.MonthLayout, month)
+		if err != nil {
+			continue
+		}
+		dates = append(dates, date)
+	}
+	layout := "January"
+	if len(dates) > 0 && dates[0].Year() != dates[len(dates)-1].Year() {
+		layout = "January 2006"
 	}
 	list := ""
-	for month, count := range transactionByMonth {
-		list += fmt.Sprintf("Number of transactions in %s: %d<br>", month, count)
+	for _, date := range dates {
+		list += fmt.Sprintf("Number of transactions in %s: %d<br>", date.Format(layout), monthlyRows[date.Format(constant.MonthLayout)])
 	}
 	return list
 }
 
-// getAvgDebit is a partial application of getAvgDebitOrCredit with debit as the movement type
-func getAvgDebit(movements []entity.Movement) float64 {
-	return getAvgDebitOrCredit(movements, constant.OutcomeType)
+// getAvgDebit returns the average debit amount of the balance, rounded to cents
+func getAvgDebit(balance *entity.CurrencyBalance) money.Amount {
+	return balance.TotalOutcome.Div(balance.OutcomeRows)
 }
 
-// getAvgCredit is a partial application of getAvgDebitOrCredit with credit as the movement type
-func getAvgCredit(movements []entity.Movement) float64 {
-	return getAvgDebitOrCredit(movements, constant.IncomeType)
+// getAvgCredit returns the average credit amount of the balance, rounded to cents
+func getAvgCredit(balance *entity.CurrencyBalance) money.Amount {
+	return balance.TotalIncome.Div(balance.IncomeRows)
 }
 
-// getAvgDebitOrCredit returns the average debit or credit amount
-func getAvgDebitOrCredit(movements []entity.Movement, movementType int) float64 {
-	var sum float64
-	var count int
-	for _, movement := range movements {
-		if movement.Type == movementType {
-			sum += movement.Quantity
-			count++
+// getBalance returns the balance and the average amounts of the currency with the given title
+func getBalance(title string, balance *entity.CurrencyBalance) string {
+	return fmt.Sprintf(`
+		<p>
+		%s in %s is: <strong>%s</strong><br>
+		Average debit amount: <strong>%s</strong>
+		Average credit amount: <strong>%s</strong>
+		</p>`, title, balance.Currency, balance.FinalAvailable, getAvgDebit(balance), getAvgCredit(balance))
+}
+
+// getBalances returns the total balance and the average amounts in the home currency of the customer,
+// followed by the ones of each currency when the import has amounts in other currencies
+func getBalances(summary *dto.ImportSummary) string {
+	list := getBalance("Your total balance", &entity.CurrencyBalance{
+		Currency:       summary.HomeCurrency,
+		IncomeRows:     summary.IncomeRows,
+		OutcomeRows:    summary.OutcomeRows,
+		TotalIncome:    summary.HomeIncome,
+		TotalOutcome:   summary.HomeOutcome,
+		FinalAvailable: summary.HomeAvailable,
+	})
+	if len(summary.Balances) == 1 && summary.Balances[0].Currency == summary.HomeCurrency {
+		return list
+	}
+	for index := range summary.Balances {
+		list += getBalance("Your balance", &summary.Balances[index])
+	}
+	return list
+}
+
+// getTopCategories returns the categories with the highest outcomes in the home currency, empty when
+// the movements don't have categories
+func getTopCategories(summary *dto.ImportSummary) string {
+	categories := make([]string, 0, len(summary.CategoryOutcomes))
+	for category := range summary.CategoryOutcomes {
+		categories = append(categories, category)
+	}
+	if len(categories) == 0 {
+		return ""
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		outcomeI, outcomeJ := summary.CategoryOutcomes[categories[i]], summary.CategoryOutcomes[categories[j]]
+		if outcomeI != outcomeJ {
+			return outcomeI > outcomeJ
 		}
+		return categories[i] < categories[j]
+	})
+	if len(categories) > topCategories {
+		categories = categories[:topCategories]
+	}
+	list := fmt.Sprintf("Your top spending categories in %s:<br>", summary.HomeCurrency)
+	for _, category := range categories {
+		list += fmt.Sprintf("%s: <strong>%s</strong><br>", html.EscapeString(category), summary.CategoryOutcomes[category])
 	}
-	return sum / float64(count)
+	return list
 }
 
 func getHTML(movementList *dto.MovementList) string {
-	listByMonth := getTransactionByMonth(movementList.Movements)
-	avgDebit := getAvgDebit(movementList.Movements)
-	avgCredit := getAvgCredit(movementList.Movements)
-	currentBalance := movementList.Movements[len(movementList.Movements)-1].Available
+	summary := movementList.Summary
+	listByMonth := getTransactionByMonth(summary.MonthlyRows)
+	balances := getBalances(summary)
+	categories := getTopCategories(summary)
 	storiLogoURL := "https://dd7tel2830j4w.cloudfront.net/f1650918197627x637468688019988200/Stori%20splash.svg"
 	return fmt.Sprintf(`
 		<center>
@@ -66,17 +131,14 @@ func getHTML(movementList *dto.MovementList) string {
 		<p>
 		Hello, <strong>%s</strong>!<br>
 		</p>
-		<p>
-		Your total balance is: <strong>%.2f</strong>
-		</p>
+		%s
 		<p>
 		%s
 		</p>
 		<p>
-		Average debit amount: <strong>%.2f</strong>
-		Average credit amount: <strong>%.2f</strong>
+		%s
 		</p>
-	`, storiLogoURL, movementList.Customer.Name, currentBalance, listByMonth, avgDebit, avgCredit)
+	`, storiLogoURL, movementList.Customer.Name, balances, listByMonth, categories)
 }
 
 func SendEmail(movementList *dto.MovementList) error {
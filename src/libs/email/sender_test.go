@@ -2,9 +2,9 @@ package email
 
 import (
 	"stori-service/src/environments/common/resources/entity"
-	"stori-service/src/utils/constant"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/money"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,19 +13,25 @@ func TestGetTransactionByMonth(t *testing.T) {
 	t.Run("Should success on", func(t *testing.T) {
 		t.Run("Getting list by month", func(t *testing.T) {
 			// fixture
-			movements := []entity.Movement{
-				{
-					Date: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
-				},
-				{
-					Date: time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC),
-				},
-			}
+			monthlyRows := map[string]int{"2020-03": 1, "2020-01": 2}
+
 			// action
-			list := getTransactionByMonth(movements)
+			list := getTransactionByMonth(monthlyRows)
 
 			// assert
-			assert.Equal(t, "Number of transactions in January: 1<br>Number of transactions in March: 1<br>", list)
+			assert.Equal(t, "Number of transactions in January: 2<br>Number of transactions in March: 1<br>", list)
+		})
+		t.Run("Getting list by month of several years", func(t *testing.T) {
+			// fixture
+			monthlyRows := map[string]int{"2025-01": 3, "2024-12": 1, "2024-01": 2}
+
+			// action
+			list := getTransactionByMonth(monthlyRows)
+
+			// assert
+			assert.Equal(t, "Number of transactions in January 2024: 2<br>"+
+				"Number of transactions in December 2024: 1<br>"+
+				"Number of transactions in January 2025: 3<br>", list)
 		})
 	})
 }
@@ -34,23 +40,20 @@ func TestGetAvgCredit(t *testing.T) {
 	t.Run("Should success on", func(t *testing.T) {
 		t.Run("Getting average credit", func(t *testing.T) {
 			// fixture
-			movements := []entity.Movement{
-				{
-					Date:     time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
-					Type:     constant.IncomeType,
-					Quantity: 100.00,
-				},
-				{
-					Date:     time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC),
-					Type:     constant.IncomeType,
-					Quantity: 200.00,
-				},
-			}
+			summary := &entity.CurrencyBalance{TotalIncome: 30000, IncomeRows: 2}
+
 			// action
-			avgCredit := getAvgCredit(movements)
+			avgCredit := getAvgCredit(summary)
 
 			// assert
-			assert.Equal(t, float64(150), avgCredit)
+			assert.Equal(t, money.Amount(15000), avgCredit)
+		})
+		t.Run("Getting average credit without credits", func(t *testing.T) {
+			// action
+			avgCredit := getAvgCredit(&entity.CurrencyBalance{})
+
+			// assert
+			assert.Equal(t, money.Amount(0), avgCredit)
 		})
 	})
 }
@@ -59,23 +62,131 @@ func TestGetAvgDebit(t *testing.T) {
 	t.Run("Should success on", func(t *testing.T) {
 		t.Run("Getting average debit", func(t *testing.T) {
 			// fixture
-			movements := []entity.Movement{
-				{
-					Date:     time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
-					Type:     constant.OutcomeType,
-					Quantity: 100.00,
+			summary := &entity.CurrencyBalance{TotalOutcome: 30000, OutcomeRows: 2}
+
+			// action
+			avgDebit := getAvgDebit(summary)
+
+			// assert
+			assert.Equal(t, money.Amount(15000), avgDebit)
+		})
+		t.Run("Rounding the average to cents", func(t *testing.T) {
+			// fixture
+			summary := &entity.CurrencyBalance{TotalOutcome: 100, OutcomeRows: 3}
+
+			// action
+			avgDebit := getAvgDebit(summary)
+
+			// assert
+			assert.Equal(t, money.Amount(33), avgDebit)
+		})
+	})
+}
+
+func TestGetTopCategories(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Listing the categories with the highest outcomes", func(t *testing.T) {
+			// fixture
+			summary := &dto.ImportSummary{
+				HomeCurrency: "MXN",
+				CategoryOutcomes: map[string]money.Amount{
+					"Transport": 1500,
+					"Groceries": 12050,
+					"Coffee":    1500,
+					"Books":     900,
+				},
+			}
+
+			// action
+			categories := getTopCategories(summary)
+
+			// assert
+			assert.Equal(t, "Your top spending categories in MXN:<br>"+
+				"Groceries: <strong>120.50</strong><br>"+
+				"Coffee: <strong>15.00</strong><br>"+
+				"Transport: <strong>15.00</strong><br>", categories)
+		})
+		t.Run("Escaping the categories", func(t *testing.T) {
+			// fixture
+			summary := &dto.ImportSummary{
+				HomeCurrency:     "MXN",
+				CategoryOutcomes: map[string]money.Amount{"<b>Food</b>": 100},
+			}
+
+			// action
+			categories := getTopCategories(summary)
+
+			// assert
+			assert.Contains(t, categories, "&lt;b&gt;Food&lt;/b&gt;: <strong>1.00</strong>")
+		})
+		t.Run("Movements without categories", func(t *testing.T) {
+			// action
+			categories := getTopCategories(&dto.ImportSummary{HomeCurrency: "MXN"})
+
+			// assert
+			assert.Empty(t, categories)
+		})
+	})
+}
+
+func TestGetHTML(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Showing the amounts in the home currency and each currency with cents", func(t *testing.T) {
+			// fixture
+			movementList := &dto.MovementList{
+				Customer: &entity.Customer{Name: "User 1"},
+				Summary: &dto.ImportSummary{
+					IncomeRows:  2,
+					OutcomeRows: 3,
+					Balances: entity.CurrencyBalances{
+						{Currency: "MXN", TotalIncome: 70, IncomeRows: 2, TotalOutcome: 1000, OutcomeRows: 1, FinalAvailable: -123450,
+							HomeIncome: 70, HomeOutcome: 1000, HomeAvailable: -123450},
+						{Currency: "USD", TotalOutcome: 2500, OutcomeRows: 2, FinalAvailable: 7500,
+							HomeOutcome: 50000, HomeAvailable: 150000},
+					},
+					HomeCurrency:     "MXN",
+					HomeIncome:       70,
+					HomeOutcome:      51000,
+					HomeAvailable:    26550,
+					CategoryOutcomes: map[string]money.Amount{"Books": 50000},
 				},
-				{
-					Date:     time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC),
-					Type:     constant.OutcomeType,
-					Quantity: 200.00,
+			}
+
+			// action
+			html := getHTML(movementList)
+
+			// assert
+			assert.Contains(t, html, "Your total balance in MXN is: <strong>265.50</strong>")
+			assert.Contains(t, html, "Average debit amount: <strong>170.00</strong>")
+			assert.Contains(t, html, "Average credit amount: <strong>0.35</strong>")
+			assert.Contains(t, html, "Your balance in MXN is: <strong>-1234.50</strong>")
+			assert.Contains(t, html, "Average debit amount: <strong>10.00</strong>")
+			assert.Contains(t, html, "Your balance in USD is: <strong>75.00</strong>")
+			assert.Contains(t, html, "Average debit amount: <strong>12.50</strong>")
+			assert.Contains(t, html, "Average credit amount: <strong>0.00</strong>")
+			assert.Contains(t, html, "Books: <strong>500.00</strong>")
+		})
+		t.Run("Showing only the total when all the amounts are in the home currency", func(t *testing.T) {
+			// fixture
+			movementList := &dto.MovementList{
+				Customer: &entity.Customer{Name: "User 1"},
+				Summary: &dto.ImportSummary{
+					IncomeRows: 1,
+					Balances: entity.CurrencyBalances{
+						{Currency: "MXN", TotalIncome: 1000, IncomeRows: 1, FinalAvailable: 1000, HomeIncome: 1000, HomeAvailable: 1000},
+					},
+					HomeCurrency:  "MXN",
+					HomeIncome:    1000,
+					HomeAvailable: 1000,
 				},
 			}
+
 			// action
-			avgDebit := getAvgDebit(movements)
+			html := getHTML(movementList)
 
 			// assert
-			assert.Equal(t, float64(150), avgDebit)
+			assert.Contains(t, html, "Your total balance in MXN is: <strong>10.00</strong>")
+			assert.NotContains(t, html, "Your balance in")
 		})
 	})
 }
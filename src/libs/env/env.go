@@ -67,6 +67,54 @@ var (
 	// FileRoute Params service URL
 	FileRoute string
 
+	// FileSource Backend where the statement files are read from: local, s3 or sftp
+	FileSource string
+
+	// S3Endpoint S3 compatible server endpoint (host:port)
+	S3Endpoint string
+
+	// S3AccessKey S3 access key
+	S3AccessKey string
+
+	// S3SecretKey S3 secret key
+	S3SecretKey string
+
+	// S3Bucket S3 bucket with the statement files
+	S3Bucket string
+
+	// S3UseSSL S3 connection uses https
+	S3UseSSL bool
+
+	// SFTPHost SFTP server host
+	SFTPHost string
+
+	// SFTPPort SFTP server port
+	SFTPPort string
+
+	// SFTPUser SFTP user
+	SFTPUser string
+
+	// SFTPPassword SFTP password
+	SFTPPassword string
+
+	// SFTPHostKey SFTP server public key in authorized_keys format, the key type and the base64 key (ssh-ed25519 AAAA...)
+	SFTPHostKey string
+
+	// SFTPRoot SFTP directory with the statement files
+	SFTPRoot string
+
+	// ImportWorkers Number of workers that process the import jobs
+	ImportWorkers int
+
+	// ImportQueueSize Number of import jobs that can wait to be processed
+	ImportQueueSize int
+
+	// ImportBatchSize Number of movements inserted together while a file is imported
+	ImportBatchSize int
+
+	// MaxUploadSize Maximum size in bytes of an uploaded file
+	MaxUploadSize int
+
 	// EmailServer Email smtp server
 	EmailServer string
 
@@ -122,6 +170,26 @@ func init() {
 	// Params service
 	FileRoute = os.Getenv("FILE_ROUTE")
 
+	// File source
+	FileSource = os.Getenv("FILE_SOURCE")
+	S3Endpoint = os.Getenv("S3_ENDPOINT")
+	S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	S3SecretKey = os.Getenv("S3_SECRET_KEY")
+	S3Bucket = os.Getenv("S3_BUCKET")
+	S3UseSSL, _ = strconv.ParseBool(os.Getenv("S3_USE_SSL"))
+	SFTPHost = os.Getenv("SFTP_HOST")
+	SFTPPort = os.Getenv("SFTP_PORT")
+	SFTPUser = os.Getenv("SFTP_USER")
+	SFTPPassword = os.Getenv("SFTP_PASSWORD")
+	SFTPHostKey = os.Getenv("SFTP_HOST_KEY")
+	SFTPRoot = os.Getenv("SFTP_ROOT")
+
+	// Import jobs
+	processIntEnvVar(&ImportWorkers, "IMPORT_WORKERS", 2)
+	processIntEnvVar(&ImportQueueSize, "IMPORT_QUEUE_SIZE", 100)
+	processIntEnvVar(&ImportBatchSize, "IMPORT_BATCH_SIZE", 1000)
+	processIntEnvVar(&MaxUploadSize, "MAX_UPLOAD_SIZE", 10<<20)
+
 	// Email settings
 	EmailServer = os.Getenv("EMAIL_SERVER")
 	EmailPort, _ = strconv.Atoi(os.Getenv("EMAIL_PORT"))
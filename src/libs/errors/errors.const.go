@@ -21,14 +21,62 @@ var (
 	//ErrPageTooHigh indicates that page param is higher than the valid number
 	ErrPageTooHigh = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.PAGE_TOO_LARGE"})
 
+	//ErrInvalidCursor indicates the cursor param wasn't made by the pagination of the same listing
+	ErrInvalidCursor = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.INVALID_CURSOR"})
+
 	//ErrURLNotFound indicates a requested URL doesn't exist
 	ErrURLNotFound = NewMyError(http.StatusNotFound, i18n.Message{MessageID: "ERRORS.URL_NOT_FOUND"})
 
 	//ErrNotFound indicates an entity not found error
 	ErrNotFound = NewMyError(http.StatusNotFound, i18n.Message{MessageID: "ERRORS.NOT_FOUND"})
 
-	//ErrInvalidFileLine indicates a line in the file is invalid
-	ErrInvalidFileLine = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.INVALID_FILE_LINE"})
+	//ErrInvalidFileLines indicates some lines in the file are invalid, it's used with the count as template
+	ErrInvalidFileLines = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.INVALID_FILE_LINES"})
+
+	//ErrMissingColumns indicates the header of the file doesn't have the columns of the profile, it's used with the columns as template
+	ErrMissingColumns = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.MISSING_COLUMNS"})
+
+	//ErrInvalidStatement indicates the statement file can't be read because it's malformed
+	ErrInvalidStatement = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.INVALID_STATEMENT"})
+
+	//ErrFileNotFound indicates the file to process doesn't exist in the file source
+	ErrFileNotFound = NewMyError(http.StatusNotFound, i18n.Message{MessageID: "ERRORS.FILE_NOT_FOUND"})
+
+	//ErrUnsupportedMediaType indicates the request body has a content type that can't be processed
+	ErrUnsupportedMediaType = NewMyError(http.StatusUnsupportedMediaType, i18n.Message{MessageID: "ERRORS.UNSUPPORTED_MEDIA_TYPE"})
+
+	//ErrFileTooLarge indicates the uploaded file is bigger than the maximum upload size
+	ErrFileTooLarge = NewMyError(http.StatusRequestEntityTooLarge, i18n.Message{MessageID: "ERRORS.FILE_TOO_LARGE"})
+
+	//ErrMissingFile indicates the multipart request doesn't have the expected file
+	ErrMissingFile = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.MISSING_FILE"})
+
+	//ErrImportQueueFull indicates there are too many imports waiting to be processed
+	ErrImportQueueFull = NewMyError(http.StatusServiceUnavailable, i18n.Message{MessageID: "ERRORS.IMPORT_QUEUE_FULL"})
+
+	//ErrImportInterrupted indicates the import job was pending or running when the service stopped
+	ErrImportInterrupted = NewMyError(http.StatusServiceUnavailable, i18n.Message{MessageID: "ERRORS.IMPORT_INTERRUPTED"})
+
+	//ErrInvalidBody indicates the request body is not a valid JSON of the expected object
+	ErrInvalidBody = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.INVALID_BODY"})
+
+	//ErrImportBatchReverted indicates the import batch was already reverted
+	ErrImportBatchReverted = NewMyError(http.StatusConflict, i18n.Message{MessageID: "ERRORS.IMPORT_BATCH_REVERTED"})
+
+	//ErrInvalidRateLine indicates a line of the exchange rates file is not valid, it's used with the line number as template
+	ErrInvalidRateLine = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.INVALID_RATE_LINE"})
+
+	//ErrMissingExchangeRate indicates there isn't an exchange rate of the currencies for the date, it's used with them as template
+	ErrMissingExchangeRate = NewMyError(http.StatusUnprocessableEntity, i18n.Message{MessageID: "ERRORS.MISSING_EXCHANGE_RATE"})
+
+	//ErrUnbalancedEntry indicates the postings of a journal entry don't balance to zero
+	ErrUnbalancedEntry = NewMyError(http.StatusUnprocessableEntity, i18n.Message{MessageID: "ERRORS.UNBALANCED_ENTRY"})
+
+	//ErrOverdraft indicates a movement leaves the balance below the overdraft allowed to the customer, it's used with the movement as template
+	ErrOverdraft = NewMyError(http.StatusUnprocessableEntity, i18n.Message{MessageID: "ERRORS.OVERDRAFT"}).SetAction("overdraft")
+
+	//ErrCreditLimit indicates a movement leaves the balance of all the currencies below the credit limit of the customer, it's used with the movement as template
+	ErrCreditLimit = NewMyError(http.StatusUnprocessableEntity, i18n.Message{MessageID: "ERRORS.CREDIT_LIMIT"}).SetAction("overdraft")
 
 	//ErrDuplicatedID indicates a that one of the movements id is already on the database
 	ErrDuplicatedID = NewMyError(http.StatusBadRequest, i18n.Message{MessageID: "ERRORS.DUPLICATED_ID"})
@@ -0,0 +1,30 @@
+package filesource
+
+import (
+	"fmt"
+	"stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/env"
+)
+
+// Names of the backends that can be set on FILE_SOURCE
+const (
+	LocalBackend = "local"
+	S3Backend    = "s3"
+	SFTPBackend  = "sftp"
+)
+
+/*
+NewFileSource returns the file source chosen by the FILE_SOURCE environment variable,
+local file system is used when it's empty
+*/
+func NewFileSource() (interfaces.IFileSource, error) {
+	switch env.FileSource {
+	case "", LocalBackend:
+		return NewLocalFileSource(env.FileRoute), nil
+	case S3Backend:
+		return NewS3FileSource(env.S3Endpoint, env.S3AccessKey, env.S3SecretKey, env.S3Bucket, env.S3UseSSL)
+	case SFTPBackend:
+		return NewSFTPFileSource(env.SFTPHost, env.SFTPPort, env.SFTPUser, env.SFTPPassword, env.SFTPHostKey, env.SFTPRoot)
+	}
+	return nil, fmt.Errorf("unknown file source %q", env.FileSource)
+}
@@ -0,0 +1,155 @@
+package filesource
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"stori-service/src/libs/env"
+	"stori-service/src/libs/errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFileSource(t *testing.T) {
+	fileSourceBackup := env.FileSource
+	s3EndpointBackup := env.S3Endpoint
+	env.S3Endpoint = "localhost:9000"
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			backend  string
+			expected interface{}
+		}{
+			{backend: "", expected: &localFileSource{}},
+			{backend: LocalBackend, expected: &localFileSource{}},
+			{backend: S3Backend, expected: &s3FileSource{}},
+		}
+		for _, tC := range testCases {
+			t.Run("Backend: "+tC.backend, func(t *testing.T) {
+				env.FileSource = tC.backend
+
+				got, err := NewFileSource()
+
+				assert.NoError(t, err)
+				assert.IsType(t, tC.expected, got)
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []string{"ftp", SFTPBackend} // sftp fails without host key
+		for _, backend := range testCases {
+			t.Run("Backend: "+backend, func(t *testing.T) {
+				env.FileSource = backend
+
+				got, err := NewFileSource()
+
+				assert.Error(t, err)
+				assert.Nil(t, got)
+			})
+		}
+	})
+	t.Cleanup(func() {
+		env.FileSource = fileSourceBackup
+		env.S3Endpoint = s3EndpointBackup
+	})
+}
+
+func TestLocalFileSource(t *testing.T) {
+	root := t.TempDir()
+	ioutil.WriteFile(filepath.Join(root, "customer_1.csv"), []byte("id,date,transaction"), os.ModePerm)
+	fileSource := NewLocalFileSource(root)
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Opening an existing file", func(t *testing.T) {
+			file, err := fileSource.Open("customer_1.csv")
+
+			assert.NoError(t, err)
+			content, _ := ioutil.ReadAll(file)
+			file.Close()
+			assert.Equal(t, "id,date,transaction", string(content))
+		})
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []string{"customer_2.csv", "../customer_1.csv", ".."}
+		for _, name := range testCases {
+			t.Run("Opening "+name, func(t *testing.T) {
+				file, err := fileSource.Open(name)
+
+				assert.Nil(t, file)
+				assert.ErrorIs(t, err, errors.ErrFileNotFound)
+			})
+		}
+	})
+}
+
+/*
+TestS3FileSource runs against the MinIO of the docker-compose with the S3 environment variables,
+it's skipped when the endpoint isn't reachable
+*/
+func TestS3FileSource(t *testing.T) {
+	connection, err := net.DialTimeout("tcp", env.S3Endpoint, time.Second)
+	if env.S3Endpoint == "" || err != nil {
+		t.Skip("S3 endpoint isn't reachable")
+	}
+	connection.Close()
+	bucket := "filesource-test"
+	fileSource, err := NewS3FileSource(env.S3Endpoint, env.S3AccessKey, env.S3SecretKey, bucket, env.S3UseSSL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fileSource.(*s3FileSource).client
+	ctx := context.Background()
+	if exists, _ := client.BucketExists(ctx, bucket); !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content := "id,date,transaction"
+	if _, err := client.PutObject(ctx, bucket, "customer_1.csv", strings.NewReader(content), int64(len(content)), minio.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Opening an existing file", func(t *testing.T) {
+			file, err := fileSource.Open("customer_1.csv")
+
+			assert.NoError(t, err)
+			got, _ := ioutil.ReadAll(file)
+			file.Close()
+			assert.Equal(t, content, string(got))
+		})
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Opening a file that doesn't exist", func(t *testing.T) {
+			file, err := fileSource.Open("customer_2.csv")
+
+			assert.Nil(t, file)
+			assert.ErrorIs(t, err, errors.ErrFileNotFound)
+		})
+	})
+	t.Cleanup(func() {
+		client.RemoveObject(ctx, bucket, "customer_1.csv", minio.RemoveObjectOptions{})
+	})
+}
+
+func TestNewSFTPFileSource(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		hostKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+
+		got, err := NewSFTPFileSource("localhost", "22", "user", "password", hostKey, "/statements")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "localhost:22", got.(*sftpFileSource).address)
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Invalid host key", func(t *testing.T) {
+			got, err := NewSFTPFileSource("localhost", "22", "user", "password", "invalid", "/statements")
+
+			assert.Error(t, err)
+			assert.Nil(t, got)
+		})
+	})
+}
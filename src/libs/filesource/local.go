@@ -0,0 +1,41 @@
+package filesource
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/errors"
+)
+
+/*
+struct that implements IFileSource reading from a local directory
+*/
+type localFileSource struct {
+	root string
+}
+
+/*
+NewLocalFileSource creates a file source that opens the files inside the root directory
+*/
+func NewLocalFileSource(root string) interfaces.IFileSource {
+	return &localFileSource{root: root}
+}
+
+/*
+Open opens the file with the given name inside the root directory,
+names with directories are rejected so files outside root can't be read
+*/
+func (s *localFileSource) Open(name string) (io.ReadSeekCloser, error) {
+	if name != filepath.Base(name) || name == ".." {
+		return nil, errors.ErrFileNotFound
+	}
+	file, err := os.Open(filepath.Join(s.root, name))
+	if os.IsNotExist(err) {
+		return nil, errors.ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
@@ -0,0 +1,52 @@
+package filesource
+
+import (
+	"context"
+	"io"
+	"stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/errors"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+/*
+struct that implements IFileSource reading from a S3 compatible bucket (AWS, MinIO, etc.)
+*/
+type s3FileSource struct {
+	client *minio.Client
+	bucket string
+}
+
+/*
+NewS3FileSource creates a file source that opens the objects of the bucket
+*/
+func NewS3FileSource(endpoint, accessKey, secretKey, bucket string, useSSL bool) (interfaces.IFileSource, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3FileSource{client: client, bucket: bucket}, nil
+}
+
+/*
+Open returns the object with the given name, the content is streamed while it's being read
+*/
+func (s *s3FileSource) Open(name string) (io.ReadSeekCloser, error) {
+	object, err := s.client.GetObject(context.Background(), s.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject doesn't make any request until the first read, so check that it exists
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, errors.ErrFileNotFound
+		}
+		return nil, err
+	}
+	return object, nil
+}
@@ -0,0 +1,89 @@
+package filesource
+
+import (
+	"io"
+	"net"
+	"os"
+	"path"
+	"stori-service/src/environments/common/resources/interfaces"
+	"stori-service/src/libs/errors"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+/*
+struct that implements IFileSource reading from a directory of a SFTP server
+*/
+type sftpFileSource struct {
+	address string
+	config  *ssh.ClientConfig
+	root    string
+}
+
+/*
+sftpFile closes the connection to the server when the file is closed
+*/
+type sftpFile struct {
+	*sftp.File
+	client *sftp.Client
+	conn   *ssh.Client
+}
+
+/*
+NewSFTPFileSource creates a file source that opens the files inside the root directory of the server,
+hostKey is the server public key in authorized_keys format, the key type and the base64 key (ssh-ed25519 AAAA...),
+and it's required to verify the server
+*/
+func NewSFTPFileSource(host, port, user, password, hostKey, root string) (interfaces.IFileSource, error) {
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: ssh.FixedHostKey(publicKey),
+	}
+	return &sftpFileSource{address: net.JoinHostPort(host, port), config: config, root: root}, nil
+}
+
+/*
+Open connects to the server and opens the file with the given name inside the root directory,
+each file uses its own connection so concurrent imports don't share state.
+Names with directories are rejected so files outside root can't be read
+*/
+func (s *sftpFileSource) Open(name string) (io.ReadSeekCloser, error) {
+	if name != path.Base(name) || name == ".." {
+		return nil, errors.ErrFileNotFound
+	}
+	conn, err := ssh.Dial("tcp", s.address, s.config)
+	if err != nil {
+		return nil, errors.ErrConnectionProvider
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	file, err := client.Open(path.Join(s.root, name))
+	if err != nil {
+		client.Close()
+		conn.Close()
+		if os.IsNotExist(err) {
+			return nil, errors.ErrFileNotFound
+		}
+		return nil, err
+	}
+	return &sftpFile{File: file, client: client, conn: conn}, nil
+}
+
+/*
+Close closes the file and the connection to the server
+*/
+func (f *sftpFile) Close() error {
+	err := f.File.Close()
+	f.client.Close()
+	f.conn.Close()
+	return err
+}
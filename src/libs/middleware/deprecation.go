@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"stori-service/src/libs/logger"
+)
+
+/*
+DeprecationMiddleware marks the responses of a deprecated route with the Deprecation header and the Link
+to the route that replaces it, given by successor, and logs each call so the clients still using it can be found
+*/
+func DeprecationMiddleware(successor func(request *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			successorURL := successor(r)
+			logger.GetInstance().Warnf("Deprecated route %s %s called by %s (%s), use %s", r.Method, r.URL.Path, r.RemoteAddr, r.UserAgent(), successorURL)
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successorURL))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
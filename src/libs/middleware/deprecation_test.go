@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	customMocks "stori-service/src/utils/test/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockHTTPHandler := new(customMocks.MockHTTPHandler)
+		successor := func(request *http.Request) string {
+			return request.URL.Path + "/imports"
+		}
+
+		mockHTTPHandler.On("ServeHTTP", mock.Anything, mock.Anything).Return()
+		ts := httptest.NewServer(DeprecationMiddleware(successor)(mockHTTPHandler))
+		defer ts.Close()
+		req, _ := http.NewRequest("GET", ts.URL+"/1", nil)
+		res, _ := ts.Client().Do(req)
+
+		//Mock Assertion: Behavioral
+		mockHTTPHandler.AssertNumberOfCalls(t, "ServeHTTP", 1)
+
+		//Data Assertion
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "true", res.Header.Get("Deprecation"))
+		assert.Equal(t, `</1/imports>; rel="successor-version"`, res.Header.Get("Link"))
+	})
+}
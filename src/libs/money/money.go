@@ -0,0 +1,242 @@
+package money
+
+import (
+	"database/sql/driver"
+	goerrors "errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	//ErrInvalidAmount indicates the value is not a decimal number
+	ErrInvalidAmount = goerrors.New("invalid amount")
+
+	//ErrPrecision indicates the value has more decimals than cents
+	ErrPrecision = goerrors.New("amount with more than 2 decimals")
+)
+
+// maxDigits keeps the amount in an int64 and in the numeric(19,2) columns
+const maxDigits = 17
+
+/*
+Amount is an amount of money in cents, so sums and balances are exact. It's saved as a numeric
+with 2 decimals in database and shown as a number with 2 decimals in JSON
+*/
+type Amount int64
+
+/*
+Parse receives a decimal number with '.' as decimal separator and an optional sign and returns the amount,
+decimals after the cents are only accepted when they are zeros
+*/
+func Parse(value string) (Amount, error) {
+	amount, err := parseDecimal(value, 2, maxDigits)
+	return Amount(amount), err
+}
+
+/*
+parseDecimal returns the decimal number as an integer of the given decimals, failing with ErrPrecision
+when it has more decimals that are not zeros and with ErrInvalidAmount when it's not a number
+or it has more than maxUnits digits before the decimals
+*/
+func parseDecimal(value string, decimals, maxUnits int) (int64, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	if negative || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+	units, fraction := value, ""
+	if point := strings.Index(value, "."); point >= 0 {
+		units, fraction = value[:point], value[point+1:]
+	}
+	if units == "" && fraction == "" || !isDigits(units) || !isDigits(fraction) {
+		return 0, ErrInvalidAmount
+	}
+	if strings.Trim(fraction[min(len(fraction), decimals):], "0") != "" {
+		return 0, ErrPrecision
+	}
+	fraction = (fraction + strings.Repeat("0", decimals))[:decimals]
+	units = strings.TrimLeft(units, "0")
+	if len(units) > maxUnits {
+		return 0, ErrInvalidAmount
+	}
+	number, err := strconv.ParseInt(units+fraction, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	if negative {
+		number = -number
+	}
+	return number, nil
+}
+
+/*
+isDigits tells if the value only has decimal digits, an empty value has none
+*/
+func isDigits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+min returns the lowest of two numbers
+*/
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+/*
+Abs returns the amount without sign
+*/
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+/*
+Sign returns 1 for positive amounts, -1 for negative ones and 0 for zero
+*/
+func (a Amount) Sign() int {
+	switch {
+	case a > 0:
+		return 1
+	case a < 0:
+		return -1
+	}
+	return 0
+}
+
+/*
+HasDecimals returns true when the amount doesn't have more decimals than the given ones,
+as 0 for currencies without cents
+*/
+func (a Amount) HasDecimals(decimals int) bool {
+	unit := Amount(1)
+	for i := decimals; i < 2; i++ {
+		unit *= 10
+	}
+	return a%unit == 0
+}
+
+/*
+Convert returns the amount in another currency with the exchange rate, rounded to cents half away from zero
+*/
+func (a Amount) Convert(rate Rate) Amount {
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(rate)))
+	half := big.NewInt(int64(OneRate / 2))
+	if product.Sign() < 0 {
+		product.Sub(product, half)
+	} else {
+		product.Add(product, half)
+	}
+	return Amount(product.Quo(product, big.NewInt(int64(OneRate))).Int64())
+}
+
+/*
+Div divides the amount in equal parts rounding half away from zero, as the averages of the email
+*/
+func (a Amount) Div(parts int) Amount {
+	if parts == 0 {
+		return 0
+	}
+	divisor := int64(parts)
+	quotient, remainder := int64(a)/divisor, int64(a)%divisor
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	if divisor < 0 {
+		divisor = -divisor
+	}
+	// the remainder is at least half of the divisor, without doubling it so it can't overflow
+	if remainder >= divisor-remainder {
+		if (a < 0) == (parts < 0) {
+			quotient++
+		} else {
+			quotient--
+		}
+	}
+	return Amount(quotient)
+}
+
+/*
+String returns the amount with 2 decimals, as -1234.50
+*/
+func (a Amount) String() string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+	}
+	cents := int64(a.Abs())
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+/*
+MarshalJSON returns the amount as a JSON number with 2 decimals
+*/
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+/*
+UnmarshalJSON reads the amount from a JSON number or string
+*/
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	amount, err := Parse(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+/*
+Value returns the amount as a decimal to be saved in a numeric column
+*/
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+/*
+Scan reads the amount from a numeric column
+*/
+func (a *Amount) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case int64:
+		*a = Amount(value * 100)
+		return nil
+	case float64:
+		*a = Amount(math.Round(value * 100))
+		return nil
+	case []byte:
+		return a.scanString(string(value))
+	case string:
+		return a.scanString(value)
+	}
+	return fmt.Errorf("can't scan %T into money.Amount", src)
+}
+
+/*
+scanString parses the decimal of the column, which can have more zeros than the cents
+*/
+func (a *Amount) scanString(value string) error {
+	amount, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
@@ -0,0 +1,182 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			value    string
+			expected Amount
+		}{
+			{name: "Integer", value: "12", expected: 1200},
+			{name: "Cents", value: "12.34", expected: 1234},
+			{name: "One decimal", value: "12.3", expected: 1230},
+			{name: "Negative", value: "-0.05", expected: -5},
+			{name: "Plus sign", value: "+3.5", expected: 350},
+			{name: "Without units", value: ".5", expected: 50},
+			{name: "Without decimals", value: "5.", expected: 500},
+			{name: "Zeros after the cents", value: "10.5000", expected: 1050},
+			{name: "Spaces", value: " 7.10 ", expected: 710},
+			{name: "Big amount", value: "12345678901234567.89", expected: 1234567890123456789},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				amount, err := Parse(testCase.value)
+
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, amount)
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			value    string
+			expected error
+		}{
+			{name: "Empty", value: "", expected: ErrInvalidAmount},
+			{name: "Only sign", value: "-", expected: ErrInvalidAmount},
+			{name: "Plus and minus", value: "+-5", expected: ErrInvalidAmount},
+			{name: "Minus and plus", value: "-+5", expected: ErrInvalidAmount},
+			{name: "Two minus", value: "--5", expected: ErrInvalidAmount},
+			{name: "Two plus", value: "++5", expected: ErrInvalidAmount},
+			{name: "Letters", value: "12a", expected: ErrInvalidAmount},
+			{name: "Exponent", value: "1e3", expected: ErrInvalidAmount},
+			{name: "Two points", value: "1.2.3", expected: ErrInvalidAmount},
+			{name: "Too big", value: "123456789012345678", expected: ErrInvalidAmount},
+			{name: "Fractions of cent", value: "1.005", expected: ErrPrecision},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				amount, err := Parse(testCase.value)
+
+				assert.Equal(t, testCase.expected, err)
+				assert.Zero(t, amount)
+			})
+		}
+	})
+}
+
+func TestAmount(t *testing.T) {
+	t.Run("Accumulating cents without drift", func(t *testing.T) {
+		// 0.1 + 0.2 is 0.30000000000000004 with float64
+		var total Amount
+		for i := 0; i < 1000; i++ {
+			cents, _ := Parse("0.1")
+			total += cents
+		}
+		twentyCents, _ := Parse("0.2")
+		total += twentyCents
+
+		assert.Equal(t, Amount(10020), total)
+		assert.Equal(t, "100.20", total.String())
+	})
+	t.Run("Balance that goes negative and back", func(t *testing.T) {
+		var available Amount
+		for _, value := range []string{"-1.6", "3.5", "-1.9", "0.01", "-0.01"} {
+			amount, _ := Parse(value)
+			available += amount
+		}
+
+		assert.Equal(t, Amount(0), available)
+		assert.Equal(t, "0.00", available.String())
+	})
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, "-1234.50", Amount(-123450).String())
+		assert.Equal(t, "0.05", Amount(5).String())
+		assert.Equal(t, "-0.05", Amount(-5).String())
+	})
+	t.Run("Abs and Sign", func(t *testing.T) {
+		assert.Equal(t, Amount(160), Amount(-160).Abs())
+		assert.Equal(t, -1, Amount(-160).Sign())
+		assert.Equal(t, 1, Amount(160).Sign())
+		assert.Equal(t, 0, Amount(0).Sign())
+	})
+	t.Run("HasDecimals", func(t *testing.T) {
+		assert.True(t, Amount(1250).HasDecimals(2))
+		assert.True(t, Amount(-1200).HasDecimals(0))
+		assert.False(t, Amount(1250).HasDecimals(0))
+		assert.True(t, Amount(1250).HasDecimals(1))
+		assert.False(t, Amount(1255).HasDecimals(1))
+	})
+	t.Run("Div", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			amount   Amount
+			parts    int
+			expected Amount
+		}{
+			{name: "Rounding down", amount: 100, parts: 3, expected: 33},
+			{name: "Rounding up", amount: 200, parts: 3, expected: 67},
+			{name: "Negative", amount: -200, parts: 3, expected: -67},
+			{name: "Half away from zero", amount: 1, parts: 2, expected: 1},
+			{name: "Negative half away from zero", amount: -1, parts: 2, expected: -1},
+			{name: "Negative parts", amount: 200, parts: -3, expected: -67},
+			{name: "Large amount", amount: 1234567890123456789, parts: 2, expected: 617283945061728395},
+			{name: "Amount over the float64 precision", amount: 9007199254740993, parts: 1, expected: 9007199254740993},
+			{name: "Zero parts", amount: 100, parts: 0, expected: 0},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				assert.Equal(t, testCase.expected, testCase.amount.Div(testCase.parts))
+			})
+		}
+	})
+	t.Run("Convert", func(t *testing.T) {
+		assert.Equal(t, Amount(1000), Amount(1000).Convert(OneRate))
+		assert.Equal(t, Amount(17125), Amount(1000).Convert(1712500000)) // 10.00 at 17.125
+		assert.Equal(t, Amount(-17125), Amount(-1000).Convert(1712500000))
+		assert.Equal(t, Amount(6), Amount(100).Convert(5500000)) // 1.00 at 0.055, half away from zero
+		assert.Equal(t, Amount(-6), Amount(-100).Convert(5500000))
+		assert.Equal(t, Amount(5839), Amount(1000000).Convert(583942)) // 10,000.00 at 0.00583942
+	})
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(struct {
+			Available Amount `json:"available"`
+		}{Available: 350})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"available":3.50}`, string(data))
+
+		var amounts []Amount
+		err = json.Unmarshal([]byte(`[3.5, "-1.60", 10]`), &amounts)
+		assert.NoError(t, err)
+		assert.Equal(t, []Amount{350, -160, 1000}, amounts)
+
+		err = json.Unmarshal([]byte(`[0.001]`), &amounts)
+		assert.Equal(t, ErrPrecision, err)
+	})
+	t.Run("Database", func(t *testing.T) {
+		value, err := Amount(-105).Value()
+		assert.NoError(t, err)
+		assert.Equal(t, "-1.05", value)
+
+		testCases := []struct {
+			name     string
+			src      interface{}
+			expected Amount
+		}{
+			{name: "Numeric as bytes", src: []byte("12.30"), expected: 1230},
+			{name: "Numeric as string", src: "-0.50", expected: -50},
+			{name: "Integer", src: int64(3), expected: 300},
+			{name: "Float", src: 1.15, expected: 115},
+			{name: "Null", src: nil, expected: 0},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				amount := Amount(99)
+				err := amount.Scan(testCase.src)
+
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, amount)
+			})
+		}
+		var amount Amount
+		assert.Error(t, amount.Scan(true))
+	})
+}
@@ -0,0 +1,112 @@
+package money
+
+import (
+	"database/sql/driver"
+	goerrors "errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalidRate indicates the value is not a positive decimal number with up to 8 decimals
+var ErrInvalidRate = goerrors.New("invalid exchange rate")
+
+const (
+	// rateDecimals are the decimals of the exchange rates, as the numeric(19,8) columns
+	rateDecimals = 8
+	// maxRateDigits keeps the rate in an int64 and in the numeric(19,8) columns
+	maxRateDigits = 10
+)
+
+// OneRate is the rate of a currency to itself
+const OneRate Rate = 100000000
+
+/*
+Rate is an exchange rate with 8 decimals, the amount of the quote currency that a unit of the base currency buys.
+It's saved as a numeric with 8 decimals in database and shown as a number in JSON
+*/
+type Rate int64
+
+/*
+ParseRate receives a positive decimal number with '.' as decimal separator and returns the rate,
+decimals after the eighth one are only accepted when they are zeros
+*/
+func ParseRate(value string) (Rate, error) {
+	rate, err := parseDecimal(value, rateDecimals, maxRateDigits)
+	if err != nil || rate <= 0 {
+		return 0, ErrInvalidRate
+	}
+	return Rate(rate), nil
+}
+
+/*
+String returns the rate with 8 decimals, as 17.12345600
+*/
+func (r Rate) String() string {
+	sign := ""
+	if r < 0 {
+		sign = "-"
+		r = -r
+	}
+	return fmt.Sprintf("%s%d.%08d", sign, int64(r/OneRate), int64(r%OneRate))
+}
+
+/*
+MarshalJSON returns the rate as a JSON number with 8 decimals
+*/
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+/*
+UnmarshalJSON reads the rate from a JSON number or string, zero is kept for the entities without rate
+*/
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	rate, err := parseDecimal(strings.Trim(string(data), `"`), rateDecimals, maxRateDigits)
+	if err != nil {
+		return ErrInvalidRate
+	}
+	*r = Rate(rate)
+	return nil
+}
+
+/*
+Value returns the rate as a decimal to be saved in a numeric column
+*/
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+/*
+Scan reads the rate from a numeric column
+*/
+func (r *Rate) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case int64:
+		*r = Rate(value) * OneRate
+		return nil
+	case float64:
+		*r = Rate(math.Round(value * float64(OneRate)))
+		return nil
+	case []byte:
+		return r.scanString(string(value))
+	case string:
+		return r.scanString(value)
+	}
+	return fmt.Errorf("can't scan %T into money.Rate", src)
+}
+
+/*
+scanString parses the decimal of the column
+*/
+func (r *Rate) scanString(value string) error {
+	rate, err := parseDecimal(value, rateDecimals, maxRateDigits)
+	if err != nil {
+		return err
+	}
+	*r = Rate(rate)
+	return nil
+}
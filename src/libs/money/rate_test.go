@@ -0,0 +1,107 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			value    string
+			expected Rate
+		}{
+			{name: "Integer", value: "17", expected: 1700000000},
+			{name: "Eight decimals", value: "0.05839421", expected: 5839421},
+			{name: "Few decimals", value: "17.1", expected: 1710000000},
+			{name: "Zeros after the eighth decimal", value: "1.1234567800", expected: 112345678},
+			{name: "Spaces", value: " 20.5 ", expected: 2050000000},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				rate, err := ParseRate(testCase.value)
+
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, rate)
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			value string
+		}{
+			{name: "Empty", value: ""},
+			{name: "Letters", value: "1a"},
+			{name: "Zero", value: "0.00"},
+			{name: "Negative", value: "-17.5"},
+			{name: "Too many decimals", value: "1.123456789"},
+			{name: "Too big", value: "12345678901"},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				rate, err := ParseRate(testCase.value)
+
+				assert.Equal(t, ErrInvalidRate, err)
+				assert.Zero(t, rate)
+			})
+		}
+	})
+}
+
+func TestRate(t *testing.T) {
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, "1.00000000", OneRate.String())
+		assert.Equal(t, "0.05839421", Rate(5839421).String())
+		assert.Equal(t, "17.12500000", Rate(1712500000).String())
+	})
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(struct {
+			Rate Rate `json:"rate"`
+		}{Rate: 1712500000})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"rate":17.12500000}`, string(data))
+
+		var value struct {
+			Rate Rate `json:"rate"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(`{"rate":0.055}`), &value))
+		assert.Equal(t, Rate(5500000), value.Rate)
+		assert.NoError(t, json.Unmarshal([]byte(`{"rate":"20"}`), &value))
+		assert.Equal(t, Rate(2000000000), value.Rate)
+		assert.NoError(t, json.Unmarshal([]byte(`{"rate":0.00000000}`), &value))
+		assert.Zero(t, value.Rate)
+		assert.Error(t, json.Unmarshal([]byte(`{"rate":"veinte"}`), &value))
+	})
+	t.Run("Database", func(t *testing.T) {
+		value, err := Rate(1712500000).Value()
+		assert.NoError(t, err)
+		assert.Equal(t, "17.12500000", value)
+
+		testCases := []struct {
+			name     string
+			src      interface{}
+			expected Rate
+		}{
+			{name: "Numeric as bytes", src: []byte("17.12500000"), expected: 1712500000},
+			{name: "Numeric as string", src: "0.05839421", expected: 5839421},
+			{name: "Integer", src: int64(1), expected: OneRate},
+			{name: "Float", src: 0.055, expected: 5500000},
+			{name: "Null", src: nil, expected: 0},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				rate := Rate(99)
+				err := rate.Scan(testCase.src)
+
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, rate)
+			})
+		}
+		var rate Rate
+		assert.Error(t, rate.Scan(true))
+	})
+}
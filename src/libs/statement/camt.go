@@ -0,0 +1,195 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"stori-service/src/libs/errors"
+	"strings"
+)
+
+/*
+camtReader reads the Ntry entries of ISO 20022 CAMT.053 statements
+*/
+type camtReader struct {
+	decoder *xml.Decoder
+	lines   *lineCounter
+}
+
+/*
+camtEntry is the part of a CAMT.053 entry used to create a movement, names match any namespace.
+The related parties are the ones of the transaction details, the creditor is the merchant of a debit
+and the debtor the one of a credit
+*/
+type camtEntry struct {
+	NtryRef      string        `xml:"NtryRef"`
+	AcctSvcrRef  string        `xml:"AcctSvcrRef"`
+	Amt          camtAmount    `xml:"Amt"`
+	CdtDbtInd    string        `xml:"CdtDbtInd"`
+	BookgDt      camtDate      `xml:"BookgDt"`
+	ValDt        camtDate      `xml:"ValDt"`
+	AddtlNtryInf string        `xml:"AddtlNtryInf"`
+	RltdPties    camtRltdPties `xml:"NtryDtls>TxDtls>RltdPties"`
+}
+
+/*
+camtRltdPties are the names of the debtor and creditor, as Nm or Pty>Nm depending on the version
+*/
+type camtRltdPties struct {
+	DbtrNm    string `xml:"Dbtr>Nm"`
+	DbtrPtyNm string `xml:"Dbtr>Pty>Nm"`
+	CdtrNm    string `xml:"Cdtr>Nm"`
+	CdtrPtyNm string `xml:"Cdtr>Pty>Nm"`
+}
+
+/*
+camtAmount is the amount of an entry with its currency in the Ccy attribute
+*/
+type camtAmount struct {
+	Value string `xml:",chardata"`
+	Ccy   string `xml:"Ccy,attr"`
+}
+
+/*
+camtDate is a date that can come as Dt (YYYY-MM-DD) or DtTm (ISO datetime)
+*/
+type camtDate struct {
+	Dt   string `xml:"Dt"`
+	DtTm string `xml:"DtTm"`
+}
+
+/*
+NewCAMTReader returns a Reader for the entries of a CAMT.053 statement
+*/
+func NewCAMTReader(file io.Reader) Reader {
+	lines := &lineCounter{reader: file}
+	return &camtReader{decoder: xml.NewDecoder(lines), lines: lines}
+}
+
+/*
+Read returns the next entry of the statement, the line is the one where the Ntry element starts.
+It fails with ErrInvalidStatement when the XML is malformed
+*/
+func (r *camtReader) Read() (*Record, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, errors.ErrInvalidStatement
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Ntry" {
+			continue
+		}
+		record := &Record{Line: r.lines.lineAt(r.decoder.InputOffset())}
+		var entry camtEntry
+		if err := r.decoder.DecodeElement(&entry, &start); err != nil {
+			return nil, errors.ErrInvalidStatement
+		}
+		r.fill(record, &entry)
+		return record, nil
+	}
+}
+
+/*
+Columns returns the CAMT.053 elements of the id, date, amount, currency, description and merchant
+*/
+func (r *camtReader) Columns() Columns {
+	return Columns{ID: "NtryRef", Date: "BookgDt", Amount: "Amt", Currency: "Ccy", Description: "AddtlNtryInf", Merchant: "Nm"}
+}
+
+/*
+fill sets the values of the entry in the record. The id is the entry reference or the one
+of the bank, the date is the booking date or the value date, and debits are negative.
+The description is the additional entry information and the merchant the related party of the other side
+*/
+func (r *camtReader) fill(record *Record, entry *camtEntry) {
+	id := strings.TrimSpace(entry.NtryRef)
+	if id == "" {
+		id = strings.TrimSpace(entry.AcctSvcrRef)
+	}
+	setReference(record, id)
+	record.Date = entry.BookgDt.date()
+	if record.Date == "" {
+		record.Date = entry.ValDt.date()
+	}
+	record.Currency = strings.ToUpper(strings.TrimSpace(entry.Amt.Ccy))
+	record.Description = strings.TrimSpace(entry.AddtlNtryInf)
+	record.Merchant = entry.RltdPties.name(strings.TrimSpace(entry.CdtDbtInd))
+	amount, ok := normalizeAmount(strings.TrimSpace(entry.Amt.Value), '.')
+	if !ok {
+		record.Err = &RecordError{Column: "Amt", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+		return
+	}
+	amount = strings.TrimLeft(amount, "+-")
+	switch strings.TrimSpace(entry.CdtDbtInd) {
+	case "CRDT":
+		record.Amount = amount
+	case "DBIT":
+		record.Amount = "-" + amount
+	default:
+		record.Err = &RecordError{Column: "CdtDbtInd", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+	}
+}
+
+/*
+name returns the name of the creditor of a debit or the debtor of a credit, the indicator is the one of the entry
+*/
+func (p camtRltdPties) name(cdtDbtInd string) string {
+	names := []string{p.DbtrNm, p.DbtrPtyNm}
+	if cdtDbtInd == "DBIT" {
+		names = []string{p.CdtrNm, p.CdtrPtyNm}
+	}
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+/*
+date returns the date part of Dt or DtTm
+*/
+func (d camtDate) date() string {
+	if value := strings.TrimSpace(d.Dt); value != "" {
+		return value
+	}
+	value := strings.TrimSpace(d.DtTm)
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
+
+/*
+lineCounter saves the offsets of the line breaks it reads, so the line of an offset can be known
+*/
+type lineCounter struct {
+	reader   io.Reader
+	offset   int64
+	newlines []int64
+}
+
+/*
+Read reads from the wrapped reader saving the offsets of the line breaks
+*/
+func (c *lineCounter) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	for i, char := range p[:n] {
+		if char == '\n' {
+			c.newlines = append(c.newlines, c.offset+int64(i))
+		}
+	}
+	c.offset += int64(n)
+	return n, err
+}
+
+/*
+lineAt returns the line number of the offset, starting from 1
+*/
+func (c *lineCounter) lineAt(offset int64) int {
+	return sort.Search(len(c.newlines), func(i int) bool { return c.newlines[i] >= offset }) + 1
+}
@@ -0,0 +1,70 @@
+package statement
+
+import (
+	"stori-service/src/libs/errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCAMTReader(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		input := strings.Join([]string{
+			`<?xml version="1.0" encoding="UTF-8"?>`,
+			`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">`,
+			"<BkToCstmrStmt><Stmt>",
+			"<Ntry>",
+			"<NtryRef>1</NtryRef>",
+			`<Amt Ccy="USD">3.50</Amt>`,
+			"<CdtDbtInd>CRDT</CdtDbtInd>",
+			"<BookgDt><Dt>2022-05-25</Dt></BookgDt>",
+			"</Ntry>",
+			"<Ntry>",
+			"<Amt Ccy=\"USD\">1.6</Amt><CdtDbtInd>DBIT</CdtDbtInd>",
+			"<ValDt><DtTm>2022-03-20T10:00:00</DtTm></ValDt><AcctSvcrRef>REF-2</AcctSvcrRef>",
+			"<NtryDtls><TxDtls><RltdPties>",
+			"<Dbtr><Nm>Customer</Nm></Dbtr><Cdtr><Pty><Nm>Super Market</Nm></Pty></Cdtr>",
+			"</RltdPties></TxDtls></NtryDtls>",
+			"<AddtlNtryInf>Weekly shopping</AddtlNtryInf>",
+			"</Ntry>",
+			"</Stmt></BkToCstmrStmt>",
+			"</Document>",
+		}, "\n")
+
+		// action
+		reader := NewCAMTReader(strings.NewReader(input))
+
+		// assertion
+		assert.Equal(t, Columns{ID: "NtryRef", Date: "BookgDt", Amount: "Amt", Currency: "Ccy", Description: "AddtlNtryInf", Merchant: "Nm"}, reader.Columns())
+		assert.Equal(t, []Record{
+			{Line: 4, ID: "1", Date: "2022-05-25", Amount: "3.50", Currency: "USD"},
+			{Line: 10, ID: numericID("REF-2"), Reference: "REF-2", Date: "2022-03-20", Amount: "-1.6", Currency: "USD", Description: "Weekly shopping", Merchant: "Super Market"},
+		}, readAll(t, reader))
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Invalid values", func(t *testing.T) {
+			input := "<Document><Ntry><NtryRef>1</NtryRef><Amt>3.5</Amt><CdtDbtInd>X</CdtDbtInd></Ntry>" +
+				"<Ntry><NtryRef>2</NtryRef><Amt>ten</Amt><CdtDbtInd>CRDT</CdtDbtInd></Ntry></Document>"
+
+			// action
+			records := readAll(t, NewCAMTReader(strings.NewReader(input)))
+
+			// assertion
+			assert.Equal(t, []Record{
+				{Line: 1, ID: "1", Err: &RecordError{Column: "CdtDbtInd", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}},
+				{Line: 1, ID: "2", Err: &RecordError{Column: "Amt", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}},
+			}, records)
+		})
+		t.Run("Malformed XML", func(t *testing.T) {
+			reader := NewCAMTReader(strings.NewReader("<Document><Ntry><Amt>3.5</Ntry>"))
+
+			// action
+			record, err := reader.Read()
+
+			// assertion
+			assert.Nil(t, record)
+			assert.ErrorIs(t, err, errors.ErrInvalidStatement)
+		})
+	})
+}
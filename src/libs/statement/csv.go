@@ -0,0 +1,266 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"stori-service/src/libs/errors"
+	"strconv"
+	"strings"
+)
+
+/*
+csvReader reads CSV statements with the layout of a profile, columns are found by the name in the header
+*/
+type csvReader struct {
+	scanner     *lineScanner
+	profile     *Profile
+	indexes     map[string]int
+	columnCount int
+	line        int
+}
+
+/*
+NewCSVReader reads the header of the file and returns a Reader for its lines,
+it fails when the header doesn't have the columns of the profile
+*/
+func NewCSVReader(file io.Reader, profile *Profile) (Reader, error) {
+	r := &csvReader{
+		scanner: newLineScanner(file),
+		profile: profile,
+	}
+	if err := r.readHeader(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+/*
+Read returns the next line of the file, blank lines are skipped
+*/
+func (r *csvReader) Read() (*Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := r.scanner.Text()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		record := &Record{Line: r.line}
+		fields, err := r.split(text)
+		if err != nil || len(fields) != r.columnCount {
+			record.Err = &RecordError{
+				MessageID:    "IMPORT_LINE.WRONG_COLUMNS",
+				TemplateData: map[string]interface{}{"Count": r.columnCount},
+			}
+			return record, nil
+		}
+		record.ID = r.field(fields, r.profile.IDColumn)
+		record.Date = r.field(fields, r.profile.DateColumn)
+		record.Amount, record.Err = r.amount(fields)
+		if r.hasColumn(r.profile.CurrencyColumn) {
+			record.Currency = strings.ToUpper(r.field(fields, r.profile.CurrencyColumn))
+		}
+		if r.hasColumn(r.profile.DescriptionColumn) {
+			record.Description = r.field(fields, r.profile.DescriptionColumn)
+		}
+		if r.hasColumn(r.profile.MerchantColumn) {
+			record.Merchant = r.field(fields, r.profile.MerchantColumn)
+		}
+		if r.hasColumn(r.profile.CategoryColumn) {
+			record.Category = r.field(fields, r.profile.CategoryColumn)
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+/*
+Columns returns the names of the profile columns
+*/
+func (r *csvReader) Columns() Columns {
+	return Columns{
+		ID:          r.profile.IDColumn,
+		Date:        r.profile.DateColumn,
+		Amount:      r.profile.amountColumn(),
+		Currency:    r.profile.CurrencyColumn,
+		Description: r.profile.DescriptionColumn,
+		Merchant:    r.profile.MerchantColumn,
+		Category:    r.profile.CategoryColumn,
+	}
+}
+
+/*
+readHeader reads the first line and saves the position of each column
+*/
+func (r *csvReader) readHeader() error {
+	var header []string
+	if r.scanner.Scan() {
+		r.line++
+		header, _ = r.split(strings.TrimPrefix(r.scanner.Text(), "\uFEFF")) // without the BOM of some editors
+	}
+	if err := r.scanner.Err(); err != nil {
+		return err
+	}
+	r.columnCount = len(header)
+	r.indexes = make(map[string]int, len(header))
+	for i, name := range header {
+		r.indexes[normalizeColumn(name)] = i
+	}
+	required := []string{r.profile.IDColumn, r.profile.DateColumn}
+	if r.profile.hasDebitAndCredit() {
+		required = append(required, r.profile.DebitColumn, r.profile.CreditColumn)
+	} else {
+		required = append(required, r.profile.AmountColumn)
+	}
+	var missing []string
+	for _, column := range required {
+		if _, ok := r.indexes[normalizeColumn(column)]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.ErrMissingColumns.WithTemplate(map[string]interface{}{
+			"Columns": strings.Join(missing, ", "),
+		})
+	}
+	return nil
+}
+
+/*
+split splits a line by the profile delimiter, quoted values can have the delimiter inside
+*/
+func (r *csvReader) split(text string) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = r.profile.Delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	return reader.Read()
+}
+
+/*
+hasColumn tells if the header has the optional column
+*/
+func (r *csvReader) hasColumn(column string) bool {
+	if column == "" {
+		return false
+	}
+	_, ok := r.indexes[normalizeColumn(column)]
+	return ok
+}
+
+/*
+field returns the trimmed value of the column
+*/
+func (r *csvReader) field(fields []string, column string) string {
+	return strings.TrimSpace(fields[r.indexes[normalizeColumn(column)]])
+}
+
+/*
+amount returns the normalized amount of the line, taking it from the debit and credit columns
+when the profile has them, and applying the sign convention of the profile
+*/
+func (r *csvReader) amount(fields []string) (string, *RecordError) {
+	if !r.profile.hasDebitAndCredit() {
+		amount, ok := normalizeAmount(r.field(fields, r.profile.AmountColumn), r.profile.DecimalSeparator)
+		if !ok {
+			return "", &RecordError{Column: r.profile.AmountColumn, MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+		}
+		if r.profile.SignConvention == InvertedAmounts {
+			amount = negate(amount)
+		}
+		return amount, nil
+	}
+	debit := r.field(fields, r.profile.DebitColumn)
+	credit := r.field(fields, r.profile.CreditColumn)
+	switch {
+	case debit != "" && credit != "":
+		return "", &RecordError{Column: r.profile.amountColumn(), MessageID: "IMPORT_LINE.DEBIT_AND_CREDIT"}
+	case debit != "":
+		amount, ok := normalizeAmount(debit, r.profile.DecimalSeparator)
+		if !ok {
+			return "", &RecordError{Column: r.profile.DebitColumn, MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+		}
+		return "-" + strings.TrimLeft(amount, "+-"), nil
+	case credit != "":
+		amount, ok := normalizeAmount(credit, r.profile.DecimalSeparator)
+		if !ok {
+			return "", &RecordError{Column: r.profile.CreditColumn, MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+		}
+		return strings.TrimLeft(amount, "+-"), nil
+	}
+	return "", &RecordError{Column: r.profile.amountColumn(), MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+}
+
+/*
+normalizeColumn returns the column name to be compared without case and spaces
+*/
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+/*
+normalizeAmount removes the thousands separator and leaves '.' as decimal separator,
+amounts between parentheses are negative as in accounting. It returns false when it's not a number
+*/
+func normalizeAmount(value string, decimalSeparator rune) (string, bool) {
+	negative := strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+	if negative {
+		value = value[1 : len(value)-1]
+	}
+	thousandsSeparator := ","
+	if decimalSeparator == ',' {
+		thousandsSeparator = "."
+	}
+	value = strings.ReplaceAll(value, " ", "")
+	if !validThousandsGroups(value, thousandsSeparator, decimalSeparator) {
+		return "", false
+	}
+	value = strings.ReplaceAll(value, thousandsSeparator, "")
+	value = strings.Replace(value, string(decimalSeparator), ".", 1)
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return "", false
+	}
+	if negative {
+		value = negate(value)
+	}
+	return value, true
+}
+
+/*
+validThousandsGroups checks the thousands separator is only used in the integer part of the amount
+and between groups of 3 digits, like 1,234,567.50, so 10,50 isn't read as 1050
+*/
+func validThousandsGroups(value string, thousandsSeparator string, decimalSeparator rune) bool {
+	integer := strings.TrimLeft(value, "+-")
+	if i := strings.IndexRune(integer, decimalSeparator); i >= 0 {
+		if strings.Contains(integer[i+1:], thousandsSeparator) {
+			return false
+		}
+		integer = integer[:i]
+	}
+	groups := strings.Split(integer, thousandsSeparator)
+	if len(groups) == 1 {
+		return true
+	}
+	if len(groups[0]) == 0 || len(groups[0]) > 3 {
+		return false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+negate changes the sign of a normalized amount
+*/
+func negate(amount string) string {
+	if strings.HasPrefix(amount, "-") {
+		return amount[1:]
+	}
+	return "-" + strings.TrimPrefix(amount, "+")
+}
@@ -0,0 +1,252 @@
+package statement
+
+import (
+	"io"
+	"stori-service/src/libs/errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+readAll reads all the records of the reader
+*/
+func readAll(t *testing.T, reader Reader) []Record {
+	var records []Record
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records
+		}
+		assert.NoError(t, err)
+		records = append(records, *record)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			profile  string
+			input    string
+			columns  Columns
+			expected []Record
+		}{
+			{
+				name:    "Default profile",
+				profile: DefaultProfile,
+				input:   "id,date,transaction\n1,5/25,+3.5\n2,3/20,-1.6",
+				columns: Columns{ID: "id", Date: "date", Amount: "transaction", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "5/25", Amount: "+3.5"},
+					{Line: 3, ID: "2", Date: "3/20", Amount: "-1.6"},
+				},
+			},
+			{
+				name:    "Columns in another order, with spaces, upper case and BOM",
+				profile: DefaultProfile,
+				input:   "\uFEFFTransaction, Date ,ID\n+3.5,5/25,1\n\n-1.6,3/20,2\n",
+				columns: Columns{ID: "id", Date: "date", Amount: "transaction", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "5/25", Amount: "+3.5"},
+					{Line: 4, ID: "2", Date: "3/20", Amount: "-1.6"},
+				},
+			},
+			{
+				name:    "Extra columns",
+				profile: DefaultProfile,
+				input:   "id,notes,date,transaction\n1,\"Coffee, milk\",5/25,-3.5",
+				columns: Columns{ID: "id", Date: "date", Amount: "transaction", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "5/25", Amount: "-3.5"},
+				},
+			},
+			{
+				name:    "Currency column",
+				profile: DefaultProfile,
+				input:   "id,date,transaction,currency\n1,5/25,+3.5,mxn\n2,3/20,-1.6,USD\n3,3/21,-2,",
+				columns: Columns{ID: "id", Date: "date", Amount: "transaction", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "5/25", Amount: "+3.5", Currency: "MXN"},
+					{Line: 3, ID: "2", Date: "3/20", Amount: "-1.6", Currency: "USD"},
+					{Line: 4, ID: "3", Date: "3/21", Amount: "-2"},
+				},
+			},
+			{
+				name:    "Description, merchant and category columns",
+				profile: DefaultProfile,
+				input:   "id,date,transaction,description,merchant,category\n1,5/25,-3.5,\"Coffee, milk\", Corner Cafe ,Food\n2,3/20,+100,,,",
+				columns: Columns{ID: "id", Date: "date", Amount: "transaction", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "5/25", Amount: "-3.5", Description: "Coffee, milk", Merchant: "Corner Cafe", Category: "Food"},
+					{Line: 3, ID: "2", Date: "3/20", Amount: "+100"},
+				},
+			},
+			{
+				name:    "Semicolon and decimal comma",
+				profile: "latam",
+				input:   "id;fecha;monto\n1;25/05/2022;1.234,50\n2;26/05/2022;-0,5\n3;27/05/2022;(10,25)",
+				columns: Columns{ID: "id", Date: "fecha", Amount: "monto", Currency: "moneda", Description: "descripcion", Merchant: "comercio", Category: "categoria"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "25/05/2022", Amount: "1234.50"},
+					{Line: 3, ID: "2", Date: "26/05/2022", Amount: "-0.5"},
+					{Line: 4, ID: "3", Date: "27/05/2022", Amount: "-10.25"},
+				},
+			},
+			{
+				name:    "Debit and credit columns",
+				profile: "debit_credit",
+				input:   "reference,date,debit,credit\n1,2022-05-25,12.5,\n2,2022-05-26,,\"1,000.75\"\n3,2022-05-27,\"-1,234,567\",",
+				columns: Columns{ID: "reference", Date: "date", Amount: "debit/credit", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "2022-05-25", Amount: "-12.5"},
+					{Line: 3, ID: "2", Date: "2022-05-26", Amount: "1000.75"},
+					{Line: 4, ID: "3", Date: "2022-05-27", Amount: "-1234567"},
+				},
+			},
+			{
+				name:    "Inverted amounts",
+				profile: "credit_card",
+				input:   "id,date,amount\n1,05/25/2022,25.5\n2,05/26/2022,-10",
+				columns: Columns{ID: "id", Date: "date", Amount: "amount", Currency: "currency", Description: "description", Merchant: "merchant", Category: "category"},
+				expected: []Record{
+					{Line: 2, ID: "1", Date: "05/25/2022", Amount: "-25.5"},
+					{Line: 3, ID: "2", Date: "05/26/2022", Amount: "10"},
+				},
+			},
+		}
+		for _, tC := range testCases {
+			t.Run(tC.name, func(t *testing.T) {
+				profile, _ := GetProfile(tC.profile)
+
+				reader, err := NewCSVReader(strings.NewReader(tC.input), profile)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tC.columns, reader.Columns())
+				assert.Equal(t, tC.expected, readAll(t, reader))
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Invalid lines", func(t *testing.T) {
+			testCases := []struct {
+				name     string
+				profile  string
+				input    string
+				expected *RecordError
+			}{
+				{
+					name:    "Less columns than the header",
+					profile: DefaultProfile,
+					input:   "id,date,transaction\n1,5/25",
+					expected: &RecordError{
+						MessageID:    "IMPORT_LINE.WRONG_COLUMNS",
+						TemplateData: map[string]interface{}{"Count": 3},
+					},
+				},
+				{
+					name:    "More columns than the header",
+					profile: DefaultProfile,
+					input:   "id,date,transaction\n1,5/25,-1.6,1.6",
+					expected: &RecordError{
+						MessageID:    "IMPORT_LINE.WRONG_COLUMNS",
+						TemplateData: map[string]interface{}{"Count": 3},
+					},
+				},
+				{
+					name:     "Invalid amount",
+					profile:  DefaultProfile,
+					input:    "id,date,transaction\n1,5/25,a",
+					expected: &RecordError{Column: "transaction", MessageID: "IMPORT_LINE.INVALID_AMOUNT"},
+				},
+				{
+					name:     "Decimal comma with the decimal point profile",
+					profile:  DefaultProfile,
+					input:    "id,date,transaction\n1,5/25,\"10,50\"",
+					expected: &RecordError{Column: "transaction", MessageID: "IMPORT_LINE.INVALID_AMOUNT"},
+				},
+				{
+					name:     "Thousands separator in the decimals",
+					profile:  "latam",
+					input:    "id;fecha;monto\n1;25/05/2022;1,234.50",
+					expected: &RecordError{Column: "monto", MessageID: "IMPORT_LINE.INVALID_AMOUNT"},
+				},
+				{
+					name:     "Invalid debit",
+					profile:  "debit_credit",
+					input:    "reference,date,debit,credit\n1,2022-05-25,a,",
+					expected: &RecordError{Column: "debit", MessageID: "IMPORT_LINE.INVALID_AMOUNT"},
+				},
+				{
+					name:     "Invalid credit",
+					profile:  "debit_credit",
+					input:    "reference,date,debit,credit\n1,2022-05-25,,a",
+					expected: &RecordError{Column: "credit", MessageID: "IMPORT_LINE.INVALID_AMOUNT"},
+				},
+				{
+					name:     "Debit and credit",
+					profile:  "debit_credit",
+					input:    "reference,date,debit,credit\n1,2022-05-25,1,2",
+					expected: &RecordError{Column: "debit/credit", MessageID: "IMPORT_LINE.DEBIT_AND_CREDIT"},
+				},
+				{
+					name:     "Without debit nor credit",
+					profile:  "debit_credit",
+					input:    "reference,date,debit,credit\n1,2022-05-25,,",
+					expected: &RecordError{Column: "debit/credit", MessageID: "IMPORT_LINE.INVALID_AMOUNT"},
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					profile, _ := GetProfile(tC.profile)
+					reader, _ := NewCSVReader(strings.NewReader(tC.input), profile)
+
+					records := readAll(t, reader)
+
+					assert.Equal(t, 1, len(records))
+					assert.Equal(t, 2, records[0].Line)
+					assert.Equal(t, tC.expected, records[0].Err)
+				})
+			}
+		})
+		t.Run("Invalid header", func(t *testing.T) {
+			testCases := []struct {
+				name     string
+				profile  string
+				input    string
+				expected string
+			}{
+				{
+					name:     "Missing a column",
+					profile:  DefaultProfile,
+					input:    "id,fecha,transaction\n1,5/25,-1.6",
+					expected: "date",
+				},
+				{
+					name:     "Missing debit and credit",
+					profile:  "debit_credit",
+					input:    "reference,date,amount\n1,2022-05-25,-1.6",
+					expected: "debit, credit",
+				},
+				{
+					name:     "Empty file",
+					profile:  DefaultProfile,
+					input:    "",
+					expected: "id, date, transaction",
+				},
+			}
+			for _, tC := range testCases {
+				t.Run(tC.name, func(t *testing.T) {
+					profile, _ := GetProfile(tC.profile)
+
+					reader, err := NewCSVReader(strings.NewReader(tC.input), profile)
+
+					assert.Nil(t, reader)
+					assert.ErrorIs(t, err, errors.ErrMissingColumns)
+					assert.Equal(t, map[string]interface{}{"Columns": tC.expected}, err.(errors.MyError).GetData())
+				})
+			}
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package statement
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+/*
+lineScanner reads a file line by line like bufio.Scanner but without its 64KB limit per line,
+the line breaks (\n or \r\n) are not included in the text
+*/
+type lineScanner struct {
+	reader *bufio.Reader
+	text   string
+	err    error
+}
+
+/*
+newLineScanner returns a lineScanner of the file
+*/
+func newLineScanner(file io.Reader) *lineScanner {
+	return &lineScanner{reader: bufio.NewReader(file)}
+}
+
+/*
+Scan reads the next line, it returns false at the end of the file or when the read fails
+*/
+func (s *lineScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		s.err = err
+		if err != io.EOF || line == "" {
+			return false
+		}
+	}
+	s.text = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	return true
+}
+
+/*
+Text returns the last line read
+*/
+func (s *lineScanner) Text() string {
+	return s.text
+}
+
+/*
+Err returns the error of the read, nil at the end of the file
+*/
+func (s *lineScanner) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
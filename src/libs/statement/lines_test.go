@@ -0,0 +1,39 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineScanner(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		longLine := strings.Repeat("a", 100*1024)
+		testCases := []struct {
+			name     string
+			input    string
+			expected []string
+		}{
+			{name: "Empty file", input: "", expected: nil},
+			{name: "Last line without line break", input: "a\r\nb\n\nc", expected: []string{"a", "b", "", "c"}},
+			{name: "Last line with line break", input: "a\nb\n", expected: []string{"a", "b"}},
+			{name: "Line longer than 64KB", input: "id\n" + longLine, expected: []string{"id", longLine}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				scanner := newLineScanner(strings.NewReader(tc.input))
+
+				// action
+				var lines []string
+				for scanner.Scan() {
+					lines = append(lines, scanner.Text())
+				}
+
+				// assertion
+				assert.NoError(t, scanner.Err())
+				assert.Equal(t, tc.expected, lines)
+			})
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package statement
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+/*
+ofxReader reads the STMTTRN transactions of OFX statements, both the SGML (1.x) and XML (2.x) versions.
+Elements are read as tag and value pairs, so closing tags are optional as in SGML.
+The currency of the transactions is the CURDEF of their statement, the payee NAME is the merchant
+and the MEMO the description
+*/
+type ofxReader struct {
+	reader   *bufio.Reader
+	line     int
+	currency string
+}
+
+/*
+ofxElement is a tag with the text that follows it until the next tag
+*/
+type ofxElement struct {
+	tag   string
+	value string
+	line  int
+}
+
+/*
+NewOFXReader returns a Reader for the transactions of an OFX statement
+*/
+func NewOFXReader(file io.Reader) Reader {
+	return &ofxReader{reader: bufio.NewReader(file), line: 1}
+}
+
+/*
+Read returns the next transaction of the statement, the line is the one of its STMTTRN tag
+*/
+func (r *ofxReader) Read() (*Record, error) {
+	var record *Record
+	var fitID, posted, amount, name, memo string
+	for {
+		element, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch element.tag {
+		case "CURDEF":
+			r.currency = strings.ToUpper(element.value)
+		case "STMTTRN":
+			record = &Record{Line: element.line}
+			fitID, posted, amount, name, memo = "", "", "", "", ""
+		case "FITID":
+			fitID = element.value
+		case "DTPOSTED":
+			posted = element.value
+		case "TRNAMT":
+			amount = element.value
+		case "NAME":
+			name = element.value
+		case "MEMO":
+			memo = element.value
+		case "/STMTTRN":
+			if record == nil {
+				continue
+			}
+			setReference(record, fitID)
+			record.Date = ofxDate(posted)
+			record.Currency = r.currency
+			record.Merchant = name
+			record.Description = memo
+			var ok bool
+			if record.Amount, ok = normalizeAmount(amount, decimalSeparatorOf(amount)); !ok {
+				record.Err = &RecordError{Column: "TRNAMT", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+			}
+			return record, nil
+		}
+	}
+}
+
+/*
+Columns returns the OFX tags of the id, date, amount, currency, description and merchant
+*/
+func (r *ofxReader) Columns() Columns {
+	return Columns{ID: "FITID", Date: "DTPOSTED", Amount: "TRNAMT", Currency: "CURDEF", Description: "MEMO", Merchant: "NAME"}
+}
+
+/*
+next returns the next element of the file, the tag is in upper case
+and the value is trimmed. It returns io.EOF when there are no more tags
+*/
+func (r *ofxReader) next() (*ofxElement, error) {
+	if _, err := r.readUntil('<'); err != nil {
+		return nil, err
+	}
+	element := &ofxElement{line: r.line}
+	tag, err := r.readUntil('>')
+	if err != nil {
+		return nil, err
+	}
+	element.tag = strings.ToUpper(strings.TrimSpace(tag))
+	value, err := r.readUntil('<')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err == nil {
+		r.reader.UnreadRune() // the '<' belongs to the next tag
+	}
+	element.value = strings.TrimSpace(value)
+	return element, nil
+}
+
+/*
+readUntil reads the text before the delimiter, consuming it, and counts the lines
+*/
+func (r *ofxReader) readUntil(delimiter rune) (string, error) {
+	var text strings.Builder
+	for {
+		char, _, err := r.reader.ReadRune()
+		if err != nil {
+			return text.String(), err
+		}
+		if char == delimiter {
+			return text.String(), nil
+		}
+		if char == '\n' {
+			r.line++
+		}
+		text.WriteRune(char)
+	}
+}
+
+/*
+ofxDate returns the date part of an OFX datetime (YYYYMMDDHHMMSS.XXX[gmt offset:tz name]) as YYYY-MM-DD,
+or the value as it is when it doesn't start with a date
+*/
+func ofxDate(value string) string {
+	if len(value) < 8 || strings.IndexFunc(value[:8], func(char rune) bool { return char < '0' || char > '9' }) != -1 {
+		return value
+	}
+	return value[:4] + "-" + value[4:6] + "-" + value[6:8]
+}
+
+/*
+decimalSeparatorOf returns ',' when the amount only has commas, like 10,50, and '.' otherwise
+*/
+func decimalSeparatorOf(amount string) rune {
+	if strings.Contains(amount, ",") && !strings.Contains(amount, ".") {
+		return ','
+	}
+	return '.'
+}
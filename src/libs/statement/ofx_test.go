@@ -0,0 +1,85 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOFXReader(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			input    string
+			expected []Record
+		}{
+			{
+				name: "SGML statement",
+				input: strings.Join([]string{
+					"OFXHEADER:100",
+					"DATA:OFXSGML",
+					"",
+					"<OFX>",
+					"<BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>usd<BANKTRANLIST>",
+					"<STMTTRN>",
+					"<TRNTYPE>CREDIT",
+					"<DTPOSTED>20220525120000.000[-3:ART]",
+					"<TRNAMT>3.5",
+					"<FITID>1",
+					"</STMTTRN>",
+					"<STMTTRN>",
+					"<TRNTYPE>DEBIT",
+					"<DTPOSTED>20220320",
+					"<TRNAMT>-1,6",
+					"<FITID>2",
+					"<NAME>Super Market",
+					"<MEMO>Weekly shopping",
+					"</STMTTRN>",
+					"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1>",
+					"</OFX>",
+				}, "\n"),
+				expected: []Record{
+					{Line: 6, ID: "1", Date: "2022-05-25", Amount: "3.5", Currency: "USD"},
+					{Line: 12, ID: "2", Date: "2022-03-20", Amount: "-1.6", Currency: "USD", Description: "Weekly shopping", Merchant: "Super Market"},
+				},
+			},
+			{
+				name: "XML statement with text ids",
+				input: strings.Join([]string{
+					`<?xml version="1.0" encoding="UTF-8"?>`,
+					`<?OFX OFXHEADER="200" VERSION="220"?>`,
+					"<OFX><BANKTRANLIST>",
+					"<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20220525</DTPOSTED><TRNAMT>-10.00</TRNAMT><FITID>AB-1</FITID></STMTTRN>",
+					"</BANKTRANLIST></OFX>",
+				}, "\n"),
+				expected: []Record{
+					{Line: 4, ID: numericID("AB-1"), Reference: "AB-1", Date: "2022-05-25", Amount: "-10.00"},
+				},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				// action
+				reader := NewOFXReader(strings.NewReader(tc.input))
+
+				// assertion
+				assert.Equal(t, Columns{ID: "FITID", Date: "DTPOSTED", Amount: "TRNAMT", Currency: "CURDEF", Description: "MEMO", Merchant: "NAME"}, reader.Columns())
+				assert.Equal(t, tc.expected, readAll(t, reader))
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Invalid values", func(t *testing.T) {
+			input := "<OFX><STMTTRN><DTPOSTED>25/05<TRNAMT>ten<FITID>1</STMTTRN></OFX>"
+
+			// action
+			records := readAll(t, NewOFXReader(strings.NewReader(input)))
+
+			// assertion
+			assert.Equal(t, []Record{
+				{Line: 1, ID: "1", Date: "25/05", Err: &RecordError{Column: "TRNAMT", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}},
+			}, records)
+		})
+	})
+}
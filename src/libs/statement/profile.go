@@ -0,0 +1,143 @@
+package statement
+
+import (
+	"sort"
+	"stori-service/src/libs/errors"
+	"stori-service/src/utils/constant"
+	"strings"
+)
+
+// Sign conventions of the amounts
+const (
+	SignedAmounts   = "signed"   // negative amounts are outcomes
+	InvertedAmounts = "inverted" // negative amounts are incomes, as in credit card statements
+)
+
+// DefaultProfile is the name of the profile used when the import doesn't set one
+const DefaultProfile = "default"
+
+/*
+Profile describes the layout of the CSV files of a bank. When DebitColumn and CreditColumn are set
+the amount is taken from them (debits are outcomes) instead of AmountColumn. CurrencyColumn is optional
+in the files, without it the movements are in the currency of the import. The description, merchant
+and category columns are optional too
+*/
+type Profile struct {
+	Name              string
+	Delimiter         rune
+	DecimalSeparator  rune
+	DateFormat        string
+	IDColumn          string
+	DateColumn        string
+	AmountColumn      string
+	DebitColumn       string
+	CreditColumn      string
+	CurrencyColumn    string
+	DescriptionColumn string
+	MerchantColumn    string
+	CategoryColumn    string
+	SignConvention    string
+}
+
+var profiles = map[string]Profile{
+	DefaultProfile: {
+		Name:              DefaultProfile,
+		Delimiter:         ',',
+		DecimalSeparator:  '.',
+		DateFormat:        constant.DateFormatMonthDay,
+		IDColumn:          "id",
+		DateColumn:        "date",
+		AmountColumn:      "transaction",
+		CurrencyColumn:    "currency",
+		DescriptionColumn: "description",
+		MerchantColumn:    "merchant",
+		CategoryColumn:    "category",
+		SignConvention:    SignedAmounts,
+	},
+	"latam": {
+		Name:              "latam",
+		Delimiter:         ';',
+		DecimalSeparator:  ',',
+		DateFormat:        constant.DateFormatDayMonthYear,
+		IDColumn:          "id",
+		DateColumn:        "fecha",
+		AmountColumn:      "monto",
+		CurrencyColumn:    "moneda",
+		DescriptionColumn: "descripcion",
+		MerchantColumn:    "comercio",
+		CategoryColumn:    "categoria",
+		SignConvention:    SignedAmounts,
+	},
+	"debit_credit": {
+		Name:              "debit_credit",
+		Delimiter:         ',',
+		DecimalSeparator:  '.',
+		DateFormat:        constant.DateFormatISO,
+		IDColumn:          "reference",
+		DateColumn:        "date",
+		DebitColumn:       "debit",
+		CreditColumn:      "credit",
+		CurrencyColumn:    "currency",
+		DescriptionColumn: "description",
+		MerchantColumn:    "merchant",
+		CategoryColumn:    "category",
+		SignConvention:    SignedAmounts,
+	},
+	"credit_card": {
+		Name:              "credit_card",
+		Delimiter:         ',',
+		DecimalSeparator:  '.',
+		DateFormat:        constant.DateFormatMonthDayYear,
+		IDColumn:          "id",
+		DateColumn:        "date",
+		AmountColumn:      "amount",
+		CurrencyColumn:    "currency",
+		DescriptionColumn: "description",
+		MerchantColumn:    "merchant",
+		CategoryColumn:    "category",
+		SignConvention:    InvertedAmounts,
+	},
+}
+
+/*
+GetProfile returns the profile with the given name, the default one when it's empty
+*/
+func GetProfile(name string) (*Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, errors.ErrFieldValidation("profile", "oneof", strings.Join(ProfileNames(), " "))
+	}
+	return &profile, nil
+}
+
+/*
+ProfileNames returns the names of all the profiles sorted
+*/
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+hasDebitAndCredit returns true when the amount is split in debit and credit columns
+*/
+func (p *Profile) hasDebitAndCredit() bool {
+	return p.DebitColumn != "" && p.CreditColumn != ""
+}
+
+/*
+amountColumn returns the name of the amount column to report invalid lines
+*/
+func (p *Profile) amountColumn() string {
+	if p.hasDebitAndCredit() {
+		return p.DebitColumn + "/" + p.CreditColumn
+	}
+	return p.AmountColumn
+}
@@ -0,0 +1,60 @@
+package statement
+
+import (
+	"stori-service/src/utils/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProfile(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			input    string
+			expected string
+		}{
+			{
+				name:     "Empty name",
+				input:    "",
+				expected: DefaultProfile,
+			},
+			{
+				name:     "Existing profile",
+				input:    "latam",
+				expected: "latam",
+			},
+		}
+		for _, tC := range testCases {
+			t.Run(tC.name, func(t *testing.T) {
+				profile, err := GetProfile(tC.input)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tC.expected, profile.Name)
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Unknown profile", func(t *testing.T) {
+			profile, err := GetProfile("unknown")
+
+			assert.Error(t, err)
+			assert.Nil(t, profile)
+		})
+	})
+}
+
+func TestProfileNames(t *testing.T) {
+	assert.Equal(t, []string{"credit_card", "debit_credit", DefaultProfile, "latam"}, ProfileNames())
+}
+
+func TestProfiles(t *testing.T) {
+	for name, profile := range profiles {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, name, profile.Name)
+			assert.Contains(t, constant.DateLayouts, profile.DateFormat)
+			assert.Contains(t, []string{SignedAmounts, InvertedAmounts}, profile.SignConvention)
+			assert.NotEmpty(t, profile.amountColumn())
+		})
+	}
+}
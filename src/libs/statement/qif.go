@@ -0,0 +1,157 @@
+package statement
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+qifReader reads the transactions of QIF statements, one field per line that starts with its code
+and '^' at the end of each transaction. Account and option blocks are skipped. Occurrences counts
+the transactions read with the same values, to tell apart the ids of the repeated ones
+*/
+type qifReader struct {
+	scanner     *lineScanner
+	profile     *Profile
+	line        int
+	occurrences map[string]int
+}
+
+/*
+NewQIFReader returns a Reader for the transactions of a QIF statement, the order of day and month
+in the dates is the one of the profile date format
+*/
+func NewQIFReader(file io.Reader, profile *Profile) Reader {
+	return &qifReader{scanner: newLineScanner(file), profile: profile, occurrences: make(map[string]int)}
+}
+
+/*
+Read returns the next transaction of the statement, the line is the one of its first field
+*/
+func (r *qifReader) Read() (*Record, error) {
+	var record *Record
+	var number, date, amount, payee, memo, category string
+	skipping := false
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(strings.TrimPrefix(r.scanner.Text(), "\uFEFF"))
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "!") {
+			skipping = strings.EqualFold(text, "!Account") // the account block has its own fields
+			continue
+		}
+		if text == "^" {
+			if skipping || record == nil {
+				skipping = false
+				continue
+			}
+			return r.record(record, number, date, amount, payee, memo, category), nil
+		}
+		if skipping {
+			continue
+		}
+		if record == nil {
+			record = &Record{Line: r.line}
+		}
+		value := strings.TrimSpace(text[1:])
+		switch text[0] {
+		case 'N':
+			number = value
+		case 'D':
+			date = value
+		case 'T':
+			amount = value
+		case 'U':
+			if amount == "" {
+				amount = value
+			}
+		case 'P':
+			payee = value
+		case 'M':
+			memo = value
+		case 'L':
+			category = value
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if record != nil { // the last transaction without '^'
+		return r.record(record, number, date, amount, payee, memo, category), nil
+	}
+	return nil, io.EOF
+}
+
+/*
+Columns returns the QIF codes of the id, date, amount, description, merchant and category
+*/
+func (r *qifReader) Columns() Columns {
+	return Columns{ID: "N", Date: "D", Amount: "T", Description: "M", Merchant: "P", Category: "L"}
+}
+
+/*
+record fills the record with the fields of the transaction. QIF has no transaction id, the check number
+is used when it's an integer, otherwise the id is a hash of the values of the transaction and how many
+transactions with the same values came before it, so importing the same file again, or a new export of it
+with other transactions added, gives the same ids. The payee is the merchant and the memo the description
+*/
+func (r *qifReader) record(record *Record, number, date, amount, payee, memo, category string) *Record {
+	if _, err := strconv.Atoi(number); err == nil {
+		record.ID = number
+	} else {
+		values := strings.Join([]string{date, amount, payee, memo, number}, "|")
+		r.occurrences[values]++
+		record.ID = numericID(values + "|" + strconv.Itoa(r.occurrences[values]))
+	}
+	record.Date = qifDate(date, strings.HasPrefix(r.profile.DateFormat, "DD"))
+	record.Merchant = payee
+	record.Description = memo
+	record.Category = category
+	var ok bool
+	if record.Amount, ok = normalizeAmount(amount, r.profile.DecimalSeparator); !ok {
+		record.Err = &RecordError{Column: "T", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}
+	}
+	return record
+}
+
+/*
+qifDate returns a QIF date (like 5/25/2022, 05/25'22 or 25/05/22 with dayFirst) as YYYY-MM-DD,
+two digit years are from 2000. It returns the value as it is when it's not a date
+*/
+func qifDate(value string, dayFirst bool) string {
+	normalized := strings.NewReplacer(" ", "", "'", "/", "-", "/", ".", "/").Replace(value)
+	parts := strings.Split(normalized, "/")
+	if len(parts) != 3 {
+		return value
+	}
+	if len(parts[0]) == 4 { // already year first
+		parts[0], parts[2] = parts[2], parts[0]
+		dayFirst = true
+	}
+	day, month, year := parts[1], parts[0], parts[2]
+	if dayFirst {
+		day, month = parts[0], parts[1]
+	}
+	if len(year) == 2 {
+		year = "20" + year
+	}
+	for _, part := range []string{day, month, year} {
+		if _, err := strconv.Atoi(part); err != nil || part == "" {
+			return value
+		}
+	}
+	return year + "-" + padDatePart(month) + "-" + padDatePart(day)
+}
+
+/*
+padDatePart adds the leading zero to single digit days and months
+*/
+func padDatePart(part string) string {
+	if len(part) == 1 {
+		return "0" + part
+	}
+	return part
+}
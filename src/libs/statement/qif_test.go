@@ -0,0 +1,118 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQIFReader(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			profile  string
+			input    string
+			expected []Record
+		}{
+			{
+				name:    "Bank statement with check numbers",
+				profile: DefaultProfile,
+				input: strings.Join([]string{
+					"!Account",
+					"NChecking",
+					"TBank",
+					"^",
+					"!Type:Bank",
+					"D5/25'22",
+					"T3.50",
+					"N1",
+					"PSalary",
+					"^",
+					"D03/20/2022",
+					"T-1,000.60",
+					"N2",
+					"PSuper Market",
+					"MWeekly shopping",
+					"LGroceries",
+					"^",
+				}, "\n"),
+				expected: []Record{
+					{Line: 6, ID: "1", Date: "2022-05-25", Amount: "3.50", Merchant: "Salary"},
+					{Line: 11, ID: "2", Date: "2022-03-20", Amount: "-1000.60", Description: "Weekly shopping", Merchant: "Super Market", Category: "Groceries"},
+				},
+			},
+			{
+				name:    "Day first dates and decimal comma without '^' at the end",
+				profile: "latam",
+				input:   "!Type:Bank\nD25/05/2022\nT-1,6\nNATM",
+				expected: []Record{
+					{Line: 2, ID: numericID("25/05/2022|-1,6|||ATM|1"), Date: "2022-05-25", Amount: "-1.6"},
+				},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				profile, _ := GetProfile(tc.profile)
+
+				// action
+				reader := NewQIFReader(strings.NewReader(tc.input), profile)
+
+				// assertion
+				assert.Equal(t, Columns{ID: "N", Date: "D", Amount: "T", Description: "M", Merchant: "P", Category: "L"}, reader.Columns())
+				assert.Equal(t, tc.expected, readAll(t, reader))
+			})
+		}
+		t.Run("Keeping the ids of the transactions without number when others are added", func(t *testing.T) {
+			profile, _ := GetProfile(DefaultProfile)
+			coffee := "D03/20/2022\nT-3.50\nPCorner Cafe\n^\n"
+			statement := "!Type:Bank\n" + coffee + coffee + "D03/21/2022\nT100\nPSalary\n^\n"
+			reexported := "!Type:Bank\nD03/19/2022\nT-20\nPBus\n^\n" + strings.TrimPrefix(statement, "!Type:Bank\n")
+
+			// action
+			records := readAll(t, NewQIFReader(strings.NewReader(statement), profile))
+			reexportedRecords := readAll(t, NewQIFReader(strings.NewReader(reexported), profile))
+
+			// assertion
+			assert.Len(t, records, 3)
+			assert.Len(t, reexportedRecords, 4)
+			assert.NotEqual(t, records[0].ID, records[1].ID) // the same coffee twice
+			for index, record := range records {
+				assert.Equal(t, record.ID, reexportedRecords[index+1].ID)
+			}
+		})
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Invalid values", func(t *testing.T) {
+			profile, _ := GetProfile(DefaultProfile)
+
+			// action
+			records := readAll(t, NewQIFReader(strings.NewReader("!Type:Bank\nDyesterday\nTten\nN1\n^"), profile))
+
+			// assertion
+			assert.Equal(t, []Record{
+				{Line: 2, ID: "1", Date: "yesterday", Err: &RecordError{Column: "T", MessageID: "IMPORT_LINE.INVALID_AMOUNT"}},
+			}, records)
+		})
+	})
+}
+
+func TestQIFDate(t *testing.T) {
+	testCases := []struct {
+		value    string
+		dayFirst bool
+		expected string
+	}{
+		{value: "5/25/2022", expected: "2022-05-25"},
+		{value: " 5/ 3'22", expected: "2022-05-03"},
+		{value: "25.05.2022", dayFirst: true, expected: "2022-05-25"},
+		{value: "2022-05-25", expected: "2022-05-25"},
+		{value: "5/25", expected: "5/25"},
+		{value: "a/b/c", expected: "a/b/c"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, qifDate(tc.value, tc.dayFirst))
+		})
+	}
+}
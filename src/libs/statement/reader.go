@@ -0,0 +1,134 @@
+package statement
+
+import (
+	"bufio"
+	"bytes"
+	"hash/fnv"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Statement formats
+const (
+	CSVFormat  = "csv"
+	OFXFormat  = "ofx"
+	QIFFormat  = "qif"
+	CAMTFormat = "camt053"
+)
+
+// sniffSize is how many bytes are read from the start of the file to guess its format
+const sniffSize = 1024
+
+var extensions = map[string]string{
+	".csv":  CSVFormat,
+	".txt":  CSVFormat,
+	".ofx":  OFXFormat,
+	".qfx":  OFXFormat,
+	".qif":  QIFFormat,
+	".xml":  CAMTFormat,
+	".camt": CAMTFormat,
+	".053":  CAMTFormat,
+}
+
+/*
+NewReader returns the Reader of the statement format. When format is empty it is picked by the
+extension of the file name and, if the name doesn't tell it, by the content of the file
+*/
+func NewReader(file io.Reader, name string, format string, profile *Profile) (Reader, error) {
+	buffered := bufio.NewReader(file)
+	if format == "" {
+		format = FormatFromName(name)
+	}
+	if format == "" {
+		head, _ := buffered.Peek(sniffSize) // a shorter file returns its whole content with io.EOF
+		format = sniffFormat(head)
+	}
+	switch format {
+	case OFXFormat:
+		return NewOFXReader(buffered), nil
+	case QIFFormat:
+		return NewQIFReader(buffered, profile), nil
+	case CAMTFormat:
+		return NewCAMTReader(buffered), nil
+	}
+	return NewCSVReader(buffered, profile)
+}
+
+/*
+FormatFromName returns the format of the file extension or empty when it's unknown
+*/
+func FormatFromName(name string) string {
+	return extensions[strings.ToLower(filepath.Ext(name))]
+}
+
+/*
+Extension returns the file extension of the format
+*/
+func Extension(format string) string {
+	switch format {
+	case OFXFormat:
+		return ".ofx"
+	case QIFFormat:
+		return ".qif"
+	case CAMTFormat:
+		return ".xml"
+	}
+	return ".csv"
+}
+
+/*
+Formats returns the supported formats sorted
+*/
+func Formats() []string {
+	formats := []string{CSVFormat, OFXFormat, QIFFormat, CAMTFormat}
+	sort.Strings(formats)
+	return formats
+}
+
+/*
+sniffFormat guesses the format by the start of the file, CSV is the default
+*/
+func sniffFormat(head []byte) string {
+	head = bytes.TrimPrefix(head, []byte("\uFEFF"))
+	trimmed := bytes.TrimSpace(head)
+	upper := bytes.ToUpper(trimmed)
+	switch {
+	case bytes.HasPrefix(upper, []byte("OFXHEADER")), bytes.Contains(upper, []byte("<OFX>")):
+		return OFXFormat
+	case bytes.HasPrefix(upper, []byte("!TYPE:")), bytes.HasPrefix(upper, []byte("!ACCOUNT")), bytes.HasPrefix(upper, []byte("!OPTION:")):
+		return QIFFormat
+	case bytes.HasPrefix(trimmed, []byte("<")) && (bytes.Contains(trimmed, []byte("camt.053")) || bytes.Contains(trimmed, []byte("BkToCstmrStmt"))):
+		return CAMTFormat
+	}
+	return CSVFormat
+}
+
+/*
+numericID returns the id when it's an integer, otherwise a positive 63-bit integer hash of it.
+Movement IDs are integers while OFX, QIF and CAMT references are free text
+*/
+func numericID(id string) string {
+	if id == "" {
+		return ""
+	}
+	if _, err := strconv.Atoi(id); err == nil {
+		return id
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(id))
+	return strconv.FormatUint(hash.Sum64()&0x7fffffffffffffff, 10)
+}
+
+/*
+setReference sets the ID of the record from the reference of the bank, when the reference isn't an integer
+the ID is its hash and the reference is kept so different references with the same hash aren't duplicates
+*/
+func setReference(record *Record, reference string) {
+	record.ID = numericID(reference)
+	if record.ID != reference {
+		record.Reference = reference
+	}
+}
@@ -0,0 +1,71 @@
+package statement
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReader(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			fileName string
+			format   string
+			input    string
+			expected Reader
+		}{
+			{name: "CSV by extension", fileName: "customer_1.csv", input: "id,date,transaction", expected: &csvReader{}},
+			{name: "OFX by extension", fileName: "customer_1.QFX", input: "", expected: &ofxReader{}},
+			{name: "QIF by extension", fileName: "customer_1.qif", input: "", expected: &qifReader{}},
+			{name: "CAMT.053 by extension", fileName: "customer_1.xml", input: "", expected: &camtReader{}},
+			{name: "Format of the options", fileName: "customer_1.csv", format: QIFFormat, input: "", expected: &qifReader{}},
+			{name: "OFX by content", input: "OFXHEADER:100\n<OFX>", expected: &ofxReader{}},
+			{name: "OFX 2 by content", input: "<?xml version=\"1.0\"?>\n<?OFX OFXHEADER=\"200\"?>\n<OFX>", expected: &ofxReader{}},
+			{name: "QIF by content", input: "!Type:Bank\nD5/25/2022", expected: &qifReader{}},
+			{name: "CAMT.053 by content", input: "\uFEFF<?xml version=\"1.0\"?>\n<Document xmlns=\"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02\">", expected: &camtReader{}},
+			{name: "CSV by content", fileName: "upload", input: "id,date,transaction", expected: &csvReader{}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				profile, _ := GetProfile(DefaultProfile)
+
+				// action
+				reader, err := NewReader(strings.NewReader(tc.input), tc.fileName, tc.format, profile)
+
+				// assertion
+				assert.NoError(t, err)
+				assert.IsType(t, tc.expected, reader)
+			})
+		}
+	})
+}
+
+func TestNumericID(t *testing.T) {
+	assert.Equal(t, "", numericID(""))
+	assert.Equal(t, "15", numericID("15"))
+	assert.Equal(t, numericID("AB-1"), numericID("AB-1"))
+	assert.NotEqual(t, numericID("AB-1"), numericID("AB-2"))
+	assert.NotContains(t, numericID("AB-1"), "-")
+	// the 31-bit hash of these references was the same
+	assert.NotEqual(t, numericID("costarring"), numericID("liquid"))
+	_, err := strconv.Atoi(numericID("costarring"))
+	assert.Nil(t, err)
+}
+
+func TestSetReference(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Integer reference", func(t *testing.T) {
+			var record Record
+			setReference(&record, "15")
+			assert.Equal(t, Record{ID: "15"}, record)
+		})
+		t.Run("Text reference", func(t *testing.T) {
+			var record Record
+			setReference(&record, "AB-1")
+			assert.Equal(t, Record{ID: numericID("AB-1"), Reference: "AB-1"}, record)
+		})
+	})
+}
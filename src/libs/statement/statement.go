@@ -0,0 +1,52 @@
+package statement
+
+/*
+Reader reads the transactions of a statement one by one, it returns io.EOF when there are no more
+*/
+type Reader interface {
+	Read() (*Record, error)
+	Columns() Columns
+}
+
+/*
+Columns are the names that the id, date, amount, currency and texts have in the file, used to report invalid lines
+*/
+type Columns struct {
+	ID          string
+	Reference   string
+	Date        string
+	Amount      string
+	Currency    string
+	Description string
+	Merchant    string
+	Category    string
+}
+
+/*
+Record is a transaction of the statement with the values as they are in the file, except for the amount
+that uses '.' as decimal separator and is negative for outcomes. Currency is the ISO 4217 code in upper case,
+empty when the file doesn't have it. Description, Merchant and Category are optional texts, trimmed.
+Reference is the original text of the ID when the bank reference isn't an integer and the ID is its hash.
+Err is set when the line can't be read
+*/
+type Record struct {
+	Line        int
+	ID          string
+	Reference   string
+	Date        string
+	Amount      string
+	Currency    string
+	Description string
+	Merchant    string
+	Category    string
+	Err         *RecordError
+}
+
+/*
+RecordError is the column and the i18n message of the reason why a line can't be read
+*/
+type RecordError struct {
+	Column       string
+	MessageID    string
+	TemplateData map[string]interface{}
+}
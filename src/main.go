@@ -31,7 +31,12 @@ func SetupHandler() *http.Handler {
 		"X-pagination-page-count",
 		"X-pagination-current-page",
 		"X-pagination-page-size",
+		"X-pagination-next-cursor",
+		"X-pagination-prev-cursor",
 		"needs-action",
+		"Location",
+		"Deprecation",
+		"Link",
 	})
 
 	muxRouter.Use(handlers.CORS(originsOk, headersOk, methodsOk, exposeHeadersOk, credentialsOk))
@@ -0,0 +1,45 @@
+package balancefilter
+
+import (
+	"net/url"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/utils/constant"
+	"stori-service/src/utils/movementfilter"
+	"time"
+)
+
+/*
+GetBalanceFilterFromQuery receives a queryString from request, extracts at (RFC 3339 or YYYY-MM-DD), from and to (YYYY-MM-DD),
+then returns the balance filter. A day in at is its end, so the balance has all the movements of that day, and a missing at is now
+*/
+func GetBalanceFilterFromQuery(queryString url.Values) (*dto.BalanceFilter, error) {
+	at, err := parseAt(queryString.Get("at"))
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := movementfilter.GetDateRangeFromQuery(queryString)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BalanceFilter{At: at, From: from, To: to}, nil
+}
+
+/*
+parseAt returns the timestamp of the at param, the last microsecond of the day when it's a day,
+that's the precision of the dates in the database
+*/
+func parseAt(atStr string) (time.Time, error) {
+	if atStr == "" {
+		return time.Now(), nil
+	}
+	at, err := time.Parse(time.RFC3339Nano, atStr)
+	if err == nil {
+		return at, nil
+	}
+	day, err := time.Parse(constant.DateLayouts[constant.DateFormatISO], atStr)
+	if err != nil {
+		return time.Time{}, errors.ErrFieldValidation("at", "datetime", time.RFC3339)
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
@@ -0,0 +1,68 @@
+package balancefilter
+
+import (
+	"net/url"
+	"stori-service/src/libs/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceFilter(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Default values", func(t *testing.T) {
+			queryString := url.Values{}
+			result, err := GetBalanceFilterFromQuery(queryString)
+			assert.WithinDuration(t, time.Now(), result.At, time.Second)
+			assert.Nil(t, result.From)
+			assert.Nil(t, result.To)
+			assert.False(t, result.HasWindow())
+			assert.NoError(t, err)
+		})
+		t.Run("Timestamp", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("at", "2022-01-15T10:30:00-06:00")
+			result, err := GetBalanceFilterFromQuery(queryString)
+			assert.True(t, time.Date(2022, time.January, 15, 16, 30, 0, 0, time.UTC).Equal(result.At))
+			assert.NoError(t, err)
+		})
+		t.Run("Day and window", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("at", "2022-01-31")
+			queryString.Set("from", "2022-01-01")
+			queryString.Set("to", "2022-01-31")
+			result, err := GetBalanceFilterFromQuery(queryString)
+			assert.Equal(t, time.Date(2022, time.January, 31, 23, 59, 59, 999999000, time.UTC), result.At)
+			assert.Equal(t, time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), *result.From)
+			assert.Equal(t, time.Date(2022, time.January, 31, 0, 0, 0, 0, time.UTC), *result.To)
+			assert.True(t, result.HasWindow())
+			assert.NoError(t, err)
+		})
+	})
+	t.Run("Fail", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			query    url.Values
+			expected error
+		}{
+			{
+				name:     "Invalid at",
+				query:    url.Values{"at": []string{"01/31/2022"}},
+				expected: errors.ErrFieldValidation("at", "datetime", time.RFC3339),
+			},
+			{
+				name:     "To before from",
+				query:    url.Values{"from": []string{"2022-02-01"}, "to": []string{"2022-01-31"}},
+				expected: errors.ErrFieldValidation("to", "gtefield", "from"),
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				result, err := GetBalanceFilterFromQuery(testCase.query)
+				assert.Nil(t, result)
+				assert.EqualError(t, err, testCase.expected.Error())
+			})
+		}
+	})
+}
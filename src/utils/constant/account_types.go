@@ -0,0 +1,7 @@
+package constant
+
+//Constants for ledger account types
+const (
+	CustomerAccount = "customer"
+	ClearingAccount = "clearing"
+)
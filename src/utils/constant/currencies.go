@@ -0,0 +1,18 @@
+package constant
+
+// DefaultCurrency is the ISO 4217 currency of the movements when the file and the import don't set one
+const DefaultCurrency = "MXN"
+
+// CurrencyDecimals are the supported ISO 4217 currencies with the number of decimals of their amounts
+var CurrencyDecimals = map[string]int{
+	"ARS": 2,
+	"BRL": 2,
+	"CAD": 2,
+	"CLP": 0,
+	"COP": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"JPY": 0,
+	"MXN": 2,
+	"USD": 2,
+}
@@ -0,0 +1,22 @@
+package constant
+
+//Constants for the date formats of the files
+const (
+	DateFormatMonthDay     = "MM/DD"
+	DateFormatDayMonth     = "DD/MM"
+	DateFormatMonthDayYear = "MM/DD/YYYY"
+	DateFormatDayMonthYear = "DD/MM/YYYY"
+	DateFormatISO          = "YYYY-MM-DD"
+)
+
+//DateLayouts has the layout to parse each date format, formats without year don't have it in the layout
+var DateLayouts = map[string]string{
+	DateFormatMonthDay:     "01/02",
+	DateFormatDayMonth:     "02/01",
+	DateFormatMonthDayYear: "01/02/2006",
+	DateFormatDayMonthYear: "02/01/2006",
+	DateFormatISO:          "2006-01-02",
+}
+
+//MonthLayout is the layout of the months (YYYY-MM) in the import summary
+const MonthLayout = "2006-01"
@@ -0,0 +1,9 @@
+package constant
+
+//Constants for import job status
+const (
+	ImportJobPending   = "pending"
+	ImportJobRunning   = "running"
+	ImportJobSucceeded = "succeeded"
+	ImportJobFailed    = "failed"
+)
@@ -0,0 +1,7 @@
+package constant
+
+//Constants for import modes
+const (
+	ImportModeStrict  = "strict"
+	ImportModePartial = "partial"
+)
@@ -0,0 +1,17 @@
+package constant
+
+//Constants for the sort orders of the movement listing, the ones with a minus are descending
+const (
+	MovementSortDate       = "date"
+	MovementSortDateDesc   = "-date"
+	MovementSortAmount     = "amount"
+	MovementSortAmountDesc = "-amount"
+)
+
+//MovementSorts has the columns to order the movements by each sort, the ID breaks the ties
+var MovementSorts = map[string]string{
+	MovementSortDate:       "date ASC, movement_id ASC",
+	MovementSortDateDesc:   "date DESC, movement_id DESC",
+	MovementSortAmount:     "home_quantity ASC, movement_id ASC",
+	MovementSortAmountDesc: "home_quantity DESC, movement_id DESC",
+}
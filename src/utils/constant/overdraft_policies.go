@@ -0,0 +1,8 @@
+package constant
+
+//Constants for the overdraft policies of the customers
+const (
+	OverdraftReject      = "reject"
+	OverdraftCreditLimit = "credit_limit"
+	OverdraftAllowFlag   = "allow_flag"
+)
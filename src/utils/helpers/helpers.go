@@ -1,8 +1,14 @@
 package helpers
 
 import (
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"os"
+	"stori-service/src/libs/errors"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -27,6 +33,77 @@ func IDFromRequestToInt(request *http.Request) (int, error) {
 	return customerid, err
 }
 
+// errBodyTooLarge is the message of the error of http.MaxBytesReader, Go 1.16 doesn't export the error
+const errBodyTooLarge = "http: request body too large"
+
+/*
+FileFromRequest returns the uploaded file of the request without loading it into memory.
+It accepts a multipart form with the file in the given field or a raw body of a statement format.
+The body is limited to maxSize bytes, reading past it fails with ErrFileTooLarge
+*/
+func FileFromRequest(response http.ResponseWriter, request *http.Request, field string, maxSize int64) (io.ReadCloser, error) {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.ErrUnsupportedMediaType
+	}
+	if request.ContentLength > maxSize {
+		return nil, errors.ErrFileTooLarge
+	}
+	request.Body = http.MaxBytesReader(response, request.Body, maxSize)
+	switch mediaType {
+	case "text/csv", "text/plain", "application/x-ofx", "application/vnd.intu.qfx", "application/qif",
+		"application/x-qif", "application/xml", "text/xml":
+		return request.Body, nil
+	case "multipart/form-data":
+		reader, err := request.MultipartReader()
+		if err != nil {
+			return nil, errors.ErrMissingFile
+		}
+		for {
+			part, err := reader.NextPart()
+			if isBodyTooLarge(err) {
+				return nil, errors.ErrFileTooLarge
+			}
+			if err != nil {
+				return nil, errors.ErrMissingFile
+			}
+			if part.FormName() == field {
+				return part, nil
+			}
+			part.Close()
+		}
+	}
+	return nil, errors.ErrUnsupportedMediaType
+}
+
+/*
+isBodyTooLarge returns true when the error is from reading a request body past its limit,
+the multipart reader only keeps the message of the error
+*/
+func isBodyTooLarge(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errBodyTooLarge)
+}
+
+/*
+SaveTempFile copies the content of the reader to a new temporary file and returns its path,
+the caller is responsible for removing it. A request body past its limit fails with ErrFileTooLarge
+*/
+func SaveTempFile(reader io.Reader, pattern string) (string, error) {
+	file, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, reader); err != nil {
+		os.Remove(file.Name())
+		if isBodyTooLarge(err) {
+			return "", errors.ErrFileTooLarge
+		}
+		return "", err
+	}
+	return file.Name(), nil
+}
+
 //PointerToString is a helper to create (inline) pointers to string value, returns nil if string is empty
 func PointerToString(value string) *string {
 	if value == "" {
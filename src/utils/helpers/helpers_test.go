@@ -1,9 +1,15 @@
 package helpers
 
 import (
+	"bytes"
+	"io/ioutil"
 	"math/rand"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"stori-service/src/libs/errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -82,6 +88,151 @@ func TestIDFromRequestToInt(t *testing.T) {
 	})
 }
 
+func TestFileFromRequest(t *testing.T) {
+	fileContent := "id,date,transaction\n1,5/25,+3.5"
+	t.Run("Should success on", func(t *testing.T) {
+		t.Run("Multipart form", func(t *testing.T) {
+			// Fixture
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			writer.WriteField("other", "value")
+			part, _ := writer.CreateFormFile("file", "customer_1.csv")
+			part.Write([]byte(fileContent))
+			writer.Close()
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			// action
+			got, err := FileFromRequest(httptest.NewRecorder(), req, "file", 1024)
+
+			// assertion
+			assert.NoError(t, err)
+			content, _ := ioutil.ReadAll(got)
+			assert.Equal(t, fileContent, string(content))
+		})
+		rawContentTypes := []string{"text/csv; charset=utf-8", "application/x-ofx", "application/qif", "application/xml"}
+		for _, contentType := range rawContentTypes {
+			t.Run("Raw body: "+contentType, func(t *testing.T) {
+				// Fixture
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(fileContent))
+				req.Header.Set("Content-Type", contentType)
+
+				// action
+				got, err := FileFromRequest(httptest.NewRecorder(), req, "file", 1024)
+
+				// assertion
+				assert.NoError(t, err)
+				content, _ := ioutil.ReadAll(got)
+				assert.Equal(t, fileContent, string(content))
+			})
+		}
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Multipart form without the file", func(t *testing.T) {
+			// Fixture
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			writer.WriteField("other", "value")
+			writer.Close()
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			// action
+			got, err := FileFromRequest(httptest.NewRecorder(), req, "file", 1024)
+
+			// assertion
+			assert.Nil(t, got)
+			assert.ErrorIs(t, err, errors.ErrMissingFile)
+		})
+		t.Run("Raw body larger than the limit", func(t *testing.T) {
+			// Fixture
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(fileContent))
+			req.Header.Set("Content-Type", "text/csv")
+
+			// action
+			got, err := FileFromRequest(httptest.NewRecorder(), req, "file", 10)
+
+			// assertion
+			assert.Nil(t, got)
+			assert.ErrorIs(t, err, errors.ErrFileTooLarge)
+		})
+		t.Run("Chunked raw body larger than the limit", func(t *testing.T) {
+			// Fixture
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(fileContent))
+			req.Header.Set("Content-Type", "text/csv")
+			req.ContentLength = -1 // unknown length, the limit is checked while reading
+
+			// action
+			got, err := FileFromRequest(httptest.NewRecorder(), req, "file", 10)
+			assert.NoError(t, err)
+			path, err := SaveTempFile(got, "upload-*.csv")
+
+			// assertion
+			assert.Empty(t, path)
+			assert.ErrorIs(t, err, errors.ErrFileTooLarge)
+		})
+		t.Run("Multipart form larger than the limit", func(t *testing.T) {
+			// Fixture
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			writer.WriteField("other", strings.Repeat("a", 2048))
+			part, _ := writer.CreateFormFile("file", "customer_1.csv")
+			part.Write([]byte(fileContent))
+			writer.Close()
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req.ContentLength = -1
+
+			// action
+			got, err := FileFromRequest(httptest.NewRecorder(), req, "file", 1024)
+
+			// assertion
+			assert.Nil(t, got)
+			assert.ErrorIs(t, err, errors.ErrFileTooLarge)
+		})
+		testCases := []string{"", "application/json", "invalid;;"}
+		for _, contentType := range testCases {
+			t.Run("Content type: "+contentType, func(t *testing.T) {
+				// Fixture
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(fileContent))
+				req.Header.Set("Content-Type", contentType)
+
+				// action
+				got, err := FileFromRequest(httptest.NewRecorder(), req, "file", 1024)
+
+				// assertion
+				assert.Nil(t, got)
+				assert.ErrorIs(t, err, errors.ErrUnsupportedMediaType)
+			})
+		}
+	})
+}
+
+func TestSaveTempFile(t *testing.T) {
+	t.Run("Should success on", func(t *testing.T) {
+		// action
+		path, err := SaveTempFile(strings.NewReader("id,date,transaction"), "upload-*.csv")
+
+		// assertion
+		assert.NoError(t, err)
+		content, _ := ioutil.ReadFile(path)
+		assert.Equal(t, "id,date,transaction", string(content))
+		t.Cleanup(func() {
+			os.Remove(path)
+		})
+	})
+	t.Run("Should fail on", func(t *testing.T) {
+		t.Run("Invalid pattern", func(t *testing.T) {
+			// action
+			path, err := SaveTempFile(strings.NewReader("id,date,transaction"), "../upload-*.csv")
+
+			// assertion
+			assert.Error(t, err)
+			assert.Empty(t, path)
+		})
+	})
+}
+
 func TestPointerToString(t *testing.T) {
 	t.Run("Empty string", func(t *testing.T) {
 		result := PointerToString("")
@@ -3,12 +3,15 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"stori-service/src/libs/dto"
 	myErrors "stori-service/src/libs/errors"
 	"stori-service/src/utils/constant"
 	"strconv"
+	"strings"
 )
 
 /*
@@ -23,6 +26,42 @@ func MakePaginateResponse(response http.ResponseWriter, data interface{}, status
 	makeResponse(response, body, statusCode)
 }
 
+/*
+MakeCursorPaginateResponse Set Data array, cursor pagination headers, the RFC 8288 Link header with the pages
+before and after, with the query of the request, and Errors to empty array
+*/
+func MakeCursorPaginateResponse(response http.ResponseWriter, requestURL *url.URL, data interface{}, statusCode int, pagination *dto.CursorPagination) {
+	links := []string{}
+	if pagination.Next != nil {
+		next := pagination.Next.Encode()
+		response.Header().Set("X-pagination-next-cursor", next)
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, cursorURL(requestURL, next)))
+	}
+	if pagination.Prev != nil {
+		prev := pagination.Prev.Encode()
+		response.Header().Set("X-pagination-prev-cursor", prev)
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, cursorURL(requestURL, prev)))
+	}
+	if len(links) > 0 {
+		response.Header().Set("Link", strings.Join(links, ", "))
+	}
+	response.Header().Set("X-pagination-page-size", strconv.Itoa(pagination.PageSize))
+	body := dto.NewBodyResponse("Success", make([]map[string]string, 0), data)
+	makeResponse(response, body, statusCode)
+}
+
+/*
+cursorURL returns the path and query of the request with the cursor of another page
+*/
+func cursorURL(requestURL *url.URL, cursor string) string {
+	query := requestURL.Query()
+	query.Del("pagination")
+	query.Del("page")
+	query.Set("cursor", cursor)
+	link := url.URL{Path: requestURL.Path, RawQuery: query.Encode()}
+	return link.RequestURI()
+}
+
 /*
 MakeSuccessResponse Set Message, Data object and Errors to empty array
 */
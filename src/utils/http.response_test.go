@@ -3,13 +3,16 @@ package utils
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"stori-service/src/libs/dto"
 	myErrors "stori-service/src/libs/errors"
 	"stori-service/src/utils/constant"
 	customMock "stori-service/src/utils/test/mock"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -58,6 +61,58 @@ func TestMakePaginateResponse(t *testing.T) {
 	})
 }
 
+func TestMakeCursorPaginateResponse(t *testing.T) {
+	t.Run("Should Succeed", func(t *testing.T) {
+		t.Run("Correct header data", func(t *testing.T) {
+			// Fixture
+			next := &dto.Cursor{Date: time.Date(2022, time.July, 2, 0, 0, 0, 0, time.UTC), MovementID: 8}
+			prev := &dto.Cursor{Date: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), MovementID: 3, Backward: true}
+			page := &dto.CursorPagination{
+				PageSize: 10,
+				Next:     next,
+				Prev:     prev,
+			}
+			query := url.Values{"type": []string{"income"}, "pagination": []string{"cursor"}}
+
+			// Run Foo inside request
+			response := customMock.MHTTPHandle("GET", "/movements",
+				func(response http.ResponseWriter, request *http.Request) {
+					assert.NotPanics(t, func() {
+						MakeCursorPaginateResponse(
+							response,
+							request.URL,
+							dto.NewBodyResponse(defaultMessage, defaultErrors, defaultData),
+							http.StatusOK,
+							page,
+						)
+					})
+				}, "movements", query, nil)
+
+			// Assert Data
+			assert.Equal(t, "10", response.Header.Get("X-pagination-page-size"))
+			assert.Equal(t, next.Encode(), response.Header.Get("X-pagination-next-cursor"))
+			assert.Equal(t, prev.Encode(), response.Header.Get("X-pagination-prev-cursor"))
+			assert.Equal(t, fmt.Sprintf(`</movements?cursor=%s&type=income>; rel="next", </movements?cursor=%s&type=income>; rel="prev"`, next.Encode(), prev.Encode()), response.Header.Get("Link"))
+		})
+		t.Run("Last page", func(t *testing.T) {
+			// Fixture
+			page := &dto.CursorPagination{PageSize: 10}
+
+			// Run Foo inside request
+			response := customMock.MHTTPHandle("GET", "/movements",
+				func(response http.ResponseWriter, request *http.Request) {
+					MakeCursorPaginateResponse(response, request.URL, defaultData, http.StatusOK, page)
+				}, "movements", nil, nil)
+
+			// Assert Data
+			assert.Equal(t, "10", response.Header.Get("X-pagination-page-size"))
+			assert.Empty(t, response.Header.Get("X-pagination-next-cursor"))
+			assert.Empty(t, response.Header.Get("X-pagination-prev-cursor"))
+			assert.Empty(t, response.Header.Get("Link"))
+		})
+	})
+}
+
 func TestMakeSuccessResponse(t *testing.T) {
 	t.Run("Should Succeed", func(t *testing.T) {
 		t.Run("Correct OK response", func(t *testing.T) {
@@ -0,0 +1,110 @@
+package importoptions
+
+import (
+	"net/url"
+	"sort"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/statement"
+	"stori-service/src/utils/constant"
+	"stori-service/src/utils/helpers"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSourceLength is the size of the source column of the movements
+const maxSourceLength = 50
+
+/*
+GetImportOptionsFromQuery receives a queryString from request, extracts mode, preview, profile, source, currency,
+format, date format and statement date, then returns the import options. The mode is strict, the profile is the default one,
+the currency is the default one and the source and date format are the ones of the profile by default
+*/
+func GetImportOptionsFromQuery(queryString url.Values) (*dto.ImportOptions, error) {
+	mode := queryString.Get("mode")
+	if mode == "" {
+		mode = constant.ImportModeStrict
+	}
+	if !helpers.StringInSlice(mode, []string{constant.ImportModeStrict, constant.ImportModePartial}) {
+		return nil, errors.ErrFieldValidation("mode", "oneof", constant.ImportModeStrict+" "+constant.ImportModePartial)
+	}
+	var preview bool
+	var err error
+	if previewStr := queryString.Get("preview"); previewStr != "" {
+		preview, err = strconv.ParseBool(previewStr)
+		if err != nil {
+			return nil, errors.ErrFieldValidation("preview", "boolean", "")
+		}
+	}
+	profile, err := statement.GetProfile(queryString.Get("profile"))
+	if err != nil {
+		return nil, err
+	}
+	source := queryString.Get("source")
+	if source == "" {
+		source = profile.Name
+	}
+	if len(source) > maxSourceLength {
+		return nil, errors.ErrFieldValidation("source", "max", strconv.Itoa(maxSourceLength))
+	}
+	sourceCurrency := strings.ToUpper(queryString.Get("source_currency"))
+	if sourceCurrency == "" {
+		sourceCurrency = constant.DefaultCurrency
+	}
+	if _, ok := constant.CurrencyDecimals[sourceCurrency]; !ok {
+		return nil, errors.ErrFieldValidation("source_currency", "oneof", strings.Join(currencies(), " "))
+	}
+	format := queryString.Get("format")
+	if format != "" && !helpers.StringInSlice(format, statement.Formats()) {
+		return nil, errors.ErrFieldValidation("format", "oneof", strings.Join(statement.Formats(), " "))
+	}
+	dateFormat := queryString.Get("date_format")
+	if dateFormat == "" {
+		dateFormat = profile.DateFormat
+	}
+	if _, ok := constant.DateLayouts[dateFormat]; !ok {
+		return nil, errors.ErrFieldValidation("date_format", "oneof", strings.Join(dateFormats(), " "))
+	}
+	var statementDate time.Time
+	if statementDateStr := queryString.Get("statement_date"); statementDateStr != "" {
+		statementDate, err = time.Parse(constant.DateLayouts[constant.DateFormatISO], statementDateStr)
+		if err != nil {
+			return nil, errors.ErrFieldValidation("statement_date", "date", constant.DateFormatISO)
+		}
+	}
+	return &dto.ImportOptions{
+		Mode:           mode,
+		Preview:        preview,
+		Profile:        profile.Name,
+		Source:         source,
+		SourceCurrency: sourceCurrency,
+		Format:         format,
+		DateFormat:     dateFormat,
+		StatementDate:  statementDate,
+	}, nil
+}
+
+/*
+dateFormats returns the supported date formats sorted, to show them in the validation error
+*/
+func dateFormats() []string {
+	formats := make([]string, 0, len(constant.DateLayouts))
+	for format := range constant.DateLayouts {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+/*
+currencies returns the supported currencies sorted, to show them in the validation error
+*/
+func currencies() []string {
+	codes := make([]string, 0, len(constant.CurrencyDecimals))
+	for code := range constant.CurrencyDecimals {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
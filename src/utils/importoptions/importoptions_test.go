@@ -0,0 +1,153 @@
+package importoptions
+
+import (
+	"net/url"
+	"stori-service/src/libs/errors"
+	"stori-service/src/utils/constant"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportOptions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Default values", func(t *testing.T) {
+			queryString := url.Values{}
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, constant.ImportModeStrict, result.Mode)
+			assert.False(t, result.Preview)
+			assert.Equal(t, "default", result.Profile)
+			assert.Equal(t, "default", result.Source)
+			assert.Equal(t, constant.DefaultCurrency, result.SourceCurrency)
+			assert.Equal(t, constant.DateFormatMonthDay, result.DateFormat)
+			assert.Empty(t, result.Format)
+			assert.True(t, result.StatementDate.IsZero())
+			assert.NoError(t, err)
+		})
+		t.Run("Date format and statement date", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("date_format", "DD/MM/YYYY")
+			queryString.Set("statement_date", "2022-01-31")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, constant.DateFormatDayMonthYear, result.DateFormat)
+			assert.Equal(t, time.Date(2022, time.January, 31, 0, 0, 0, 0, time.UTC), result.StatementDate)
+			assert.NoError(t, err)
+		})
+		t.Run("Profile with its date format", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("profile", "latam")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, "latam", result.Profile)
+			assert.Equal(t, "latam", result.Source)
+			assert.Equal(t, constant.DateFormatDayMonthYear, result.DateFormat)
+			assert.NoError(t, err)
+		})
+		t.Run("Source", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("profile", "latam")
+			queryString.Set("source", "bank_a")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, "latam", result.Profile)
+			assert.Equal(t, "bank_a", result.Source)
+			assert.NoError(t, err)
+		})
+		t.Run("Source currency", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("source_currency", "usd")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, "USD", result.SourceCurrency)
+			assert.NoError(t, err)
+		})
+		t.Run("Profile with another date format", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("profile", "latam")
+			queryString.Set("date_format", "DD/MM")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, "latam", result.Profile)
+			assert.Equal(t, constant.DateFormatDayMonth, result.DateFormat)
+			assert.NoError(t, err)
+		})
+		t.Run("Preview", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("preview", "true")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, constant.ImportModeStrict, result.Mode)
+			assert.True(t, result.Preview)
+			assert.NoError(t, err)
+		})
+		t.Run("Format", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("format", "ofx")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, "ofx", result.Format)
+			assert.NoError(t, err)
+		})
+		t.Run("Partial mode", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("mode", "partial")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Equal(t, constant.ImportModePartial, result.Mode)
+			assert.NoError(t, err)
+		})
+	})
+	t.Run("Fail", func(t *testing.T) {
+		t.Run("Unknown mode", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("mode", "lenient")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.Error(t, err)
+		})
+		t.Run("Unknown profile", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("profile", "unknown")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.Error(t, err)
+		})
+		t.Run("Unknown date format", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("date_format", "YY/MM/DD")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.EqualError(t, err, errors.ErrFieldValidation("date_format", "oneof", "DD/MM DD/MM/YYYY MM/DD MM/DD/YYYY YYYY-MM-DD").Error())
+		})
+		t.Run("Unknown format", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("format", "xls")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.EqualError(t, err, errors.ErrFieldValidation("format", "oneof", "camt053 csv ofx qif").Error())
+		})
+		t.Run("Source too long", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("source", strings.Repeat("a", 51))
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.EqualError(t, err, errors.ErrFieldValidation("source", "max", "50").Error())
+		})
+		t.Run("Unknown source currency", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("source_currency", "XYZ")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.EqualError(t, err, errors.ErrFieldValidation("source_currency", "oneof", "ARS BRL CAD CLP COP EUR GBP JPY MXN USD").Error())
+		})
+		t.Run("Invalid statement date", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("statement_date", "31/01/2022")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.Error(t, err)
+		})
+		t.Run("Invalid preview", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("preview", "maybe")
+			result, err := GetImportOptionsFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.Error(t, err)
+		})
+	})
+}
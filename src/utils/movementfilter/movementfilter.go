@@ -0,0 +1,127 @@
+package movementfilter
+
+import (
+	"net/url"
+	"sort"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
+	"strings"
+	"time"
+)
+
+// types has the movement type of each value of the type param
+var types = map[string]int{
+	"income":  constant.IncomeType,
+	"outcome": constant.OutcomeType,
+}
+
+/*
+GetMovementFilterFromQuery receives a queryString from request, extracts from and to (YYYY-MM-DD), type (income or outcome),
+min_amount, max_amount and sort, then returns the movement filter. The movements are sorted from the newest by default
+*/
+func GetMovementFilterFromQuery(queryString url.Values) (*dto.MovementFilter, error) {
+	from, to, err := GetDateRangeFromQuery(queryString)
+	if err != nil {
+		return nil, err
+	}
+	var movementType int
+	if typeStr := queryString.Get("type"); typeStr != "" {
+		var ok bool
+		movementType, ok = types[typeStr]
+		if !ok {
+			return nil, errors.ErrFieldValidation("type", "oneof", "income outcome")
+		}
+	}
+	minAmount, err := parseAmount(queryString, "min_amount")
+	if err != nil {
+		return nil, err
+	}
+	maxAmount, err := parseAmount(queryString, "max_amount")
+	if err != nil {
+		return nil, err
+	}
+	if minAmount != nil && maxAmount != nil && *maxAmount < *minAmount {
+		return nil, errors.ErrFieldValidation("max_amount", "gtefield", "min_amount")
+	}
+	sortOrder := queryString.Get("sort")
+	if sortOrder == "" {
+		sortOrder = constant.MovementSortDateDesc
+	}
+	if _, ok := constant.MovementSorts[sortOrder]; !ok {
+		return nil, errors.ErrFieldValidation("sort", "oneof", strings.Join(sorts(), " "))
+	}
+	return &dto.MovementFilter{
+		From:      from,
+		To:        to,
+		Type:      movementType,
+		MinAmount: minAmount,
+		MaxAmount: maxAmount,
+		Sort:      sortOrder,
+	}, nil
+}
+
+/*
+GetDateRangeFromQuery receives a queryString from request and extracts the days from and to (YYYY-MM-DD),
+both included, a missing day is nil and doesn't limit the range
+*/
+func GetDateRangeFromQuery(queryString url.Values) (*time.Time, *time.Time, error) {
+	from, err := parseDate(queryString, "from")
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseDate(queryString, "to")
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, nil, errors.ErrFieldValidation("to", "gtefield", "from")
+	}
+	return from, to, nil
+}
+
+/*
+parseDate returns the ISO date of the param, or nil when it's missing
+*/
+func parseDate(queryString url.Values, param string) (*time.Time, error) {
+	dateStr := queryString.Get(param)
+	if dateStr == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(constant.DateLayouts[constant.DateFormatISO], dateStr)
+	if err != nil {
+		return nil, errors.ErrFieldValidation(param, "date", constant.DateFormatISO)
+	}
+	return &date, nil
+}
+
+/*
+parseAmount returns the amount of the param, that can't be negative, or nil when it's missing
+*/
+func parseAmount(queryString url.Values, param string) (*money.Amount, error) {
+	amountStr := queryString.Get(param)
+	if amountStr == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(amountStr)
+	if err != nil {
+		return nil, errors.ErrFieldValidation(param, "numeric", "")
+	}
+	if amount < 0 {
+		return nil, errors.ErrFieldValidation(param, "gte", "0")
+	}
+	return &amount, nil
+}
+
+/*
+sorts returns the supported sort orders sorted, to show them in the validation error
+*/
+func sorts() []string {
+	names := make([]string, 0, len(constant.MovementSorts))
+	for name := range constant.MovementSorts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
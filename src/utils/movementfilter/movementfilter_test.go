@@ -0,0 +1,100 @@
+package movementfilter
+
+import (
+	"net/url"
+	"stori-service/src/libs/errors"
+	"stori-service/src/libs/money"
+	"stori-service/src/utils/constant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovementFilter(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Run("Default values", func(t *testing.T) {
+			queryString := url.Values{}
+			result, err := GetMovementFilterFromQuery(queryString)
+			assert.Nil(t, result.From)
+			assert.Nil(t, result.To)
+			assert.Zero(t, result.Type)
+			assert.Nil(t, result.MinAmount)
+			assert.Nil(t, result.MaxAmount)
+			assert.Equal(t, constant.MovementSortDateDesc, result.Sort)
+			assert.NoError(t, err)
+		})
+		t.Run("Date range", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("from", "2022-01-01")
+			queryString.Set("to", "2022-01-31")
+			result, err := GetMovementFilterFromQuery(queryString)
+			assert.Equal(t, time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), *result.From)
+			assert.Equal(t, time.Date(2022, time.January, 31, 0, 0, 0, 0, time.UTC), *result.To)
+			assert.NoError(t, err)
+		})
+		t.Run("Type, amount range and sort", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("type", "outcome")
+			queryString.Set("min_amount", "10")
+			queryString.Set("max_amount", "99.50")
+			queryString.Set("sort", "-amount")
+			result, err := GetMovementFilterFromQuery(queryString)
+			assert.Equal(t, constant.OutcomeType, result.Type)
+			assert.Equal(t, money.Amount(1000), *result.MinAmount)
+			assert.Equal(t, money.Amount(9950), *result.MaxAmount)
+			assert.Equal(t, constant.MovementSortAmountDesc, result.Sort)
+			assert.NoError(t, err)
+		})
+	})
+	t.Run("Fail", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			query    url.Values
+			expected error
+		}{
+			{
+				name:     "Invalid from",
+				query:    url.Values{"from": []string{"01/31/2022"}},
+				expected: errors.ErrFieldValidation("from", "date", constant.DateFormatISO),
+			},
+			{
+				name:     "To before from",
+				query:    url.Values{"from": []string{"2022-02-01"}, "to": []string{"2022-01-31"}},
+				expected: errors.ErrFieldValidation("to", "gtefield", "from"),
+			},
+			{
+				name:     "Invalid type",
+				query:    url.Values{"type": []string{"1"}},
+				expected: errors.ErrFieldValidation("type", "oneof", "income outcome"),
+			},
+			{
+				name:     "Invalid amount",
+				query:    url.Values{"min_amount": []string{"ten"}},
+				expected: errors.ErrFieldValidation("min_amount", "numeric", ""),
+			},
+			{
+				name:     "Negative amount",
+				query:    url.Values{"max_amount": []string{"-5"}},
+				expected: errors.ErrFieldValidation("max_amount", "gte", "0"),
+			},
+			{
+				name:     "Max amount below min amount",
+				query:    url.Values{"min_amount": []string{"10"}, "max_amount": []string{"5"}},
+				expected: errors.ErrFieldValidation("max_amount", "gtefield", "min_amount"),
+			},
+			{
+				name:     "Invalid sort",
+				query:    url.Values{"sort": []string{"merchant"}},
+				expected: errors.ErrFieldValidation("sort", "oneof", "-amount -date amount date"),
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				result, err := GetMovementFilterFromQuery(testCase.query)
+				assert.Nil(t, result)
+				assert.EqualError(t, err, testCase.expected.Error())
+			})
+		}
+	})
+}
@@ -13,12 +13,7 @@ and page_size, then returns pagination object
 */
 func GetPaginationFromQuery(queryString url.Values) (*dto.Pagination, error) {
 	pageStr := queryString.Get("page")
-	pageSizeStr := queryString.Get("page_size")
-	// BWC
-	if pageSizeStr == "" {
-		pageSizeStr = queryString.Get("pageSize")
-	}
-	var page, pageSize int
+	var page int
 	var err error
 	if pageStr != "" {
 		page, err = strconv.Atoi(pageStr)
@@ -26,11 +21,9 @@ func GetPaginationFromQuery(queryString url.Values) (*dto.Pagination, error) {
 			return nil, errors.ErrFieldValidation("page", "not_number", "")
 		}
 	}
-	if pageSizeStr != "" {
-		pageSize, err = strconv.Atoi(pageSizeStr)
-		if err != nil {
-			return nil, errors.ErrFieldValidation("page_size", "not_number", "")
-		}
+	pageSize, err := getPageSize(queryString)
+	if err != nil {
+		return nil, err
 	}
 	if page > 100 {
 		return nil, errors.ErrPageTooHigh
@@ -38,11 +31,58 @@ func GetPaginationFromQuery(queryString url.Values) (*dto.Pagination, error) {
 	if page < 1 {
 		page = 1
 	}
+	return dto.NewPagination(page, pageSize, 0), nil
+}
+
+/*
+IsCursorQuery returns true when the queryString asks for the cursor pagination, with pagination=cursor
+for the first page or with the cursor of another page
+*/
+func IsCursorQuery(queryString url.Values) bool {
+	return queryString.Get("pagination") == "cursor" || queryString.Get("cursor") != ""
+}
+
+/*
+GetCursorPaginationFromQuery receives a queryString from request, extracts cursor
+and page_size, then returns cursor pagination object, without cursor it's the first page
+*/
+func GetCursorPaginationFromQuery(queryString url.Values) (*dto.CursorPagination, error) {
+	pageSize, err := getPageSize(queryString)
+	if err != nil {
+		return nil, err
+	}
+	var cursor *dto.Cursor
+	if cursorStr := queryString.Get("cursor"); cursorStr != "" {
+		cursor, err = dto.DecodeCursor(cursorStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dto.NewCursorPagination(pageSize, cursor), nil
+}
+
+/*
+getPageSize extracts page_size, 20 by default and up to 100
+*/
+func getPageSize(queryString url.Values) (int, error) {
+	pageSizeStr := queryString.Get("page_size")
+	// BWC
+	if pageSizeStr == "" {
+		pageSizeStr = queryString.Get("pageSize")
+	}
+	var pageSize int
+	var err error
+	if pageSizeStr != "" {
+		pageSize, err = strconv.Atoi(pageSizeStr)
+		if err != nil {
+			return 0, errors.ErrFieldValidation("page_size", "not_number", "")
+		}
+	}
 	if pageSize > 100 {
-		return nil, errors.ErrPageSizeTooHigh
+		return 0, errors.ErrPageSizeTooHigh
 	}
 	if pageSize < 1 {
 		pageSize = 20
 	}
-	return dto.NewPagination(page, pageSize, 0), nil
+	return pageSize, nil
 }
@@ -2,8 +2,10 @@ package pagination
 
 import (
 	"net/url"
+	"stori-service/src/libs/dto"
 	"stori-service/src/libs/i18n"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -92,3 +94,50 @@ func TestPagination(t *testing.T) {
 		})
 	})
 }
+
+func TestCursorPagination(t *testing.T) {
+	cursor := &dto.Cursor{Date: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), MovementID: 15}
+	t.Run("Success", func(t *testing.T) {
+		t.Run("First page", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("pagination", "cursor")
+			assert.True(t, IsCursorQuery(queryString))
+			result, err := GetCursorPaginationFromQuery(queryString)
+			assert.Equal(t, 20, result.PageSize)
+			assert.Nil(t, result.Cursor)
+			assert.NoError(t, err)
+		})
+		t.Run("Page of a cursor", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("cursor", cursor.Encode())
+			queryString.Set("page_size", "10")
+			assert.True(t, IsCursorQuery(queryString))
+			result, err := GetCursorPaginationFromQuery(queryString)
+			assert.Equal(t, 10, result.PageSize)
+			assert.Equal(t, cursor, result.Cursor)
+			assert.NoError(t, err)
+		})
+		t.Run("Offset query", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("page", "2")
+			assert.False(t, IsCursorQuery(queryString))
+		})
+	})
+	t.Run("Fail", func(t *testing.T) {
+		t.Run("Invalid cursor", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("cursor", "not_a_cursor")
+			result, err := GetCursorPaginationFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.EqualError(t, err, i18n.T(i18n.Message{MessageID: "ERRORS.INVALID_CURSOR"}))
+		})
+		t.Run("PageSize exceeds maximum", func(t *testing.T) {
+			queryString := url.Values{}
+			queryString.Set("pagination", "cursor")
+			queryString.Set("page_size", "101")
+			result, err := GetCursorPaginationFromQuery(queryString)
+			assert.Nil(t, result)
+			assert.EqualError(t, err, i18n.T(i18n.Message{MessageID: "ERRORS.PAGE_SIZE_TOO_LARGE"}))
+		})
+	})
+}
@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientBalanceController is a IBalanceController mock
+*/
+type ClientBalanceController struct {
+	mock.Mock
+}
+
+// FindAt mock method
+func (mock *ClientBalanceController) FindAt(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
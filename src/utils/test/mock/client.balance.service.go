@@ -0,0 +1,52 @@
+package mock
+
+import (
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientBalanceService is a IBalanceService mock
+*/
+type ClientBalanceService struct {
+	mock.Mock
+}
+
+// Recalculate mock method
+func (c *ClientBalanceService) Recalculate(tx interface{}, customerID int, currency string, from time.Time) error {
+	args := c.Called(tx, customerID, currency, from)
+	return args.Error(0)
+}
+
+// FindOverdrafts mock method
+func (c *ClientBalanceService) FindOverdrafts(tx interface{}, importBatchID int) ([]entity.Movement, error) {
+	args := c.Called(tx, importBatchID)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindCreditLimitOverdraft mock method
+func (c *ClientBalanceService) FindCreditLimitOverdraft(tx interface{}, customer *entity.Customer, importBatchID int) (*entity.Overdraft, error) {
+	args := c.Called(tx, customer, importBatchID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.Overdraft), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindAt mock method
+func (c *ClientBalanceService) FindAt(customerID int, filter dto.BalanceFilter) (*dto.BalanceAt, error) {
+	args := c.Called(customerID, filter)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*dto.BalanceAt), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientCategoryRuleController is a ICategoryRuleController mock
+*/
+type ClientCategoryRuleController struct {
+	mock.Mock
+}
+
+// List mock method
+func (mock *ClientCategoryRuleController) List(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
+
+// FindByID mock method
+func (mock *ClientCategoryRuleController) FindByID(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
+
+// Create mock method
+func (mock *ClientCategoryRuleController) Create(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
+
+// Update mock method
+func (mock *ClientCategoryRuleController) Update(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
+
+// Delete mock method
+func (mock *ClientCategoryRuleController) Delete(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
@@ -0,0 +1,58 @@
+package mock
+
+import "stori-service/src/environments/common/resources/entity"
+
+/*
+ClientCategoryRuleRepository is a ICategoryRuleRepository mock
+*/
+type ClientCategoryRuleRepository struct {
+	TransactionalRepository
+}
+
+/*
+Create mock method
+*/
+func (mock *ClientCategoryRuleRepository) Create(categoryRule *entity.CategoryRule) error {
+	args := mock.Called(categoryRule)
+	return args.Error(0)
+}
+
+/*
+Update mock method
+*/
+func (mock *ClientCategoryRuleRepository) Update(categoryRule *entity.CategoryRule) error {
+	args := mock.Called(categoryRule)
+	return args.Error(0)
+}
+
+/*
+Delete mock method
+*/
+func (mock *ClientCategoryRuleRepository) Delete(categoryRuleID int) error {
+	args := mock.Called(categoryRuleID)
+	return args.Error(0)
+}
+
+/*
+FindByID mock method
+*/
+func (mock *ClientCategoryRuleRepository) FindByID(categoryRuleID int) (*entity.CategoryRule, error) {
+	args := mock.Called(categoryRuleID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.CategoryRule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+/*
+Find mock method
+*/
+func (mock *ClientCategoryRuleRepository) Find(customerID *int) ([]entity.CategoryRule, error) {
+	args := mock.Called(customerID)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.CategoryRule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,77 @@
+package mock
+
+import (
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/categorizer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientCategoryRuleService is a ICategoryRuleService mock
+*/
+type ClientCategoryRuleService struct {
+	mock.Mock
+}
+
+// Create mock method
+func (c *ClientCategoryRuleService) Create(categoryRule entity.CategoryRule) (*entity.CategoryRule, error) {
+	args := c.Called(categoryRule)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.CategoryRule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Update mock method
+func (c *ClientCategoryRuleService) Update(categoryRuleID int, categoryRule entity.CategoryRule) (*entity.CategoryRule, error) {
+	args := c.Called(categoryRuleID, categoryRule)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.CategoryRule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Delete mock method
+func (c *ClientCategoryRuleService) Delete(categoryRuleID int) error {
+	args := c.Called(categoryRuleID)
+	return args.Error(0)
+}
+
+// FindByID mock method
+func (c *ClientCategoryRuleService) FindByID(categoryRuleID int) (*entity.CategoryRule, error) {
+	args := c.Called(categoryRuleID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.CategoryRule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Find mock method
+func (c *ClientCategoryRuleService) Find(customerID *int) ([]entity.CategoryRule, error) {
+	args := c.Called(customerID)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.CategoryRule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// NewCategorizer mock method
+func (c *ClientCategoryRuleService) NewCategorizer(tx interface{}, customerID int) (*categorizer.Categorizer, error) {
+	args := c.Called(tx, customerID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*categorizer.Categorizer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Recategorize mock method
+func (c *ClientCategoryRuleService) Recategorize(customerID int) (int, error) {
+	args := c.Called(customerID)
+	return args.Int(0), args.Error(1)
+}
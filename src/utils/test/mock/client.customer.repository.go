@@ -32,3 +32,15 @@ func (mock *ClientCustomerRepository) FindByCustomerID(customerid int) (*entity.
 	}
 	return nil, args.Error(1)
 }
+
+/*
+FindIDs mock method
+*/
+func (mock *ClientCustomerRepository) FindIDs() ([]int, error) {
+	args := mock.Called()
+	result := args.Get(0)
+	if result != nil {
+		return result.([]int), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"stori-service/src/environments/common/resources/entity"
+	"time"
+)
+
+/*
+ClientExchangeRateRepository is a IExchangeRateRepository mock
+*/
+type ClientExchangeRateRepository struct {
+	TransactionalRepository
+}
+
+/*
+FindEffective mock method
+*/
+func (mock *ClientExchangeRateRepository) FindEffective(baseCurrency string, quoteCurrency string, date time.Time) (*entity.ExchangeRate, error) {
+	args := mock.Called(baseCurrency, quoteCurrency, date)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ExchangeRate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+/*
+Upsert mock method
+*/
+func (mock *ClientExchangeRateRepository) Upsert(exchangeRates []entity.ExchangeRate) error {
+	args := mock.Called(exchangeRates)
+	return args.Error(0)
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"io"
+	"stori-service/src/libs/money"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientExchangeRateService is a IExchangeRateService mock
+*/
+type ClientExchangeRateService struct {
+	mock.Mock
+}
+
+// Rate mock method
+func (c *ClientExchangeRateService) Rate(from string, to string, date time.Time) (money.Rate, error) {
+	args := c.Called(from, to, date)
+	return args.Get(0).(money.Rate), args.Error(1)
+}
+
+// Convert mock method
+func (c *ClientExchangeRateService) Convert(amount money.Amount, from string, to string, date time.Time) (money.Amount, error) {
+	args := c.Called(amount, from, to, date)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+// ImportRates mock method
+func (c *ClientExchangeRateService) ImportRates(file io.Reader) (int, error) {
+	args := c.Called(file)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientImportBatchController is a IImportBatchController mock
+*/
+type ClientImportBatchController struct {
+	mock.Mock
+}
+
+// Revert mock method
+func (mock *ClientImportBatchController) Revert(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
@@ -0,0 +1,50 @@
+package mock
+
+import "stori-service/src/environments/common/resources/entity"
+
+/*
+ClientImportBatchRepository is a IImportBatchRepository mock
+*/
+type ClientImportBatchRepository struct {
+	TransactionalRepository
+}
+
+/*
+Create mock method
+*/
+func (mock *ClientImportBatchRepository) Create(importBatch *entity.ImportBatch) error {
+	args := mock.Called(importBatch)
+	return args.Error(0)
+}
+
+/*
+Update mock method
+*/
+func (mock *ClientImportBatchRepository) Update(importBatch *entity.ImportBatch) error {
+	args := mock.Called(importBatch)
+	return args.Error(0)
+}
+
+/*
+FindByFileHash mock method
+*/
+func (mock *ClientImportBatchRepository) FindByFileHash(customerID int, fileHash string) (*entity.ImportBatch, error) {
+	args := mock.Called(customerID, fileHash)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportBatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+/*
+FindAndLockByID mock method
+*/
+func (mock *ClientImportBatchRepository) FindAndLockByID(importBatchID int) (*entity.ImportBatch, error) {
+	args := mock.Called(importBatchID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportBatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,25 @@
+package mock
+
+import (
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientImportBatchService is a IImportBatchService mock
+*/
+type ClientImportBatchService struct {
+	mock.Mock
+}
+
+// Revert mock method
+func (c *ClientImportBatchService) Revert(importBatchID int, importRevert dto.ImportRevert) (*entity.ImportBatch, error) {
+	args := c.Called(importBatchID, importRevert)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportBatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
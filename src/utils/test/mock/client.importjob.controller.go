@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientImportJobController is a IImportJobController mock
+*/
+type ClientImportJobController struct {
+	mock.Mock
+}
+
+// FindByID mock method
+func (mock *ClientImportJobController) FindByID(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
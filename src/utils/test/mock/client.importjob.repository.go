@@ -0,0 +1,42 @@
+package mock
+
+import "stori-service/src/environments/common/resources/entity"
+
+/*
+ClientImportJobRepository is a IImportJobRepository mock
+*/
+type ClientImportJobRepository struct {
+	TransactionalRepository
+}
+
+// Create mock method
+func (mock *ClientImportJobRepository) Create(importJob *entity.ImportJob) error {
+	args := mock.Called(importJob)
+	return args.Error(0)
+}
+
+// Update mock method
+func (mock *ClientImportJobRepository) Update(importJob *entity.ImportJob) error {
+	args := mock.Called(importJob)
+	return args.Error(0)
+}
+
+// FindByID mock method
+func (mock *ClientImportJobRepository) FindByID(importJobID int) (*entity.ImportJob, error) {
+	args := mock.Called(importJobID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindUnfinished mock method
+func (mock *ClientImportJobRepository) FindUnfinished() ([]entity.ImportJob, error) {
+	args := mock.Called()
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
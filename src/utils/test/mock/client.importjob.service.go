@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"stori-service/src/environments/client/resources/interfaces"
+	"stori-service/src/environments/common/resources/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ClientImportJobService struct {
+	mock.Mock
+}
+
+// Enqueue mock method
+func (c *ClientImportJobService) Enqueue(customerID int, run interfaces.ImportFunc) (*entity.ImportJob, error) {
+	args := c.Called(customerID, run)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindByID mock method
+func (c *ClientImportJobService) FindByID(importJobID int) (*entity.ImportJob, error) {
+	args := c.Called(importJobID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FailUnfinished mock method
+func (c *ClientImportJobService) FailUnfinished() error {
+	args := c.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientLedgerController is a ILedgerController mock
+*/
+type ClientLedgerController struct {
+	mock.Mock
+}
+
+// TrialBalance mock method
+func (mock *ClientLedgerController) TrialBalance(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+)
+
+/*
+ClientLedgerRepository is a ILedgerRepository mock
+*/
+type ClientLedgerRepository struct {
+	TransactionalRepository
+}
+
+/*
+UpsertAccount mock method
+*/
+func (mock *ClientLedgerRepository) UpsertAccount(account *entity.Account) error {
+	args := mock.Called(account)
+	return args.Error(0)
+}
+
+/*
+CreateEntries mock method
+*/
+func (mock *ClientLedgerRepository) CreateEntries(journalEntries []entity.JournalEntry) error {
+	args := mock.Called(journalEntries)
+	return args.Error(0)
+}
+
+/*
+FindEntriesByImportBatchID mock method
+*/
+func (mock *ClientLedgerRepository) FindEntriesByImportBatchID(importBatchID int) ([]entity.JournalEntry, error) {
+	args := mock.Called(importBatchID)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.JournalEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+/*
+FindAccountBalances mock method
+*/
+func (mock *ClientLedgerRepository) FindAccountBalances() ([]dto.AccountBalance, error) {
+	args := mock.Called()
+	result := args.Get(0)
+	if result != nil {
+		return result.([]dto.AccountBalance), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+ClientLedgerService is a ILedgerService mock
+*/
+type ClientLedgerService struct {
+	mock.Mock
+}
+
+// PostMovements mock method
+func (c *ClientLedgerService) PostMovements(tx interface{}, movements []entity.Movement) error {
+	args := c.Called(tx, movements)
+	return args.Error(0)
+}
+
+// ReverseImportBatch mock method
+func (c *ClientLedgerService) ReverseImportBatch(tx interface{}, importBatchID int, date time.Time) error {
+	args := c.Called(tx, importBatchID, date)
+	return args.Error(0)
+}
+
+// TrialBalance mock method
+func (c *ClientLedgerService) TrialBalance() (*dto.TrialBalance, error) {
+	args := c.Called()
+	result := args.Get(0)
+	if result != nil {
+		return result.(*dto.TrialBalance), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -17,3 +17,13 @@ type ClientMovementController struct {
 func (mock *ClientMovementController) ProcessFile(response http.ResponseWriter, request *http.Request) {
 	mock.Called(response, request)
 }
+
+// UploadFile mock method
+func (mock *ClientMovementController) UploadFile(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
+
+// List mock method
+func (mock *ClientMovementController) List(response http.ResponseWriter, request *http.Request) {
+	mock.Called(response, request)
+}
@@ -2,6 +2,9 @@ package mock
 
 import (
 	"stori-service/src/environments/common/resources/entity"
+	"stori-service/src/libs/dto"
+	"stori-service/src/libs/money"
+	"time"
 )
 
 /*
@@ -18,11 +21,129 @@ func (mock *ClientMovementRepository) BulkCreate(movements []entity.Movement) er
 }
 
 // GetLastMovementByCustomerID mock method
-func (mock *ClientMovementRepository) GetLastMovementByCustomerID(customerID int) (*entity.Movement, error) {
-	args := mock.Called(customerID)
+func (mock *ClientMovementRepository) GetLastMovementByCustomerID(customerID int, currency string) (*entity.Movement, error) {
+	args := mock.Called(customerID, currency)
 	result := args.Get(0)
 	if result != nil {
 		return result.(*entity.Movement), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
+
+// GetLastMovementsAtDate mock method
+func (mock *ClientMovementRepository) GetLastMovementsAtDate(customerID int, date time.Time) ([]entity.Movement, error) {
+	args := mock.Called(customerID, date)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetTotalsByCustomerID mock method
+func (mock *ClientMovementRepository) GetTotalsByCustomerID(customerID int, from, to *time.Time) ([]dto.CurrencyTotals, error) {
+	args := mock.Called(customerID, from, to)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]dto.CurrencyTotals), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindExistingExternalRefs mock method
+func (mock *ClientMovementRepository) FindExistingExternalRefs(customerID int, source string, externalRefs []string) ([]string, error) {
+	args := mock.Called(customerID, source, externalRefs)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteByImportBatchID mock method
+func (mock *ClientMovementRepository) DeleteByImportBatchID(importBatchID int) error {
+	args := mock.Called(importBatchID)
+	return args.Error(0)
+}
+
+// GetLastMovementBeforeDate mock method
+func (mock *ClientMovementRepository) GetLastMovementBeforeDate(customerID int, currency string, date time.Time) (*entity.Movement, error) {
+	args := mock.Called(customerID, currency, date)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindFirstOverdrawn mock method
+func (mock *ClientMovementRepository) FindFirstOverdrawn(importBatchID int) ([]entity.Movement, error) {
+	args := mock.Called(importBatchID)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindFirstOverCreditLimit mock method
+func (mock *ClientMovementRepository) FindFirstOverCreditLimit(customerID int, importBatchID int, creditLimit money.Amount) (*entity.Overdraft, error) {
+	args := mock.Called(customerID, importBatchID, creditLimit)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.Overdraft), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// UpdateAvailableFromDate mock method
+func (mock *ClientMovementRepository) UpdateAvailableFromDate(customerID int, currency string, date time.Time, opening money.Amount) error {
+	args := mock.Called(customerID, currency, date, opening)
+	return args.Error(0)
+}
+
+// FindFirstDatesByImportBatchID mock method
+func (mock *ClientMovementRepository) FindFirstDatesByImportBatchID(importBatchID int) (map[string]time.Time, error) {
+	args := mock.Called(importBatchID)
+	result := args.Get(0)
+	if result != nil {
+		return result.(map[string]time.Time), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindCategorizable mock method
+func (mock *ClientMovementRepository) FindCategorizable(customerID int, afterID int, limit int) ([]entity.Movement, error) {
+	args := mock.Called(customerID, afterID, limit)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// UpdateCategory mock method
+func (mock *ClientMovementRepository) UpdateCategory(movementID int, category string, categoryRuleID *int) error {
+	args := mock.Called(movementID, category, categoryRuleID)
+	return args.Error(0)
+}
+
+// FindByCustomerID mock method
+func (mock *ClientMovementRepository) FindByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.Pagination) ([]entity.Movement, error) {
+	args := mock.Called(customerID, filter, pagination)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindPageByCustomerID mock method
+func (mock *ClientMovementRepository) FindPageByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.CursorPagination) ([]entity.Movement, error) {
+	args := mock.Called(customerID, filter, pagination)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"io"
+	"stori-service/src/environments/common/resources/entity"
 	"stori-service/src/libs/dto"
 
 	"github.com/stretchr/testify/mock"
@@ -11,11 +13,61 @@ type ClientMovementService struct {
 }
 
 // ProcessFile mock method
-func (c *ClientMovementService) ProcessFile(customerID int) (*dto.MovementList, error) {
-	args := c.Called(customerID)
+func (c *ClientMovementService) ProcessFile(customerID int, options dto.ImportOptions) (*dto.MovementList, error) {
+	args := c.Called(customerID, options)
 	result := args.Get(0)
 	if result != nil {
 		return result.(*dto.MovementList), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
+
+// ProcessUpload mock method
+func (c *ClientMovementService) ProcessUpload(customerID int, file io.ReadSeeker, options dto.ImportOptions) (*dto.MovementList, error) {
+	args := c.Called(customerID, file, options)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*dto.MovementList), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindImportedFile mock method
+func (c *ClientMovementService) FindImportedFile(customerID int, options dto.ImportOptions) (*entity.ImportBatch, error) {
+	args := c.Called(customerID, options)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportBatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindImportedUpload mock method
+func (c *ClientMovementService) FindImportedUpload(customerID int, file io.ReadSeeker) (*entity.ImportBatch, error) {
+	args := c.Called(customerID, file)
+	result := args.Get(0)
+	if result != nil {
+		return result.(*entity.ImportBatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindByCustomerID mock method
+func (c *ClientMovementService) FindByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.Pagination) ([]entity.Movement, error) {
+	args := c.Called(customerID, filter, pagination)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// FindPageByCustomerID mock method
+func (c *ClientMovementService) FindPageByCustomerID(customerID int, filter dto.MovementFilter, pagination *dto.CursorPagination) ([]entity.Movement, error) {
+	args := c.Called(customerID, filter, pagination)
+	result := args.Get(0)
+	if result != nil {
+		return result.([]entity.Movement), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
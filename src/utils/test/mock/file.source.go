@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"io"
+	"strings"
+
+	"github.com/stretchr/testify/mock"
+)
+
+/*
+FileSource is a IFileSource mock
+*/
+type FileSource struct {
+	mock.Mock
+}
+
+// Open mock method
+func (mock *FileSource) Open(name string) (io.ReadSeekCloser, error) {
+	args := mock.Called(name)
+	result := args.Get(0)
+	if result != nil {
+		return result.(io.ReadSeekCloser), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+/*
+File is an in memory file to be returned by the FileSource mock
+*/
+type File struct {
+	*strings.Reader
+}
+
+/*
+NewFile returns a File with the content
+*/
+func NewFile(content string) *File {
+	return &File{strings.NewReader(content)}
+}
+
+// Close does nothing, the content stays in memory
+func (f *File) Close() error {
+	return nil
+}
@@ -3,6 +3,7 @@ package mock
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -49,3 +50,25 @@ func MHTTPHandle(
 	res, _ := ts.Client().Do(req)
 	return res
 }
+
+/*
+MHTTPHandleRawBody generates a mock-server to serve func to test, sending the body as is
+with the given content type
+*/
+func MHTTPHandleRawBody(
+	method string,
+	path string,
+	f func(http.ResponseWriter, *http.Request),
+	params string,
+	contentType string,
+	body io.Reader,
+) *http.Response {
+	r := mux.NewRouter()
+	r.HandleFunc(path, f).Methods(method)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+	req, _ := http.NewRequest(method, ts.URL+"/"+params, body)
+	req.Header.Set("Content-Type", contentType)
+	res, _ := ts.Client().Do(req)
+	return res
+}